@@ -0,0 +1,111 @@
+package easings
+
+import "math"
+
+// CubicBezierEasing returns the easing described by the CSS timing function cubic-bezier(x1, y1, x2, y2).
+// The curve starts at (0, 0) and ends at (1, 1) with the control points (x1, y1) and (x2, y2).
+// x1 and x2 are clamped to [0, 1] like in CSS, so that there is exactly one y for every x.
+func CubicBezierEasing(x1, y1, x2, y2 float32) Func {
+	c, linear := newCubicBezier(x1, y1, x2, y2)
+	if linear {
+		return Linear
+	}
+	return func(x float32) float32 {
+		if x <= 0 || x >= 1 {
+			return x
+		}
+		return c.y(c.solve(x, x))
+	}
+}
+
+// CubicBezierEasingTable is like CubicBezierEasing, but precomputes a table of size samples
+// to start the search for each x from. It's faster for curves that are evaluated many times.
+func CubicBezierEasingTable(x1, y1, x2, y2 float32, size int) Func {
+	c, linear := newCubicBezier(x1, y1, x2, y2)
+	if linear {
+		return Linear
+	}
+	size = max(size, 2)
+	// table[i] is the curve parameter where x = i / (size - 1)
+	table := make([]float32, size)
+	step := 1 / float32(size-1)
+	for i := range table {
+		x := float32(i) * step
+		table[i] = c.solve(x, x)
+	}
+	table[size-1] = 1
+	return func(x float32) float32 {
+		if x <= 0 || x >= 1 {
+			return x
+		}
+		pos := x / step
+		i := min(int(pos), size-2)
+		frac := pos - float32(i)
+		guess := table[i] + (table[i+1]-table[i])*frac
+		return c.y(c.solve(x, guess))
+	}
+}
+
+// cubicBezier holds the polynomial coefficients of both axes, i.e. x(t) = ((ax*t + bx)*t + cx)*t
+type cubicBezier struct {
+	ax, bx, cx float32
+	ay, by, cy float32
+}
+
+func newCubicBezier(x1, y1, x2, y2 float32) (c cubicBezier, linear bool) {
+	x1 = min(max(x1, 0), 1)
+	x2 = min(max(x2, 0), 1)
+	c.cx = 3 * x1
+	c.bx = 3*(x2-x1) - c.cx
+	c.ax = 1 - c.cx - c.bx
+	c.cy = 3 * y1
+	c.by = 3*(y2-y1) - c.cy
+	c.ay = 1 - c.cy - c.by
+	return c, x1 == y1 && x2 == y2
+}
+
+func (c cubicBezier) x(t float32) float32 {
+	return ((c.ax*t+c.bx)*t + c.cx) * t
+}
+
+func (c cubicBezier) y(t float32) float32 {
+	return ((c.ay*t+c.by)*t + c.cy) * t
+}
+
+func (c cubicBezier) dx(t float32) float32 {
+	return (3*c.ax*t+2*c.bx)*t + c.cx
+}
+
+// solve finds t so that x(t) = x. Newton's method converges quickly from a good guess,
+// but can fail where the curve is flat, in which case it falls back to bisection.
+func (c cubicBezier) solve(x, guess float32) float32 {
+	const epsilon = 1e-6
+	t := guess
+	for i := 0; i < 8; i++ {
+		err := c.x(t) - x
+		if float32(math.Abs(float64(err))) < epsilon {
+			return t
+		}
+		d := c.dx(t)
+		if float32(math.Abs(float64(d))) < 1e-6 {
+			break
+		}
+		t -= err / d
+	}
+
+	low, high := float32(0), float32(1)
+	t = x
+	for i := 0; i < 32; i++ {
+		err := c.x(t) - x
+		if float32(math.Abs(float64(err))) < epsilon {
+			break
+		}
+		if err > 0 {
+			high = t
+		} else {
+			low = t
+		}
+		t = (low + high) / 2
+	}
+	return t
+}
@@ -0,0 +1,100 @@
+package easings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCubicBezierEasing(t *testing.T) {
+	ease := CubicBezierEasing(0.25, 0.1, 0.25, 1)
+	assert.Equal(t, float32(0), ease(0))
+	assert.Equal(t, float32(1), ease(1))
+	assert.InDelta(t, 0.8024, ease(0.5), 0.0001)
+	assert.InDelta(t, 0.0947, ease(0.1), 0.0001)
+
+	// equivalent to linear
+	assert.Equal(t, float32(0.3), CubicBezierEasing(0.2, 0.2, 0.8, 0.8)(0.3))
+
+	// overshooting y
+	back := CubicBezierEasing(0.3, -0.5, 0.7, 1.5)
+	assert.Less(t, back(0.1), float32(0))
+	assert.Greater(t, back(0.9), float32(1))
+
+	// x(t) = 1 - (1-t)^3 and y(t) = t^3, which is flat at the end
+	flat := CubicBezierEasing(1, 0, 1, 0)
+	assert.InDelta(t, 0.008779, flat(0.5), 0.0001)
+	assert.InDelta(t, 0.482929, flat(0.99), 0.001)
+}
+
+func TestCubicBezierEasingTable(t *testing.T) {
+	params := [][4]float32{{0.25, 0.1, 0.25, 1}, {0.42, 0, 1, 1}, {0.3, -0.5, 0.7, 1.5}, {0.9, 0, 0.1, 1}}
+	for _, p := range params {
+		ease := CubicBezierEasing(p[0], p[1], p[2], p[3])
+		table := CubicBezierEasingTable(p[0], p[1], p[2], p[3], 11)
+		for x := float32(0); x <= 1; x += 0.01 {
+			assert.InDelta(t, ease(x), table(x), 0.0001)
+		}
+	}
+}
+
+func TestStepsEasing(t *testing.T) {
+	xs := []float32{0, 0.1, 0.25, 0.3, 0.5, 0.8, 1}
+	tests := []struct {
+		position StepPosition
+		expected []float32
+	}{
+		{JumpEnd, []float32{0, 0, 0.25, 0.25, 0.5, 0.75, 1}},
+		{JumpStart, []float32{0.25, 0.25, 0.5, 0.5, 0.75, 1, 1}},
+		{JumpNone, []float32{0, 0, 1. / 3, 1. / 3, 2. / 3, 1, 1}},
+		{JumpBoth, []float32{0.2, 0.2, 0.4, 0.4, 0.6, 0.8, 1}},
+	}
+	for _, tt := range tests {
+		ease := StepsEasing(4, tt.position)
+		for i, x := range xs {
+			assert.InDelta(t, tt.expected[i], ease(x), 0.0001, "position %v x %v", tt.position, x)
+		}
+	}
+}
+
+func TestParseCSS(t *testing.T) {
+	f, err := ParseCSS("ease-in-out")
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.5, f(0.5), 0.0001)
+
+	f, err = ParseCSS(" cubic-bezier(0.25, 0.1,0.25 , 1.0)")
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.8024, f(0.5), 0.0001)
+
+	f, err = ParseCSS("steps(2, start)")
+	assert.NoError(t, err)
+	assert.Equal(t, float32(0.5), f(0.1))
+
+	f, err = ParseCSS("steps(5)")
+	assert.NoError(t, err)
+	assert.Equal(t, float32(0.2), f(0.3))
+
+	for _, s := range []string{"ease-sideways", "cubic-bezier(1, 2, 3)", "cubic-bezier(2, 0, 0, 1)",
+		"steps(0)", "steps(1, jump-none)", "steps(3, middle)", "steps(3"} {
+		_, err = ParseCSS(s)
+		assert.Error(t, err, s)
+	}
+
+	named, err := NewNamed("cubic-bezier(0.42, 0, 1, 1)")
+	assert.NoError(t, err)
+	assert.Equal(t, "cubic-bezier(0.42, 0, 1, 1)", named.Name)
+}
+
+func BenchmarkCubicBezierEasing(b *testing.B) {
+	ease := CubicBezierEasing(0.25, 0.1, 0.25, 1)
+	for i := 0; i < b.N; i++ {
+		ease(float32(i%100) / 100)
+	}
+}
+
+func BenchmarkCubicBezierEasingTable(b *testing.B) {
+	ease := CubicBezierEasingTable(0.25, 0.1, 0.25, 1, 11)
+	for i := 0; i < b.N; i++ {
+		ease(float32(i%100) / 100)
+	}
+}
@@ -0,0 +1,84 @@
+package easings
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseCSS parses a CSS easing function such as "ease-in", "cubic-bezier(0.1, 0.7, 1.0, 0.1)" or "steps(4, jump-end)".
+func ParseCSS(s string) (Func, error) {
+	s = strings.TrimSpace(s)
+	switch s {
+	case "linear":
+		return Linear, nil
+	case "ease":
+		return CubicBezierEasing(0.25, 0.1, 0.25, 1), nil
+	case "ease-in":
+		return CubicBezierEasing(0.42, 0, 1, 1), nil
+	case "ease-out":
+		return CubicBezierEasing(0, 0, 0.58, 1), nil
+	case "ease-in-out":
+		return CubicBezierEasing(0.42, 0, 0.58, 1), nil
+	case "step-start":
+		return StepsEasing(1, JumpStart), nil
+	case "step-end":
+		return StepsEasing(1, JumpEnd), nil
+	}
+
+	name, argString, ok := strings.Cut(s, "(")
+	if !ok || !strings.HasSuffix(argString, ")") {
+		return nil, fmt.Errorf("Unknown CSS easing: %v", s)
+	}
+	args := strings.Split(strings.TrimSuffix(argString, ")"), ",")
+	for i := range args {
+		args[i] = strings.TrimSpace(args[i])
+	}
+
+	switch strings.TrimSpace(name) {
+	case "cubic-bezier":
+		if len(args) != 4 {
+			return nil, fmt.Errorf("cubic-bezier requires 4 parameters: %v", s)
+		}
+		var p [4]float32
+		for i, arg := range args {
+			v, err := strconv.ParseFloat(arg, 32)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid cubic-bezier parameter %v: %v", arg, err)
+			}
+			p[i] = float32(v)
+		}
+		if p[0] < 0 || p[0] > 1 || p[2] < 0 || p[2] > 1 {
+			return nil, fmt.Errorf("cubic-bezier x values must be in [0, 1]: %v", s)
+		}
+		return CubicBezierEasing(p[0], p[1], p[2], p[3]), nil
+	case "steps":
+		if len(args) < 1 || len(args) > 2 {
+			return nil, fmt.Errorf("steps requires 1 or 2 parameters: %v", s)
+		}
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("Invalid number of steps: %v", args[0])
+		}
+		position := JumpEnd
+		if len(args) == 2 {
+			switch args[1] {
+			case "jump-end", "end":
+				position = JumpEnd
+			case "jump-start", "start":
+				position = JumpStart
+			case "jump-none":
+				position = JumpNone
+				if n < 2 {
+					return nil, fmt.Errorf("steps with jump-none requires at least 2 steps: %v", s)
+				}
+			case "jump-both":
+				position = JumpBoth
+			default:
+				return nil, fmt.Errorf("Unknown step position: %v", args[1])
+			}
+		}
+		return StepsEasing(n, position), nil
+	}
+	return nil, fmt.Errorf("Unknown CSS easing: %v", s)
+}
@@ -4,6 +4,18 @@ import (
 	"math"
 )
 
+// Func maps progress in [0, 1] to eased progress. All easings return 0 for 0 and 1 for 1,
+// but back, elastic and bounce easings may go outside of [0, 1] in between.
+type Func func(x float32) float32
+
+func Linear(x float32) float32 {
+	return x
+}
+
+func EaseInQuad(x float32) float32 {
+	return x * x
+}
+
 func EaseOutQuad(x float32) float32 {
 	return 1 - (1-x)*(1-x)
 }
@@ -23,6 +35,160 @@ func SmoothStep(x float32) float32 {
 	return x * x * (3 - 2*x)
 }
 
+func EaseInSine(x float32) float32 {
+	return 1 - float32(math.Cos(float64(x)*math.Pi/2))
+}
+
+func EaseOutSine(x float32) float32 {
+	return float32(math.Sin(float64(x) * math.Pi / 2))
+}
+
+func EaseInOutSine(x float32) float32 {
+	return -(float32(math.Cos(math.Pi*float64(x))) - 1) / 2
+}
+
+func EaseInCubic(x float32) float32 {
+	return x * x * x
+}
+
+func EaseOutCubic(x float32) float32 {
+	y := 1 - x
+	return 1 - y*y*y
+}
+
+func EaseInOutCubic(x float32) float32 {
+	if x < 0.5 {
+		return 4 * x * x * x
+	}
+	y := -2*x + 2
+	return 1 - y*y*y/2
+}
+
+func EaseInQuart(x float32) float32 {
+	return x * x * x * x
+}
+
+func EaseOutQuart(x float32) float32 {
+	y := 1 - x
+	return 1 - y*y*y*y
+}
+
+func EaseInOutQuart(x float32) float32 {
+	if x < 0.5 {
+		return 8 * x * x * x * x
+	}
+	y := -2*x + 2
+	return 1 - y*y*y*y/2
+}
+
+func EaseInQuint(x float32) float32 {
+	return x * x * x * x * x
+}
+
+func EaseOutQuint(x float32) float32 {
+	y := 1 - x
+	return 1 - y*y*y*y*y
+}
+
+func EaseInOutQuint(x float32) float32 {
+	if x < 0.5 {
+		return 16 * x * x * x * x * x
+	}
+	y := -2*x + 2
+	return 1 - y*y*y*y*y/2
+}
+
+func EaseInExpo(x float32) float32 {
+	if x == 0 {
+		return 0
+	}
+	return pow2(10*x - 10)
+}
+
+func EaseOutExpo(x float32) float32 {
+	if x == 1 {
+		return 1
+	}
+	return 1 - pow2(-10*x)
+}
+
+func EaseInOutExpo(x float32) float32 {
+	if x == 0 || x == 1 {
+		return x
+	}
+	if x < 0.5 {
+		return pow2(20*x-10) / 2
+	}
+	return (2 - pow2(-20*x+10)) / 2
+}
+
+func EaseInCirc(x float32) float32 {
+	return 1 - sqrt(1-x*x)
+}
+
+func EaseOutCirc(x float32) float32 {
+	return sqrt(1 - (x-1)*(x-1))
+}
+
+func EaseInOutCirc(x float32) float32 {
+	if x < 0.5 {
+		return (1 - sqrt(1-4*x*x)) / 2
+	}
+	y := -2*x + 2
+	return (sqrt(1-y*y) + 1) / 2
+}
+
+// backOvershoot is how far the back easings go past their range, about 10%
+const backOvershoot = 1.70158
+
+func EaseInBack(x float32) float32 {
+	const c3 = backOvershoot + 1
+	return c3*x*x*x - backOvershoot*x*x
+}
+
+func EaseOutBack(x float32) float32 {
+	const c3 = backOvershoot + 1
+	y := x - 1
+	return 1 + c3*y*y*y + backOvershoot*y*y
+}
+
+func EaseInOutBack(x float32) float32 {
+	const c2 = backOvershoot * 1.525
+	if x < 0.5 {
+		y := 2 * x
+		return y * y * ((c2+1)*y - c2) / 2
+	}
+	y := 2*x - 2
+	return (y*y*((c2+1)*y+c2) + 2) / 2
+}
+
+func EaseInElastic(x float32) float32 {
+	const c4 = 2 * math.Pi / 3
+	if x == 0 || x == 1 {
+		return x
+	}
+	return -pow2(10*x-10) * sin((10*x-10.75)*c4)
+}
+
+func EaseOutElastic(x float32) float32 {
+	const c4 = 2 * math.Pi / 3
+	if x == 0 || x == 1 {
+		return x
+	}
+	return pow2(-10*x)*sin((10*x-0.75)*c4) + 1
+}
+
+func EaseInOutElastic(x float32) float32 {
+	const c5 = 2 * math.Pi / 4.5
+	if x == 0 || x == 1 {
+		return x
+	}
+	if x < 0.5 {
+		return -pow2(20*x-10) * sin((20*x-11.125)*c5) / 2
+	}
+	return pow2(-20*x+10)*sin((20*x-11.125)*c5)/2 + 1
+}
+
 func EaseOutBounce(x float32) float32 {
 	const n1 = 7.5625
 	const d1 = 2.75
@@ -44,3 +210,22 @@ func EaseOutBounce(x float32) float32 {
 func EaseInBounce(x float32) float32 {
 	return 1 - EaseOutBounce(1-x)
 }
+
+func EaseInOutBounce(x float32) float32 {
+	if x < 0.5 {
+		return (1 - EaseOutBounce(1-2*x)) / 2
+	}
+	return (1 + EaseOutBounce(2*x-1)) / 2
+}
+
+func pow2(x float32) float32 {
+	return float32(math.Exp2(float64(x)))
+}
+
+func sin(x float32) float32 {
+	return float32(math.Sin(float64(x)))
+}
+
+func sqrt(x float32) float32 {
+	return float32(math.Sqrt(float64(max(x, 0))))
+}
@@ -0,0 +1,83 @@
+package easings
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEndpoints(t *testing.T) {
+	for _, name := range Names() {
+		f, _ := Lookup(name)
+		assert.InDelta(t, 0, f(0), 0.0001, name)
+		assert.InDelta(t, 1, f(1), 0.0001, name)
+	}
+}
+
+func TestInOutSymmetry(t *testing.T) {
+	for _, name := range Names() {
+		if !strings.HasPrefix(name, "easeInOut") {
+			continue
+		}
+		f, _ := Lookup(name)
+		assert.InDelta(t, 0.5, f(0.5), 0.0001, name)
+		for _, x := range []float32{0.1, 0.25, 0.4} {
+			assert.InDelta(t, 1, f(x)+f(1-x), 0.0001, name)
+		}
+	}
+}
+
+func TestInIsMirroredOut(t *testing.T) {
+	for _, name := range Names() {
+		if !strings.HasPrefix(name, "easeIn") || strings.HasPrefix(name, "easeInOut") {
+			continue
+		}
+		in, _ := Lookup(name)
+		out, ok := Lookup("easeOut" + strings.TrimPrefix(name, "easeIn"))
+		assert.True(t, ok, name)
+		for _, x := range []float32{0.1, 0.3, 0.7} {
+			assert.InDelta(t, 1-out(1-x), in(x), 0.0001, name)
+		}
+	}
+}
+
+func TestValues(t *testing.T) {
+	assert.InDelta(t, 0.125, EaseInCubic(0.5), 0.0001)
+	assert.InDelta(t, 0.7071, EaseOutSine(0.5), 0.0001)
+	assert.InDelta(t, 0.0313, EaseInExpo(0.5), 0.0001)
+	// back easings overshoot
+	assert.Less(t, EaseInBack(0.2), float32(0))
+	assert.Greater(t, EaseOutBack(0.8), float32(1))
+	assert.Greater(t, EaseOutElastic(0.1), float32(1))
+}
+
+func TestNamedJSON(t *testing.T) {
+	var data struct {
+		In  Named `json:"in"`
+		Out Named `json:"out"`
+	}
+	assert.NoError(t, json.Unmarshal([]byte(`{"in": "easeInQuad", "out": ""}`), &data))
+	assert.Equal(t, "easeInQuad", data.In.Name)
+	assert.Equal(t, float32(0.25), data.In.Ease(0.5))
+	assert.Equal(t, float32(0.3), data.Out.Ease(0.3))
+
+	encoded, err := json.Marshal(data)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"in":"easeInQuad","out":""}`, string(encoded))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"in": "easeSideways"}`), &data))
+}
+
+func TestRegister(t *testing.T) {
+	_, ok := Lookup("square")
+	assert.False(t, ok)
+	Register("square", func(x float32) float32 { return x * x })
+	defer delete(registry, "square")
+
+	named, err := NewNamed("square")
+	assert.NoError(t, err)
+	assert.Equal(t, float32(0.25), named.Ease(0.5))
+	assert.Contains(t, Names(), "square")
+}
@@ -0,0 +1,113 @@
+package easings
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+var registry = map[string]Func{
+	"linear":           Linear,
+	"smoothStep":       SmoothStep,
+	"easeInQuad":       EaseInQuad,
+	"easeOutQuad":      EaseOutQuad,
+	"easeInOutQuad":    EaseInOutQuad,
+	"easeInSine":       EaseInSine,
+	"easeOutSine":      EaseOutSine,
+	"easeInOutSine":    EaseInOutSine,
+	"easeInCubic":      EaseInCubic,
+	"easeOutCubic":     EaseOutCubic,
+	"easeInOutCubic":   EaseInOutCubic,
+	"easeInQuart":      EaseInQuart,
+	"easeOutQuart":     EaseOutQuart,
+	"easeInOutQuart":   EaseInOutQuart,
+	"easeInQuint":      EaseInQuint,
+	"easeOutQuint":     EaseOutQuint,
+	"easeInOutQuint":   EaseInOutQuint,
+	"easeInExpo":       EaseInExpo,
+	"easeOutExpo":      EaseOutExpo,
+	"easeInOutExpo":    EaseInOutExpo,
+	"easeInCirc":       EaseInCirc,
+	"easeOutCirc":      EaseOutCirc,
+	"easeInOutCirc":    EaseInOutCirc,
+	"easeInBack":       EaseInBack,
+	"easeOutBack":      EaseOutBack,
+	"easeInOutBack":    EaseInOutBack,
+	"easeInElastic":    EaseInElastic,
+	"easeOutElastic":   EaseOutElastic,
+	"easeInOutElastic": EaseInOutElastic,
+	"easeInBounce":     EaseInBounce,
+	"easeOutBounce":    EaseOutBounce,
+	"easeInOutBounce":  EaseInOutBounce,
+}
+
+// Register makes f available under name, replacing any easing already registered with that name.
+// The registry isn't synchronized, so register custom easings during initialization.
+func Register(name string, f Func) {
+	registry[name] = f
+}
+
+// Lookup returns the easing registered with name. The built-in easings use the names from easings.net,
+// such as "linear", "easeInQuad" and "easeInOutBounce".
+func Lookup(name string) (Func, bool) {
+	f, ok := registry[name]
+	return f, ok
+}
+
+// Names returns the names of all registered easings in sorted order
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Named is an easing that is stored as its registered name in JSON, e.g. "easeOutBack".
+// The zero value eases linearly.
+type Named struct {
+	Name string
+	Func Func
+}
+
+// NewNamed looks up the easing registered with name. Names that aren't registered are parsed with ParseCSS,
+// so data files can also use CSS timing functions such as "cubic-bezier(0.3, 0, 0.2, 1)".
+func NewNamed(name string) (Named, error) {
+	f, ok := Lookup(name)
+	if !ok {
+		var err error
+		if f, err = ParseCSS(name); err != nil {
+			return Named{}, fmt.Errorf("Unknown easing: %v", name)
+		}
+	}
+	return Named{Name: name, Func: f}, nil
+}
+
+func (n Named) Ease(x float32) float32 {
+	if n.Func == nil {
+		return x
+	}
+	return n.Func(x)
+}
+
+func (n Named) MarshalJSON() ([]byte, error) {
+	return json.Marshal(n.Name)
+}
+
+func (n *Named) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	if name == "" {
+		*n = Named{}
+		return nil
+	}
+	named, err := NewNamed(name)
+	if err != nil {
+		return err
+	}
+	*n = named
+	return nil
+}
@@ -0,0 +1,45 @@
+package easings
+
+import "math"
+
+// StepPosition decides where the jumps of a steps easing happen, like the second parameter of CSS steps()
+type StepPosition int
+
+const (
+	// JumpEnd holds each value until the end of its interval, so the output starts at 0 and reaches 1 only at the end
+	JumpEnd StepPosition = iota
+	// JumpStart jumps at the start of each interval, so the output starts at 1/n right away
+	JumpStart
+	// JumpNone holds both 0 and 1 for an interval each, with n-2 steps in between
+	JumpNone
+	// JumpBoth jumps both at the start and at the end, so neither 0 nor 1 are held
+	JumpBoth
+)
+
+// StepsEasing returns the easing described by the CSS timing function steps(n, position),
+// which divides the animation into n equal intervals with a constant output each.
+// n is at least 1, or 2 for JumpNone.
+func StepsEasing(n int, position StepPosition) Func {
+	n = max(n, 1)
+	jumps := n
+	switch position {
+	case JumpBoth:
+		jumps = n + 1
+	case JumpNone:
+		n = max(n, 2)
+		jumps = n - 1
+	}
+	return func(x float32) float32 {
+		step := int(math.Floor(float64(x * float32(n))))
+		if position == JumpStart || position == JumpBoth {
+			step++
+		}
+		if x >= 0 && step < 0 {
+			step = 0
+		}
+		if x <= 1 && step > jumps {
+			step = jumps
+		}
+		return float32(step) / float32(jumps)
+	}
+}
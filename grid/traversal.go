@@ -0,0 +1,143 @@
+// Package grid contains utilities for walking tile grids, such as the tiles along a line.
+// All functions are iterators, so a loop can stop as soon as it hits a blocking tile:
+//
+//	for tile := range grid.Traverse(from, to, tileSize) {
+//		if isSolid(tile) {
+//			return false
+//		}
+//	}
+package grid
+
+import (
+	"iter"
+	"math"
+
+	"github.com/Lundis/go-gmath/vec2"
+)
+
+// Line returns the tiles of a Bresenham line from a to b, including both ends.
+// Consecutive tiles may be diagonal neighbours, so the line can slip between two tiles touching at a corner.
+func Line(a, b vec2.I) iter.Seq[vec2.I] {
+	return func(yield func(vec2.I) bool) {
+		dx, sx := absSign(b.X - a.X)
+		dy, sy := absSign(b.Y - a.Y)
+		dy = -dy
+		err := dx + dy
+		p := a
+		for {
+			if !yield(p) || p == b {
+				return
+			}
+			e2 := 2 * err
+			if e2 >= dy {
+				err += dy
+				p.X += sx
+			}
+			if e2 <= dx {
+				err += dx
+				p.Y += sy
+			}
+		}
+	}
+}
+
+// Supercover returns every tile that the line between the centers of tiles a and b passes through, including both ends.
+// Unlike Line it never slips between two tiles touching at a corner. When the line passes exactly through a corner,
+// both tiles beside it are included.
+func Supercover(a, b vec2.I) iter.Seq[vec2.I] {
+	return func(yield func(vec2.I) bool) {
+		nx, sx := absSign(b.X - a.X)
+		ny, sy := absSign(b.Y - a.Y)
+		p := a
+		if !yield(p) {
+			return
+		}
+		for ix, iy := int64(0), int64(0); ix < int64(nx) || iy < int64(ny); {
+			// compares (0.5 + ix) / nx with (0.5 + iy) / ny, i.e. which tile border the line crosses next
+			decision := (1+2*ix)*int64(ny) - (1+2*iy)*int64(nx)
+			switch {
+			case decision == 0:
+				if !yield(vec2.I{X: p.X + sx, Y: p.Y}) || !yield(vec2.I{X: p.X, Y: p.Y + sy}) {
+					return
+				}
+				p.X += sx
+				p.Y += sy
+				ix++
+				iy++
+			case decision < 0:
+				p.X += sx
+				ix++
+			default:
+				p.Y += sy
+				iy++
+			}
+			if !yield(p) {
+				return
+			}
+		}
+	}
+}
+
+// Traverse returns every tile that the line segment from start to end passes through, in order, using the
+// Amanatides–Woo DDA algorithm. start and end are world coordinates, and tile (0, 0) covers [0, tileSize).
+// Consecutive tiles are always edge neighbours.
+func Traverse(start, end vec2.F, tileSize vec2.F) iter.Seq[vec2.I] {
+	return func(yield func(vec2.I) bool) {
+		tile := tileOf(start, tileSize)
+		last := tileOf(end, tileSize)
+		d := end.Sub(start)
+
+		nx, stepX := absSign(last.X - tile.X)
+		ny, stepY := absSign(last.Y - tile.Y)
+		tMaxX, tDeltaX := crossings(start.X, d.X, tileSize.X, tile.X, stepX)
+		tMaxY, tDeltaY := crossings(start.Y, d.Y, tileSize.Y, tile.Y, stepY)
+
+		if !yield(tile) {
+			return
+		}
+		// taking exactly the required number of steps guarantees that we end up at the last tile despite rounding errors
+		for nx > 0 || ny > 0 {
+			if ny == 0 || (nx > 0 && tMaxX < tMaxY) {
+				tile.X += stepX
+				tMaxX += tDeltaX
+				nx--
+			} else {
+				tile.Y += stepY
+				tMaxY += tDeltaY
+				ny--
+			}
+			if !yield(tile) {
+				return
+			}
+		}
+	}
+}
+
+// crossings returns the line parameter of the first tile border crossing along one axis, and the distance between crossings
+func crossings(start, d, size float32, tile, step int32) (tMax, tDelta float32) {
+	if step == 0 || d == 0 {
+		return math.MaxFloat32, math.MaxFloat32
+	}
+	border := float32(tile) * size
+	if step > 0 {
+		border += size
+	}
+	return (border - start) / d, size / float32(math.Abs(float64(d)))
+}
+
+func tileOf(p, tileSize vec2.F) vec2.I {
+	return vec2.I{
+		X: int32(math.Floor(float64(p.X / tileSize.X))),
+		Y: int32(math.Floor(float64(p.Y / tileSize.Y))),
+	}
+}
+
+func absSign(x int32) (abs, sign int32) {
+	switch {
+	case x > 0:
+		return x, 1
+	case x < 0:
+		return -x, -1
+	}
+	return 0, 0
+}
@@ -0,0 +1,93 @@
+package grid
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/Lundis/go-gmath/vec2"
+	"github.com/stretchr/testify/assert"
+)
+
+func pt(x, y int32) vec2.I {
+	return vec2.I{X: x, Y: y}
+}
+
+func isEdgeConnected(tiles []vec2.I) bool {
+	for i := 1; i < len(tiles); i++ {
+		d := tiles[i].Sub(tiles[i-1]).Abs()
+		if d.X+d.Y != 1 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestLine(t *testing.T) {
+	tiles := slices.Collect(Line(vec2.I{X: 0, Y: 0}, vec2.I{X: 4, Y: 2}))
+	assert.Equal(t, []vec2.I{pt(0, 0), pt(1, 1), pt(2, 1), pt(3, 2), pt(4, 2)}, tiles)
+
+	tiles = slices.Collect(Line(vec2.I{X: 2, Y: 3}, vec2.I{X: 2, Y: -1}))
+	assert.Equal(t, []vec2.I{pt(2, 3), pt(2, 2), pt(2, 1), pt(2, 0), pt(2, -1)}, tiles)
+
+	tiles = slices.Collect(Line(vec2.I{X: -1, Y: -1}, vec2.I{X: -1, Y: -1}))
+	assert.Equal(t, []vec2.I{pt(-1, -1)}, tiles)
+
+	// reversed lines cover the same number of tiles
+	for _, b := range []vec2.I{pt(7, 3), pt(-5, 2), pt(3, -8), pt(-6, -6)} {
+		forward := slices.Collect(Line(vec2.I{}, b))
+		backward := slices.Collect(Line(b, vec2.I{}))
+		assert.Len(t, backward, len(forward))
+		assert.Equal(t, b, forward[len(forward)-1])
+	}
+}
+
+func TestSupercover(t *testing.T) {
+	tiles := slices.Collect(Supercover(vec2.I{X: 0, Y: 0}, vec2.I{X: 2, Y: 2}))
+	assert.Equal(t, []vec2.I{pt(0, 0), pt(1, 0), pt(0, 1), pt(1, 1), pt(2, 1), pt(1, 2), pt(2, 2)}, tiles)
+
+	// passes exactly through the corner at (-1.5, 0.5)
+	tiles = slices.Collect(Supercover(vec2.I{X: 0, Y: 0}, vec2.I{X: -3, Y: 1}))
+	assert.Equal(t, []vec2.I{pt(0, 0), pt(-1, 0), pt(-2, 0), pt(-1, 1), pt(-2, 1), pt(-3, 1)}, tiles)
+
+	tiles = slices.Collect(Supercover(vec2.I{X: 0, Y: 0}, vec2.I{X: -4, Y: 1}))
+	assert.Equal(t, []vec2.I{pt(0, 0), pt(-1, 0), pt(-2, 0), pt(-2, 1), pt(-3, 1), pt(-4, 1)}, tiles)
+	assert.True(t, isEdgeConnected(tiles))
+}
+
+func TestTraverse(t *testing.T) {
+	size := vec2.F{X: 1, Y: 1}
+	tiles := slices.Collect(Traverse(vec2.F{X: 0.5, Y: 0.5}, vec2.F{X: 3.5, Y: 1.7}, size))
+	assert.Equal(t, []vec2.I{pt(0, 0), pt(1, 0), pt(1, 1), pt(2, 1), pt(3, 1)}, tiles)
+
+	// negative coordinates round down
+	tiles = slices.Collect(Traverse(vec2.F{X: 0.5, Y: 0.5}, vec2.F{X: -1.5, Y: 0.5}, size))
+	assert.Equal(t, []vec2.I{pt(0, 0), pt(-1, 0), pt(-2, 0)}, tiles)
+
+	tiles = slices.Collect(Traverse(vec2.F{X: 5, Y: 5}, vec2.F{X: 5, Y: 5}, size))
+	assert.Equal(t, []vec2.I{pt(5, 5)}, tiles)
+
+	tiles = slices.Collect(Traverse(vec2.F{X: 10, Y: 70}, vec2.F{X: 90, Y: 10}, vec2.F{X: 32, Y: 32}))
+	assert.Equal(t, vec2.I{X: 0, Y: 2}, tiles[0])
+	assert.Equal(t, vec2.I{X: 2, Y: 0}, tiles[len(tiles)-1])
+	assert.True(t, isEdgeConnected(tiles))
+	assert.Len(t, tiles, 5)
+}
+
+func TestTraverseStopsEarly(t *testing.T) {
+	var visited []vec2.I
+	for tile := range Traverse(vec2.F{}, vec2.F{X: 100}, vec2.F{X: 1, Y: 1}) {
+		visited = append(visited, tile)
+		if tile.X == 3 {
+			break
+		}
+	}
+	assert.Len(t, visited, 4)
+}
+
+func BenchmarkTraverse(b *testing.B) {
+	size := vec2.F{X: 16, Y: 16}
+	for i := 0; i < b.N; i++ {
+		for range Traverse(vec2.F{X: 3, Y: 5}, vec2.F{X: 1000, Y: 700}, size) {
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package lerp
+
+import (
+	"slices"
+
+	"github.com/Lundis/go-gmath/vec2"
+)
+
+// Curve2 is a parametric curve over t in [0, 1], such as CubicBezier2, Path2, CatmullRom2 or BSpline2
+type Curve2 interface {
+	Evaluate(t float32) vec2.F
+	Derivative(t float32) vec2.F
+}
+
+// ArcLength maps between the parameter t of a curve or polyline and the distance along it,
+// so that things can move along it at constant speed.
+// Building the table allocates, but none of the queries do.
+type ArcLength struct {
+	// points[i] is the point at params[i], which is lengths[i] from the start
+	points  []vec2.F
+	params  []float32
+	lengths []float32
+	// curve is nil for polylines, which are evaluated exactly from points
+	curve Curve2
+}
+
+// NewArcLengthPolyline creates the table of a polyline. Its parameter t is spread evenly over the segments,
+// like for Path2. points is not copied and must not be modified while the table is used.
+func NewArcLengthPolyline(points []vec2.F) ArcLength {
+	a := ArcLength{
+		points:  points,
+		params:  make([]float32, len(points)),
+		lengths: make([]float32, len(points)),
+	}
+	for i := 1; i < len(points); i++ {
+		a.params[i] = float32(i) / float32(len(points)-1)
+		a.lengths[i] = a.lengths[i-1] + points[i].DistanceTo(points[i-1])
+	}
+	return a
+}
+
+// NewArcLengthCurve creates the table of a curve by sampling it at samples evenly spaced parameters.
+// More samples give more accurate distances.
+func NewArcLengthCurve(curve Curve2, samples int) ArcLength {
+	samples = max(samples, 2)
+	a := ArcLength{
+		points:  make([]vec2.F, samples),
+		params:  make([]float32, samples),
+		lengths: make([]float32, samples),
+		curve:   curve,
+	}
+	for i := range samples {
+		t := float32(i) / float32(samples-1)
+		a.params[i] = t
+		a.points[i] = curve.Evaluate(t)
+		if i > 0 {
+			a.lengths[i] = a.lengths[i-1] + a.points[i].DistanceTo(a.points[i-1])
+		}
+	}
+	return a
+}
+
+// Length returns the total length
+func (a ArcLength) Length() float32 {
+	if len(a.lengths) == 0 {
+		return 0
+	}
+	return a.lengths[len(a.lengths)-1]
+}
+
+// ParamAt returns the parameter t at the given distance from the start. distance is clamped to [0, Length].
+func (a ArcLength) ParamAt(distance float32) float32 {
+	i, ratio := a.find(a.lengths, distance)
+	if i < 0 {
+		return 0
+	}
+	return Lerp(a.params[i], a.params[i+1], ratio)
+}
+
+// DistanceAt returns the distance from the start at parameter t
+func (a ArcLength) DistanceAt(t float32) float32 {
+	i, ratio := a.find(a.params, t)
+	if i < 0 {
+		return 0
+	}
+	return Lerp(a.lengths[i], a.lengths[i+1], ratio)
+}
+
+// PointAt returns the point at the given distance from the start
+func (a ArcLength) PointAt(distance float32) vec2.F {
+	if a.curve != nil {
+		return a.curve.Evaluate(a.ParamAt(distance))
+	}
+	i, ratio := a.find(a.lengths, distance)
+	if i < 0 {
+		if len(a.points) == 0 {
+			return vec2.F{}
+		}
+		return a.points[0]
+	}
+	return Lerp2(a.points[i], a.points[i+1], ratio)
+}
+
+// TangentAt returns the normalized direction of travel at the given distance from the start
+func (a ArcLength) TangentAt(distance float32) vec2.F {
+	if a.curve != nil {
+		return a.curve.Derivative(a.ParamAt(distance)).Normalized()
+	}
+	i, _ := a.find(a.lengths, distance)
+	if i < 0 {
+		return vec2.F{}
+	}
+	return a.points[i+1].Sub(a.points[i]).Normalized()
+}
+
+// Resample appends count points spaced evenly along the whole length to dst, including both ends
+func (a ArcLength) Resample(count int, dst []vec2.F) []vec2.F {
+	if count == 1 {
+		return append(dst, a.PointAt(0))
+	}
+	step := a.Length() / float32(count-1)
+	for i := 0; i < count; i++ {
+		dst = append(dst, a.PointAt(float32(i)*step))
+	}
+	return dst
+}
+
+// find returns the interval of the sorted values that value lies in and the ratio within it,
+// or -1 if there are less than two values. Zero-length intervals are skipped.
+func (a ArcLength) find(values []float32, value float32) (i int, ratio float32) {
+	n := len(values)
+	if n < 2 {
+		return -1, 0
+	}
+	if value <= values[0] {
+		return 0, 0
+	}
+	if value >= values[n-1] {
+		return n - 2, 1
+	}
+	// the first value larger than value ends the interval
+	j, found := slices.BinarySearch(values, value)
+	if found {
+		for j+1 < n && values[j+1] == value {
+			j++
+		}
+		if j == n-1 {
+			return n - 2, 1
+		}
+		return j, 0
+	}
+	i = j - 1
+	return i, (value - values[i]) / (values[j] - values[i])
+}
@@ -0,0 +1,82 @@
+package lerp
+
+import (
+	"testing"
+
+	"github.com/Lundis/go-gmath/vec2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArcLengthPolyline(t *testing.T) {
+	points := []vec2.F{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 30}}
+	a := NewArcLengthPolyline(points)
+	assert.Equal(t, float32(40), a.Length())
+
+	assert.Equal(t, vec2.F{X: 5, Y: 0}, a.PointAt(5))
+	assert.Equal(t, vec2.F{X: 10, Y: 20}, a.PointAt(30))
+	assert.Equal(t, vec2.F{X: 0, Y: 0}, a.PointAt(-5))
+	assert.Equal(t, vec2.F{X: 10, Y: 30}, a.PointAt(100))
+
+	assert.Equal(t, vec2.F{X: 1, Y: 0}, a.TangentAt(5))
+	assert.Equal(t, vec2.F{X: 0, Y: 1}, a.TangentAt(10))
+	assert.Equal(t, vec2.F{X: 0, Y: 1}, a.TangentAt(25))
+
+	// t is spread evenly over the segments
+	assert.InDelta(t, 1.0/6, a.ParamAt(5), 0.0001)
+	assert.InDelta(t, 5, a.DistanceAt(1.0/6), 0.0001)
+	assert.InDelta(t, 10, a.DistanceAt(0.5), 0.0001)
+	assert.InDelta(t, 25, a.DistanceAt(5.0/6), 0.0001)
+
+	resampled := a.Resample(5, nil)
+	assert.Equal(t, []vec2.F{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 10, Y: 20}, {X: 10, Y: 30}}, resampled)
+}
+
+func TestArcLengthCurve(t *testing.T) {
+	// a straight line with uneven speed
+	curve := CubicBezier2{P0: vec2.F{}, P1: vec2.F{X: 9}, P2: vec2.F{X: 9}, P3: vec2.F{X: 10}}
+	a := NewArcLengthCurve(curve, 256)
+	assert.InDelta(t, 10, a.Length(), 0.0001)
+	for _, d := range []float32{0, 1, 2.5, 7, 10} {
+		assert.InDelta(t, d, a.PointAt(d).X, 0.01)
+		assert.InDelta(t, d, a.DistanceAt(a.ParamAt(d)), 0.0001)
+		assert.Equal(t, vec2.F{X: 1}, a.TangentAt(d))
+	}
+
+	// quarter circle approximation with radius 10
+	arc := CubicBezier2{
+		P0: vec2.F{X: 10, Y: 0},
+		P1: vec2.F{X: 10, Y: 5.5228},
+		P2: vec2.F{X: 5.5228, Y: 10},
+		P3: vec2.F{X: 0, Y: 10},
+	}
+	a = NewArcLengthCurve(arc, 100)
+	assert.InDelta(t, 15.708, a.Length(), 0.01)
+	mid := a.PointAt(a.Length() / 2)
+	assert.InDelta(t, mid.X, mid.Y, 0.001)
+
+	resampled := a.Resample(10, nil)
+	spacing := resampled[1].DistanceTo(resampled[0])
+	for i := 2; i < len(resampled); i++ {
+		assert.InDelta(t, spacing, resampled[i].DistanceTo(resampled[i-1]), 0.01)
+	}
+}
+
+func TestArcLengthEmpty(t *testing.T) {
+	a := NewArcLengthPolyline(nil)
+	assert.Equal(t, float32(0), a.Length())
+	assert.Equal(t, vec2.F{}, a.PointAt(1))
+	assert.Equal(t, float32(0), a.ParamAt(1))
+
+	a = NewArcLengthPolyline([]vec2.F{{X: 3, Y: 4}})
+	assert.Equal(t, vec2.F{X: 3, Y: 4}, a.PointAt(1))
+}
+
+func TestArcLengthNoAllocations(t *testing.T) {
+	a := NewArcLengthCurve(testCurve, 64)
+	allocs := testing.AllocsPerRun(10, func() {
+		a.PointAt(5)
+		a.TangentAt(7)
+		a.DistanceAt(0.3)
+	})
+	assert.Equal(t, float64(0), allocs)
+}
@@ -0,0 +1,182 @@
+package lerp
+
+import (
+	"github.com/Lundis/go-gmath/vec2"
+)
+
+// CubicBezier is a one-dimensional cubic Bezier curve from P0 to P3 with the control values P1 and P2
+type CubicBezier struct {
+	P0, P1, P2, P3 float32
+}
+
+// Evaluate returns the value at t in [0, 1]
+func (b CubicBezier) Evaluate(t float32) float32 {
+	u := 1 - t
+	return u*u*u*b.P0 + 3*u*u*t*b.P1 + 3*u*t*t*b.P2 + t*t*t*b.P3
+}
+
+// Derivative returns the rate of change at t
+func (b CubicBezier) Derivative(t float32) float32 {
+	u := 1 - t
+	return 3 * (u*u*(b.P1-b.P0) + 2*u*t*(b.P2-b.P1) + t*t*(b.P3-b.P2))
+}
+
+// Split divides the curve at t into two curves that together are identical to b
+func (b CubicBezier) Split(t float32) (left, right CubicBezier) {
+	// de Casteljau's algorithm
+	p01 := Lerp(b.P0, b.P1, t)
+	p12 := Lerp(b.P1, b.P2, t)
+	p23 := Lerp(b.P2, b.P3, t)
+	p012 := Lerp(p01, p12, t)
+	p123 := Lerp(p12, p23, t)
+	mid := Lerp(p012, p123, t)
+	return CubicBezier{b.P0, p01, p012, mid}, CubicBezier{mid, p123, p23, b.P3}
+}
+
+// Bounds returns the lowest and highest value of the curve, which may lie between the end points
+func (b CubicBezier) Bounds() (low, high float32) {
+	low, high = min(b.P0, b.P3), max(b.P0, b.P3)
+	if b.P1 >= low && b.P1 <= high && b.P2 >= low && b.P2 <= high {
+		// the curve stays within the convex hull of its points
+		return
+	}
+	// the extremes are where the derivative is zero: a*t^2 + b*t + c = 0
+	qa := -b.P0 + 3*b.P1 - 3*b.P2 + b.P3
+	qb := 2 * (b.P0 - 2*b.P1 + b.P2)
+	qc := b.P1 - b.P0
+	roots, n := solveQuadratic(qa, qb, qc)
+	for _, t := range roots[:n] {
+		if t > 0 && t < 1 {
+			v := b.Evaluate(t)
+			low, high = min(low, v), max(high, v)
+		}
+	}
+	return
+}
+
+// CubicBezier2 is a cubic Bezier curve from P0 to P3 with the control points P1 and P2
+type CubicBezier2 struct {
+	P0, P1, P2, P3 vec2.F
+}
+
+func (b CubicBezier2) x() CubicBezier {
+	return CubicBezier{b.P0.X, b.P1.X, b.P2.X, b.P3.X}
+}
+
+func (b CubicBezier2) y() CubicBezier {
+	return CubicBezier{b.P0.Y, b.P1.Y, b.P2.Y, b.P3.Y}
+}
+
+// Evaluate returns the point at t in [0, 1]
+func (b CubicBezier2) Evaluate(t float32) vec2.F {
+	return vec2.F{X: b.x().Evaluate(t), Y: b.y().Evaluate(t)}
+}
+
+// Derivative returns the tangent at t, whose length is the speed of the curve
+func (b CubicBezier2) Derivative(t float32) vec2.F {
+	return vec2.F{X: b.x().Derivative(t), Y: b.y().Derivative(t)}
+}
+
+// Split divides the curve at t into two curves that together are identical to b
+func (b CubicBezier2) Split(t float32) (left, right CubicBezier2) {
+	p01 := Lerp2(b.P0, b.P1, t)
+	p12 := Lerp2(b.P1, b.P2, t)
+	p23 := Lerp2(b.P2, b.P3, t)
+	p012 := Lerp2(p01, p12, t)
+	p123 := Lerp2(p12, p23, t)
+	mid := Lerp2(p012, p123, t)
+	return CubicBezier2{b.P0, p01, p012, mid}, CubicBezier2{mid, p123, p23, b.P3}
+}
+
+// Bounds returns the tight bounding box of the curve
+func (b CubicBezier2) Bounds() vec2.Rect {
+	minX, maxX := b.x().Bounds()
+	minY, maxY := b.y().Bounds()
+	return vec2.Rect{Min: vec2.F{X: minX, Y: minY}, Max: vec2.F{X: maxX, Y: maxY}}
+}
+
+// Flatten approximates the curve with line segments that deviate at most tolerance from it.
+// The points, including both end points, are appended to dst.
+func (b CubicBezier2) Flatten(tolerance float32, dst []vec2.F) []vec2.F {
+	dst = append(dst, b.P0)
+	return b.flatten(16*tolerance*tolerance, dst, 0)
+}
+
+// flatten appends the points after P0, subdividing until the curve is flat enough
+func (b CubicBezier2) flatten(limit float32, dst []vec2.F, depth int) []vec2.F {
+	if depth >= maxFlattenDepth || b.flatness() <= limit {
+		return append(dst, b.P3)
+	}
+	left, right := b.Split(0.5)
+	dst = left.flatten(limit, dst, depth+1)
+	return right.flatten(limit, dst, depth+1)
+}
+
+// maxFlattenDepth limits the subdivision to 2^16 segments per curve
+const maxFlattenDepth = 16
+
+// flatness returns 16 times an upper bound of the squared distance between the curve and its chord
+func (b CubicBezier2) flatness() float32 {
+	u := b.P1.MulScalar(3).Sub(b.P0.MulScalar(2)).Sub(b.P3)
+	v := b.P2.MulScalar(3).Sub(b.P3.MulScalar(2)).Sub(b.P0)
+	return max(u.X*u.X, v.X*v.X) + max(u.Y*u.Y, v.Y*v.Y)
+}
+
+// Path2 is a sequence of cubic Bezier curves. The parameter t in [0, 1] is spread evenly over the curves.
+type Path2 []CubicBezier2
+
+func (p Path2) Segments() int {
+	return len(p)
+}
+
+func (p Path2) Segment(i int) CubicBezier2 {
+	return p[i]
+}
+
+func (p Path2) Evaluate(t float32) vec2.F {
+	return evaluate(p, t)
+}
+
+func (p Path2) Derivative(t float32) vec2.F {
+	return derivative(p, t)
+}
+
+func (p Path2) Bounds() vec2.Rect {
+	return bounds(p)
+}
+
+func (p Path2) Flatten(tolerance float32, dst []vec2.F) []vec2.F {
+	return flatten(p, tolerance, dst)
+}
+
+// Split divides the path at t into two new paths
+func (p Path2) Split(t float32) (left, right Path2) {
+	if len(p) == 0 {
+		return nil, nil
+	}
+	i, local := segmentAt(len(p), t)
+	l, r := p[i].Split(local)
+	left = append(append(make(Path2, 0, i+1), p[:i]...), l)
+	right = append(append(make(Path2, 0, len(p)-i), r), p[i+1:]...)
+	return
+}
+
+// solveQuadratic returns the real roots of a*x^2 + b*x + c = 0
+func solveQuadratic(a, b, c float32) (roots [2]float32, n int) {
+	const epsilon = 1e-7
+	if a > -epsilon && a < epsilon {
+		if b == 0 {
+			return
+		}
+		roots[0] = -c / b
+		return roots, 1
+	}
+	discriminant := b*b - 4*a*c
+	if discriminant < 0 {
+		return
+	}
+	sqrtD := sqrt(discriminant)
+	roots[0] = (-b + sqrtD) / (2 * a)
+	roots[1] = (-b - sqrtD) / (2 * a)
+	return roots, 2
+}
@@ -0,0 +1,62 @@
+package lerp
+
+import "github.com/Lundis/go-gmath/vec2"
+
+// BSpline2 is a uniform cubic B-spline. It's smoother than a Catmull-Rom spline, but only approaches its points
+// instead of passing through them. Repeat the first and last point three times to make an open spline start and end there.
+type BSpline2 struct {
+	Points []vec2.F
+	Closed bool
+}
+
+func (b BSpline2) Segments() int {
+	if b.Closed {
+		if len(b.Points) < 3 {
+			return 0
+		}
+		return len(b.Points)
+	}
+	return max(len(b.Points)-3, 0)
+}
+
+// Segment returns the part controlled by points i to i+3 as a Bezier curve
+func (b BSpline2) Segment(i int) CubicBezier2 {
+	p0, p1, p2, p3 := b.point(i), b.point(i+1), b.point(i+2), b.point(i+3)
+	return CubicBezier2{
+		P0: p0.Add(p1.MulScalar(4)).Add(p2).DivScalar(6),
+		P1: p1.MulScalar(2).Add(p2).DivScalar(3),
+		P2: p1.Add(p2.MulScalar(2)).DivScalar(3),
+		P3: p1.Add(p2.MulScalar(4)).Add(p3).DivScalar(6),
+	}
+}
+
+func (b BSpline2) point(i int) vec2.F {
+	n := len(b.Points)
+	return b.Points[i%n]
+}
+
+func (b BSpline2) Evaluate(t float32) vec2.F {
+	return evaluate(b, t)
+}
+
+func (b BSpline2) Derivative(t float32) vec2.F {
+	return derivative(b, t)
+}
+
+func (b BSpline2) Bounds() vec2.Rect {
+	return bounds(b)
+}
+
+func (b BSpline2) Flatten(tolerance float32, dst []vec2.F) []vec2.F {
+	return flatten(b, tolerance, dst)
+}
+
+// Split divides the spline at t into two paths
+func (b BSpline2) Split(t float32) (left, right Path2) {
+	return splitPath(b, t)
+}
+
+// Path appends the spline to dst as Bezier curves
+func (b BSpline2) Path(dst Path2) Path2 {
+	return toPath(b, dst)
+}
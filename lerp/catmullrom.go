@@ -0,0 +1,104 @@
+package lerp
+
+import (
+	"math"
+
+	"github.com/Lundis/go-gmath/vec2"
+)
+
+// Parameterizations of Catmull-Rom splines, used as CatmullRom2.Alpha
+const (
+	// Uniform is the classic Catmull-Rom spline, which can form loops and cusps at sharp turns
+	Uniform float32 = 0
+	// Centripetal never forms loops or cusps within a segment, and is usually the best choice
+	Centripetal float32 = 0.5
+	// Chordal follows the control points more tightly
+	Chordal float32 = 1
+)
+
+// CatmullRom returns the value at t in [0, 1] between p1 and p2 of a uniform Catmull-Rom spline
+func CatmullRom(p0, p1, p2, p3, t float32) float32 {
+	return Hermite{P0: p1, M0: (p2 - p0) / 2, P1: p2, M1: (p3 - p1) / 2}.Evaluate(t)
+}
+
+// CatmullRom2 is a spline that passes through all of its points.
+// Open splines run from the first to the last point, closed ones also connect the last point to the first.
+type CatmullRom2 struct {
+	Points []vec2.F
+	// Alpha is the parameterization, usually Uniform, Centripetal or Chordal
+	Alpha  float32
+	Closed bool
+}
+
+func (c CatmullRom2) Segments() int {
+	if c.Closed {
+		if len(c.Points) < 2 {
+			return 0
+		}
+		return len(c.Points)
+	}
+	return max(len(c.Points)-1, 0)
+}
+
+// Segment returns the part between point i and the next one as a Bezier curve
+func (c CatmullRom2) Segment(i int) CubicBezier2 {
+	p0, p1, p2, p3 := c.point(i-1), c.point(i), c.point(i+1), c.point(i+2)
+
+	// the tangents of the non-uniform spline, scaled to the [0, 1] range of the segment
+	d0 := knotInterval(p0, p1, c.Alpha)
+	d1 := knotInterval(p1, p2, c.Alpha)
+	d2 := knotInterval(p2, p3, c.Alpha)
+	m1 := p1.Sub(p0).DivScalar(d0).Sub(p2.Sub(p0).DivScalar(d0 + d1)).Add(p2.Sub(p1).DivScalar(d1)).MulScalar(d1)
+	m2 := p2.Sub(p1).DivScalar(d1).Sub(p3.Sub(p1).DivScalar(d1 + d2)).Add(p3.Sub(p2).DivScalar(d2)).MulScalar(d1)
+	return Hermite2{P0: p1, M0: m1, P1: p2, M1: m2}.Bezier()
+}
+
+// point returns point i, wrapping around for closed splines and extrapolating the ends of open ones
+func (c CatmullRom2) point(i int) vec2.F {
+	n := len(c.Points)
+	if c.Closed {
+		return c.Points[(i%n+n)%n]
+	}
+	if i < 0 {
+		return c.Points[0].MulScalar(2).Sub(c.Points[1])
+	}
+	if i >= n {
+		return c.Points[n-1].MulScalar(2).Sub(c.Points[n-2])
+	}
+	return c.Points[i]
+}
+
+func knotInterval(a, b vec2.F, alpha float32) float32 {
+	d := float32(math.Pow(float64(a.DistanceToSquared(b)), float64(alpha/2)))
+	if d < 1e-4 {
+		// duplicate points would divide by zero
+		return 1
+	}
+	return d
+}
+
+func (c CatmullRom2) Evaluate(t float32) vec2.F {
+	return evaluate(c, t)
+}
+
+func (c CatmullRom2) Derivative(t float32) vec2.F {
+	return derivative(c, t)
+}
+
+func (c CatmullRom2) Bounds() vec2.Rect {
+	return bounds(c)
+}
+
+func (c CatmullRom2) Flatten(tolerance float32, dst []vec2.F) []vec2.F {
+	return flatten(c, tolerance, dst)
+}
+
+// Split divides the spline at t into two paths
+func (c CatmullRom2) Split(t float32) (left, right Path2) {
+	return splitPath(c, t)
+}
+
+// Path appends the spline to dst as Bezier curves
+func (c CatmullRom2) Path(dst Path2) Path2 {
+	return toPath(c, dst)
+}
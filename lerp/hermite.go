@@ -0,0 +1,70 @@
+package lerp
+
+import "github.com/Lundis/go-gmath/vec2"
+
+// Hermite is a one-dimensional cubic Hermite curve from P0 to P1, with the derivatives M0 and M1 at the ends
+type Hermite struct {
+	P0, M0, P1, M1 float32
+}
+
+func (h Hermite) Evaluate(t float32) float32 {
+	t2 := t * t
+	t3 := t2 * t
+	return (2*t3-3*t2+1)*h.P0 + (t3-2*t2+t)*h.M0 + (-2*t3+3*t2)*h.P1 + (t3-t2)*h.M1
+}
+
+func (h Hermite) Derivative(t float32) float32 {
+	t2 := t * t
+	return (6*t2-6*t)*h.P0 + (3*t2-4*t+1)*h.M0 + (-6*t2+6*t)*h.P1 + (3*t2-2*t)*h.M1
+}
+
+// Bezier returns the identical curve in Bezier form
+func (h Hermite) Bezier() CubicBezier {
+	return CubicBezier{h.P0, h.P0 + h.M0/3, h.P1 - h.M1/3, h.P1}
+}
+
+// Hermite2 is a cubic Hermite curve from P0 to P1, with the tangents M0 and M1 at the ends
+type Hermite2 struct {
+	P0, M0, P1, M1 vec2.F
+}
+
+func (h Hermite2) Evaluate(t float32) vec2.F {
+	return h.Bezier().Evaluate(t)
+}
+
+func (h Hermite2) Derivative(t float32) vec2.F {
+	return h.Bezier().Derivative(t)
+}
+
+// Bezier returns the identical curve in Bezier form
+func (h Hermite2) Bezier() CubicBezier2 {
+	return CubicBezier2{
+		P0: h.P0,
+		P1: h.P0.Add(h.M0.DivScalar(3)),
+		P2: h.P1.Sub(h.M1.DivScalar(3)),
+		P3: h.P1,
+	}
+}
+
+// Split divides the curve at t into two curves that together are identical to h
+func (h Hermite2) Split(t float32) (left, right Hermite2) {
+	l, r := h.Bezier().Split(t)
+	return hermiteFromBezier(l), hermiteFromBezier(r)
+}
+
+func (h Hermite2) Bounds() vec2.Rect {
+	return h.Bezier().Bounds()
+}
+
+func (h Hermite2) Flatten(tolerance float32, dst []vec2.F) []vec2.F {
+	return h.Bezier().Flatten(tolerance, dst)
+}
+
+func hermiteFromBezier(b CubicBezier2) Hermite2 {
+	return Hermite2{
+		P0: b.P0,
+		M0: b.P1.Sub(b.P0).MulScalar(3),
+		P1: b.P3,
+		M1: b.P3.Sub(b.P2).MulScalar(3),
+	}
+}
@@ -15,8 +15,8 @@ func TestLerp(t *testing.T) {
 }
 
 func TestLerp2(t *testing.T) {
-	a := vec2.F{0, 0}
-	b := vec2.F{10, 10}
+	a := vec2.F{X: 0, Y: 0}
+	b := vec2.F{X: 10, Y: 10}
 	res := Lerp2(a, b, 0.5)
 
 	assert.Equal(t, float32(5), res.X)
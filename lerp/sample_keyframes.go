@@ -2,9 +2,16 @@ package lerp
 
 import "github.com/Lundis/go-gmath/vec2"
 
-// SampleKeyframes samples by keyframes. X is the time, and Y is the value. keyFrames must be sorted by time
+// SampleKeyframes samples by keyframes. X is the time, and Y is the value. keyFrames must be sorted by time.
+// Times outside the keyframes return the value of the first or last keyframe. See Track for more options.
 func SampleKeyframes(keyFrames []vec2.F, t float32) float32 {
-	for i := 0; i < len(keyFrames); i++ {
+	if len(keyFrames) == 0 {
+		return 0
+	}
+	if t <= keyFrames[0].X {
+		return keyFrames[0].Y
+	}
+	for i := 1; i < len(keyFrames); i++ {
 		if t > keyFrames[i].X {
 			continue
 		}
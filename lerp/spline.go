@@ -0,0 +1,83 @@
+package lerp
+
+import (
+	"math"
+
+	"github.com/Lundis/go-gmath/vec2"
+)
+
+// segmented is a spline made of cubic Bezier segments, which the shared spline operations work on
+type segmented interface {
+	Segments() int
+	Segment(i int) CubicBezier2
+}
+
+// segmentAt maps t in [0, 1] to a segment and the parameter within it
+func segmentAt(segments int, t float32) (i int, local float32) {
+	t = min(max(t, 0), 1) * float32(segments)
+	i = min(int(t), segments-1)
+	return i, t - float32(i)
+}
+
+func evaluate[S segmented](s S, t float32) vec2.F {
+	n := s.Segments()
+	if n == 0 {
+		return vec2.F{}
+	}
+	i, local := segmentAt(n, t)
+	return s.Segment(i).Evaluate(local)
+}
+
+func derivative[S segmented](s S, t float32) vec2.F {
+	n := s.Segments()
+	if n == 0 {
+		return vec2.F{}
+	}
+	i, local := segmentAt(n, t)
+	// each segment covers 1/n of t
+	return s.Segment(i).Derivative(local).MulScalar(float32(n))
+}
+
+func bounds[S segmented](s S) vec2.Rect {
+	n := s.Segments()
+	if n == 0 {
+		return vec2.Rect{}
+	}
+	r := s.Segment(0).Bounds()
+	for i := 1; i < n; i++ {
+		r = r.Union(s.Segment(i).Bounds())
+	}
+	return r
+}
+
+func flatten[S segmented](s S, tolerance float32, dst []vec2.F) []vec2.F {
+	n := s.Segments()
+	if n == 0 {
+		return dst
+	}
+	limit := 16 * tolerance * tolerance
+	first := s.Segment(0)
+	dst = append(dst, first.P0)
+	for i := 0; i < n; i++ {
+		dst = s.Segment(i).flatten(limit, dst, 0)
+	}
+	return dst
+}
+
+// toPath converts any spline to a sequence of Bezier curves
+func toPath[S segmented](s S, dst Path2) Path2 {
+	for i := 0; i < s.Segments(); i++ {
+		dst = append(dst, s.Segment(i))
+	}
+	return dst
+}
+
+// splitPath splits any spline at t. The halves can't be represented by the original spline type in general,
+// so they are returned as paths.
+func splitPath[S segmented](s S, t float32) (left, right Path2) {
+	return toPath(s, nil).Split(t)
+}
+
+func sqrt(x float32) float32 {
+	return float32(math.Sqrt(float64(x)))
+}
@@ -0,0 +1,166 @@
+package lerp
+
+import (
+	"math"
+	"testing"
+
+	"github.com/Lundis/go-gmath/vec2"
+	"github.com/stretchr/testify/assert"
+)
+
+var testCurve = CubicBezier2{
+	P0: vec2.F{X: 0, Y: 0},
+	P1: vec2.F{X: 0, Y: 10},
+	P2: vec2.F{X: 10, Y: 10},
+	P3: vec2.F{X: 10, Y: 0},
+}
+
+func assertVecInDelta(t *testing.T, expected, actual vec2.F, delta float64) {
+	t.Helper()
+	assert.InDelta(t, expected.X, actual.X, delta, "X of %v", actual)
+	assert.InDelta(t, expected.Y, actual.Y, delta, "Y of %v", actual)
+}
+
+func TestCubicBezier(t *testing.T) {
+	b := CubicBezier{0, 1, 1, 0}
+	assert.Equal(t, float32(0.75), b.Evaluate(0.5))
+	assert.Equal(t, float32(0), b.Derivative(0.5))
+	low, high := b.Bounds()
+	assert.Equal(t, float32(0), low)
+	assert.InDelta(t, 0.75, high, 0.0001)
+
+	left, right := b.Split(0.3)
+	assert.InDelta(t, b.Evaluate(0.15), left.Evaluate(0.5), 0.0001)
+	assert.InDelta(t, b.Evaluate(0.65), right.Evaluate(0.5), 0.0001)
+}
+
+func TestCubicBezier2(t *testing.T) {
+	assert.Equal(t, vec2.F{X: 5, Y: 7.5}, testCurve.Evaluate(0.5))
+	assert.Equal(t, testCurve.P3, testCurve.Evaluate(1))
+	assert.Equal(t, vec2.F{X: 0, Y: 30}, testCurve.Derivative(0))
+	assert.Equal(t, vec2.F{X: 15, Y: 0}, testCurve.Derivative(0.5))
+
+	bounds := testCurve.Bounds()
+	assert.Equal(t, vec2.F{}, bounds.Min)
+	assert.InDelta(t, 10, bounds.Max.X, 0.0001)
+	assert.InDelta(t, 7.5, bounds.Max.Y, 0.0001)
+
+	left, right := testCurve.Split(0.25)
+	assert.Equal(t, testCurve.Evaluate(0.25), left.P3)
+	assert.Equal(t, left.P3, right.P0)
+	assertVecInDelta(t, testCurve.Evaluate(0.625), right.Evaluate(0.5), 0.0001)
+}
+
+func TestFlatten(t *testing.T) {
+	coarse := testCurve.Flatten(1, nil)
+	fine := testCurve.Flatten(0.01, nil)
+	assert.Greater(t, len(fine), len(coarse))
+	assert.Equal(t, testCurve.P0, fine[0])
+	assert.Equal(t, testCurve.P3, fine[len(fine)-1])
+
+	// every sample of the curve is close to the polyline
+	for _, tolerance := range []float32{1, 0.1, 0.01} {
+		points := testCurve.Flatten(tolerance, nil)
+		for i := 0; i <= 100; i++ {
+			p := testCurve.Evaluate(float32(i) / 100)
+			best := float32(1e9)
+			for j := 1; j < len(points); j++ {
+				closest, _ := vec2.ClosestPointOnLineSegmentF(points[j-1], points[j], p)
+				best = min(best, closest.DistanceTo(p))
+			}
+			assert.LessOrEqual(t, best, tolerance)
+		}
+	}
+
+	straight := CubicBezier2{P1: vec2.F{X: 1}, P2: vec2.F{X: 2}, P3: vec2.F{X: 3}}
+	assert.Len(t, straight.Flatten(0.01, nil), 2)
+}
+
+func TestHermite(t *testing.T) {
+	h := Hermite{P0: 0, M0: 3, P1: 1, M1: 3}
+	b := h.Bezier()
+	for _, x := range []float32{0, 0.3, 0.5, 1} {
+		assert.InDelta(t, h.Evaluate(x), b.Evaluate(x), 0.0001)
+		assert.InDelta(t, h.Derivative(x), b.Derivative(x), 0.0001)
+	}
+	assert.Equal(t, float32(3), h.Derivative(0))
+
+	h2 := Hermite2{P0: vec2.F{}, M0: vec2.F{Y: 30}, P1: vec2.F{X: 10}, M1: vec2.F{Y: -30}}
+	assert.Equal(t, testCurve, h2.Bezier())
+	left, right := h2.Split(0.5)
+	assert.Equal(t, left.P1, right.P0)
+	assertVecInDelta(t, h2.Evaluate(0.75), right.Evaluate(0.5), 0.0001)
+}
+
+func TestCatmullRom(t *testing.T) {
+	assert.Equal(t, float32(2), CatmullRom(0, 1, 2, 3, 1))
+	assert.Equal(t, float32(1.5), CatmullRom(0, 1, 2, 3, 0.5))
+
+	points := []vec2.F{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}, {X: 0, Y: 20}}
+	for _, alpha := range []float32{Uniform, Centripetal, Chordal} {
+		c := CatmullRom2{Points: points, Alpha: alpha}
+		assert.Equal(t, 4, c.Segments())
+		// passes through every point
+		for i, p := range points {
+			assertVecInDelta(t, p, c.Evaluate(float32(i)/4), 0.0001)
+		}
+		// smooth at the joints
+		for i := 1; i < 4; i++ {
+			assertVecInDelta(t, c.Segment(i-1).Derivative(1), c.Segment(i).Derivative(0), 0.001)
+		}
+	}
+
+	closed := CatmullRom2{Points: points[:4], Alpha: Centripetal, Closed: true}
+	assert.Equal(t, 4, closed.Segments())
+	assertVecInDelta(t, points[0], closed.Evaluate(1), 0.0001)
+	assertVecInDelta(t, closed.Derivative(0), closed.Derivative(1), 0.001)
+
+	// duplicate points don't produce NaN
+	dup := CatmullRom2{Points: []vec2.F{{X: 0}, {X: 0}, {X: 5}}, Alpha: Centripetal}
+	p := dup.Evaluate(0.75)
+	assert.False(t, math.IsNaN(float64(p.X)) || math.IsNaN(float64(p.Y)))
+
+	assert.Equal(t, vec2.F{}, CatmullRom2{}.Evaluate(0.5))
+}
+
+func TestBSpline(t *testing.T) {
+	points := []vec2.F{{X: 0, Y: 0}, {X: 0, Y: 0}, {X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 10, Y: 10}, {X: 10, Y: 10}}
+	b := BSpline2{Points: points}
+	assert.Equal(t, 4, b.Segments())
+	assert.Equal(t, points[0], b.Evaluate(0))
+	assert.Equal(t, points[6], b.Evaluate(1))
+	for i := 1; i < 4; i++ {
+		assertVecInDelta(t, b.Segment(i-1).Evaluate(1), b.Segment(i).Evaluate(0), 0.0001)
+		assertVecInDelta(t, b.Segment(i-1).Derivative(1), b.Segment(i).Derivative(0), 0.0001)
+	}
+	bounds := b.Bounds()
+	assert.Equal(t, vec2.Rect{Max: vec2.F{X: 10, Y: 10}}, bounds)
+
+	closed := BSpline2{Points: []vec2.F{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}}, Closed: true}
+	assertVecInDelta(t, closed.Evaluate(0), closed.Evaluate(1), 0.0001)
+}
+
+func TestPathSplit(t *testing.T) {
+	c := CatmullRom2{Points: []vec2.F{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}}, Alpha: Centripetal}
+	path := c.Path(nil)
+	assert.Len(t, path, 2)
+	assert.Equal(t, c.Evaluate(0.3), path.Evaluate(0.3))
+
+	left, right := c.Split(0.75)
+	assert.Len(t, left, 2)
+	assert.Len(t, right, 1)
+	assertVecInDelta(t, c.Evaluate(0.75), left.Evaluate(1), 0.0001)
+	assertVecInDelta(t, c.Evaluate(0.75), right.Evaluate(0), 0.0001)
+	assertVecInDelta(t, c.Evaluate(0.875), right.Evaluate(0.5), 0.0001)
+
+	points := c.Flatten(0.1, nil)
+	assert.Equal(t, c.Points[0], points[0])
+	assertVecInDelta(t, c.Points[2], points[len(points)-1], 0.0001)
+}
+
+func BenchmarkFlatten(b *testing.B) {
+	dst := make([]vec2.F, 0, 1024)
+	for i := 0; i < b.N; i++ {
+		dst = testCurve.Flatten(0.1, dst[:0])
+	}
+}
@@ -0,0 +1,414 @@
+package lerp
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/Lundis/go-gmath/easings"
+	"github.com/Lundis/go-gmath/vec2"
+	"github.com/Lundis/go-gmath/vec3"
+)
+
+// Interpolator blends values of type T for a Track
+type Interpolator[T any] interface {
+	Lerp(a, b T, t float32) T
+	// Hermite interpolates from a to b, where m0 and m1 are the tangents at a and b
+	Hermite(a, m0, b, m1 T, t float32) T
+	// Slope returns (b - a) * scale
+	Slope(a, b T, scale float32) T
+}
+
+type FloatInterpolator struct{}
+
+func (FloatInterpolator) Lerp(a, b float32, t float32) float32 {
+	return Lerp(a, b, t)
+}
+
+func (FloatInterpolator) Hermite(a, m0, b, m1 float32, t float32) float32 {
+	return Hermite{P0: a, M0: m0, P1: b, M1: m1}.Evaluate(t)
+}
+
+func (FloatInterpolator) Slope(a, b float32, scale float32) float32 {
+	return (b - a) * scale
+}
+
+type Vec2Interpolator struct{}
+
+func (Vec2Interpolator) Lerp(a, b vec2.F, t float32) vec2.F {
+	return Lerp2(a, b, t)
+}
+
+func (Vec2Interpolator) Hermite(a, m0, b, m1 vec2.F, t float32) vec2.F {
+	return Hermite2{P0: a, M0: m0, P1: b, M1: m1}.Evaluate(t)
+}
+
+func (Vec2Interpolator) Slope(a, b vec2.F, scale float32) vec2.F {
+	return b.Sub(a).MulScalar(scale)
+}
+
+type Vec3Interpolator struct{}
+
+func (Vec3Interpolator) Lerp(a, b vec3.F, t float32) vec3.F {
+	return a.Add(b.Sub(a).MulScalar(t))
+}
+
+func (Vec3Interpolator) Hermite(a, m0, b, m1 vec3.F, t float32) vec3.F {
+	var f FloatInterpolator
+	return vec3.F{
+		X: f.Hermite(a.X, m0.X, b.X, m1.X, t),
+		Y: f.Hermite(a.Y, m0.Y, b.Y, m1.Y, t),
+		Z: f.Hermite(a.Z, m0.Z, b.Z, m1.Z, t),
+	}
+}
+
+func (Vec3Interpolator) Slope(a, b vec3.F, scale float32) vec3.F {
+	return b.Sub(a).MulScalar(scale)
+}
+
+// AngleInterpolator blends angles in radians along the shortest way around the circle.
+// The results are not wrapped to any particular range.
+type AngleInterpolator struct{}
+
+func (AngleInterpolator) Lerp(a, b float32, t float32) float32 {
+	return a + angleDiff(a, b)*t
+}
+
+func (AngleInterpolator) Hermite(a, m0, b, m1 float32, t float32) float32 {
+	return Hermite{P0: a, M0: m0, P1: a + angleDiff(a, b), M1: m1}.Evaluate(t)
+}
+
+func (AngleInterpolator) Slope(a, b float32, scale float32) float32 {
+	return angleDiff(a, b) * scale
+}
+
+// angleDiff returns the shortest signed angle from a to b, in the range [-Pi, Pi)
+func angleDiff(a, b float32) float32 {
+	d := math.Mod(float64(b-a)+math.Pi, 2*math.Pi)
+	if d < 0 {
+		d += 2 * math.Pi
+	}
+	return float32(d - math.Pi)
+}
+
+// Interpolation decides how a Track moves from a key to the next one
+type Interpolation int
+
+const (
+	Linear Interpolation = iota
+	// Step holds the value of the key until the next key
+	Step
+	// Cubic moves smoothly through the keys, like a Catmull-Rom spline that respects the timing of the keys
+	Cubic
+)
+
+var interpolationNames = []string{"linear", "step", "cubic"}
+
+func (i Interpolation) String() string {
+	if i < 0 || int(i) >= len(interpolationNames) {
+		return fmt.Sprintf("Interpolation(%d)", int(i))
+	}
+	return interpolationNames[i]
+}
+
+func (i Interpolation) MarshalText() ([]byte, error) {
+	return []byte(i.String()), nil
+}
+
+func (i *Interpolation) UnmarshalText(text []byte) error {
+	index := slices.Index(interpolationNames, string(text))
+	if index < 0 {
+		return fmt.Errorf("Unknown interpolation: %v", string(text))
+	}
+	*i = Interpolation(index)
+	return nil
+}
+
+// WrapMode decides what a Track returns before its first key and after its last key
+type WrapMode int
+
+const (
+	// Clamp holds the value of the first or last key
+	Clamp WrapMode = iota
+	// Loop repeats the track
+	Loop
+	// PingPong repeats the track, playing every other repetition backwards
+	PingPong
+	// Extrapolate continues linearly along the first or last two keys
+	Extrapolate
+)
+
+var wrapModeNames = []string{"clamp", "loop", "pingPong", "extrapolate"}
+
+func (w WrapMode) String() string {
+	if w < 0 || int(w) >= len(wrapModeNames) {
+		return fmt.Sprintf("WrapMode(%d)", int(w))
+	}
+	return wrapModeNames[w]
+}
+
+func (w WrapMode) MarshalText() ([]byte, error) {
+	return []byte(w.String()), nil
+}
+
+func (w *WrapMode) UnmarshalText(text []byte) error {
+	index := slices.Index(wrapModeNames, string(text))
+	if index < 0 {
+		return fmt.Errorf("Unknown wrap mode: %v", string(text))
+	}
+	*w = WrapMode(index)
+	return nil
+}
+
+// Key is a keyframe of a Track
+type Key[T any] struct {
+	Time  float32 `json:"time"`
+	Value T       `json:"value"`
+	// Interpolation is used between this key and the next one
+	Interpolation Interpolation `json:"interpolation,omitzero"`
+	// Easing is applied to the progress between this key and the next one, if set
+	Easing easings.Named `json:"easing,omitzero"`
+}
+
+// Track is a sequence of keyframes that can be sampled at any time.
+// It remembers the last sampled keys to speed up sequential playback, so it must not be sampled from several goroutines at once.
+// T can be float32, vec2.F or vec3.F as is. Tracks of other types need an Interpolator: UnmarshalJSON returns an error
+// without one, and Sample panics.
+type Track[T any] struct {
+	// Keys must be sorted by time
+	Keys []Key[T] `json:"keys"`
+	// Pre is used before the first key
+	Pre WrapMode `json:"pre,omitzero"`
+	// Post is used after the last key
+	Post WrapMode `json:"post,omitzero"`
+	// Interpolator blends the values. It defaults to FloatInterpolator, Vec2Interpolator or Vec3Interpolator
+	// depending on T, and must be set for other types or to interpolate angles.
+	Interpolator Interpolator[T] `json:"-"`
+
+	cursor int
+}
+
+// NewTrack creates a track with the given interpolator. keys is sorted by time in place.
+func NewTrack[T any](interpolator Interpolator[T], keys []Key[T]) *Track[T] {
+	sortKeys(keys)
+	return &Track[T]{Keys: keys, Interpolator: interpolator}
+}
+
+func NewTrackFloat(keys []Key[float32]) *Track[float32] {
+	return NewTrack[float32](FloatInterpolator{}, keys)
+}
+
+func NewTrack2(keys []Key[vec2.F]) *Track[vec2.F] {
+	return NewTrack[vec2.F](Vec2Interpolator{}, keys)
+}
+
+func NewTrack3(keys []Key[vec3.F]) *Track[vec3.F] {
+	return NewTrack[vec3.F](Vec3Interpolator{}, keys)
+}
+
+// NewTrackAngle creates a track of angles in radians, which turn the shortest way between keys
+func NewTrackAngle(keys []Key[float32]) *Track[float32] {
+	return NewTrack[float32](AngleInterpolator{}, keys)
+}
+
+func sortKeys[T any](keys []Key[T]) {
+	slices.SortStableFunc(keys, func(a, b Key[T]) int {
+		if a.Time < b.Time {
+			return -1
+		} else if a.Time > b.Time {
+			return 1
+		}
+		return 0
+	})
+}
+
+// Start returns the time of the first key
+func (tr *Track[T]) Start() float32 {
+	if len(tr.Keys) == 0 {
+		return 0
+	}
+	return tr.Keys[0].Time
+}
+
+// End returns the time of the last key
+func (tr *Track[T]) End() float32 {
+	if len(tr.Keys) == 0 {
+		return 0
+	}
+	return tr.Keys[len(tr.Keys)-1].Time
+}
+
+func (tr *Track[T]) Duration() float32 {
+	return tr.End() - tr.Start()
+}
+
+// Sample returns the value at time t
+func (tr *Track[T]) Sample(t float32) T {
+	n := len(tr.Keys)
+	if n == 0 {
+		var zero T
+		return zero
+	}
+	if n == 1 {
+		return tr.Keys[0].Value
+	}
+	if tr.Interpolator == nil {
+		interpolator, err := defaultInterpolator[T]()
+		if err != nil {
+			panic(err)
+		}
+		tr.Interpolator = interpolator
+	}
+
+	start, end := tr.Keys[0].Time, tr.Keys[n-1].Time
+	if t < start {
+		switch tr.Pre {
+		case Clamp:
+			return tr.Keys[0].Value
+		case Extrapolate:
+			return tr.extrapolate(0, t)
+		default:
+			t = tr.wrap(t, tr.Pre)
+		}
+	} else if t > end {
+		switch tr.Post {
+		case Clamp:
+			return tr.Keys[n-1].Value
+		case Extrapolate:
+			return tr.extrapolate(n-2, t)
+		default:
+			t = tr.wrap(t, tr.Post)
+		}
+	}
+	return tr.interpolate(tr.find(t), t)
+}
+
+// wrap maps t into the range of the keys
+func (tr *Track[T]) wrap(t float32, mode WrapMode) float32 {
+	start := tr.Start()
+	duration := float64(tr.Duration())
+	if duration <= 0 {
+		return start
+	}
+	offset := math.Mod(float64(t-start), 2*duration)
+	if offset < 0 {
+		offset += 2 * duration
+	}
+	if offset > duration {
+		if mode == PingPong {
+			offset = 2*duration - offset
+		} else {
+			offset -= duration
+		}
+	}
+	return start + float32(offset)
+}
+
+func (tr *Track[T]) extrapolate(i int, t float32) T {
+	k0, k1 := tr.Keys[i], tr.Keys[i+1]
+	if k1.Time <= k0.Time {
+		if t < k0.Time {
+			return k0.Value
+		}
+		return k1.Value
+	}
+	return tr.Interpolator.Lerp(k0.Value, k1.Value, (t-k0.Time)/(k1.Time-k0.Time))
+}
+
+// find returns i so that key i and i+1 surround t, which must be within the range of the keys
+func (tr *Track[T]) find(t float32) int {
+	keys := tr.Keys
+	last := len(keys) - 2
+	// sequential playback usually stays on the same keys or moves on to the next ones
+	if c := tr.cursor; c <= last && keys[c].Time <= t {
+		if t < keys[c+1].Time {
+			return c
+		}
+		if c+1 <= last && t < keys[c+2].Time {
+			tr.cursor = c + 1
+			return c + 1
+		}
+	}
+	// the last key with a time <= t
+	low, high := 0, len(keys)-1
+	for low < high {
+		mid := (low + high + 1) / 2
+		if keys[mid].Time <= t {
+			low = mid
+		} else {
+			high = mid - 1
+		}
+	}
+	tr.cursor = min(low, last)
+	return tr.cursor
+}
+
+func (tr *Track[T]) interpolate(i int, t float32) T {
+	k0, k1 := tr.Keys[i], tr.Keys[i+1]
+	duration := k1.Time - k0.Time
+	if duration <= 0 {
+		return k1.Value
+	}
+	ratio := (t - k0.Time) / duration
+	if k0.Interpolation == Step {
+		if ratio >= 1 {
+			return k1.Value
+		}
+		return k0.Value
+	}
+	if k0.Easing.Func != nil {
+		ratio = k0.Easing.Func(ratio)
+	}
+	if k0.Interpolation != Cubic {
+		return tr.Interpolator.Lerp(k0.Value, k1.Value, ratio)
+	}
+	m0 := tr.tangent(i, duration)
+	m1 := tr.tangent(i+1, duration)
+	return tr.Interpolator.Hermite(k0.Value, m0, k1.Value, m1, ratio)
+}
+
+// tangent returns the slope at key i by finite differences, scaled to a segment of the given duration
+func (tr *Track[T]) tangent(i int, duration float32) T {
+	prev, next := max(i-1, 0), min(i+1, len(tr.Keys)-1)
+	span := tr.Keys[next].Time - tr.Keys[prev].Time
+	if span <= 0 {
+		return tr.Interpolator.Slope(tr.Keys[i].Value, tr.Keys[i].Value, 0)
+	}
+	return tr.Interpolator.Slope(tr.Keys[prev].Value, tr.Keys[next].Value, duration/span)
+}
+
+func (tr *Track[T]) UnmarshalJSON(data []byte) error {
+	type Alias Track[T]
+	alias := Alias{Interpolator: tr.Interpolator}
+	if err := json.Unmarshal(data, &alias); err != nil {
+		return err
+	}
+	if alias.Interpolator == nil {
+		interpolator, err := defaultInterpolator[T]()
+		if err != nil {
+			return err
+		}
+		alias.Interpolator = interpolator
+	}
+	sortKeys(alias.Keys)
+	*tr = Track[T](alias)
+	return nil
+}
+
+// defaultInterpolator returns the Interpolator for T, or an error if T has none
+func defaultInterpolator[T any]() (Interpolator[T], error) {
+	var zero T
+	var interpolator any
+	switch any(zero).(type) {
+	case float32:
+		interpolator = FloatInterpolator{}
+	case vec2.F:
+		interpolator = Vec2Interpolator{}
+	case vec3.F:
+		interpolator = Vec3Interpolator{}
+	default:
+		return nil, fmt.Errorf("Track of %T has no default Interpolator", zero)
+	}
+	return interpolator.(Interpolator[T]), nil
+}
@@ -0,0 +1,174 @@
+package lerp
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/Lundis/go-gmath/easings"
+	"github.com/Lundis/go-gmath/vec2"
+	"github.com/Lundis/go-gmath/vec3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampleKeyframes(t *testing.T) {
+	keyFrames := []vec2.F{{X: 1, Y: 10}, {X: 2, Y: 20}, {X: 4, Y: 0}}
+	assert.Equal(t, float32(10), SampleKeyframes(keyFrames, 0))
+	assert.Equal(t, float32(10), SampleKeyframes(keyFrames, 1))
+	assert.Equal(t, float32(15), SampleKeyframes(keyFrames, 1.5))
+	assert.Equal(t, float32(10), SampleKeyframes(keyFrames, 3))
+	assert.Equal(t, float32(0), SampleKeyframes(keyFrames, 5))
+	assert.Equal(t, float32(0), SampleKeyframes(nil, 5))
+}
+
+func testTrack() *Track[float32] {
+	return NewTrackFloat([]Key[float32]{
+		{Time: 2, Value: 20},
+		{Time: 0, Value: 0},
+		{Time: 1, Value: 10},
+	})
+}
+
+func TestTrackLinear(t *testing.T) {
+	tr := testTrack()
+	assert.Equal(t, float32(0), tr.Start())
+	assert.Equal(t, float32(2), tr.Duration())
+	for _, x := range []float32{0, 0.5, 1, 1.25, 2} {
+		assert.InDelta(t, 10*x, tr.Sample(x), 0.0001)
+	}
+	// going backwards works despite the cursor
+	assert.InDelta(t, 3, tr.Sample(0.3), 0.0001)
+}
+
+func TestTrackWrapModes(t *testing.T) {
+	tr := testTrack()
+	assert.Equal(t, float32(0), tr.Sample(-1))
+	assert.Equal(t, float32(20), tr.Sample(3))
+
+	tr.Pre, tr.Post = Loop, Loop
+	assert.InDelta(t, 5, tr.Sample(2.5), 0.0001)
+	assert.InDelta(t, 15, tr.Sample(-0.5), 0.0001)
+	assert.InDelta(t, 12, tr.Sample(7.2), 0.0001)
+
+	tr.Pre, tr.Post = PingPong, PingPong
+	assert.InDelta(t, 15, tr.Sample(2.5), 0.0001)
+	assert.InDelta(t, 5, tr.Sample(4.5), 0.0001)
+	assert.InDelta(t, 5, tr.Sample(-0.5), 0.0001)
+
+	tr.Pre, tr.Post = Extrapolate, Extrapolate
+	assert.InDelta(t, 30, tr.Sample(3), 0.0001)
+	assert.InDelta(t, -10, tr.Sample(-1), 0.0001)
+}
+
+func TestTrackInterpolation(t *testing.T) {
+	tr := testTrack()
+	tr.Keys[0].Interpolation = Step
+	assert.Equal(t, float32(0), tr.Sample(0.9))
+	assert.Equal(t, float32(10), tr.Sample(1))
+
+	tr.Keys[1].Easing, _ = easings.NewNamed("easeInQuad")
+	assert.InDelta(t, 12.5, tr.Sample(1.5), 0.0001)
+
+	// cubic keys on a straight line stay on it
+	tr = testTrack()
+	for i := range tr.Keys {
+		tr.Keys[i].Interpolation = Cubic
+	}
+	for _, x := range []float32{0.2, 0.5, 1.3, 1.9} {
+		assert.InDelta(t, 10*x, tr.Sample(x), 0.0001)
+	}
+
+	// cubic curves are smooth through the keys
+	tr = NewTrackFloat([]Key[float32]{
+		{Time: 0, Value: 0, Interpolation: Cubic},
+		{Time: 1, Value: 10, Interpolation: Cubic},
+		{Time: 3, Value: 0, Interpolation: Cubic},
+	})
+	const h = 0.001
+	before := (tr.Sample(1) - tr.Sample(1-h)) / h
+	after := (tr.Sample(1+h) - tr.Sample(1)) / h
+	assert.InDelta(t, before, after, 0.05)
+	assert.Greater(t, tr.Sample(1.2), float32(9))
+}
+
+func TestTrackTypes(t *testing.T) {
+	tr2 := NewTrack2([]Key[vec2.F]{{Time: 0, Value: vec2.F{X: 0, Y: 0}}, {Time: 1, Value: vec2.F{X: 2, Y: 4}}})
+	assert.Equal(t, vec2.F{X: 1, Y: 2}, tr2.Sample(0.5))
+
+	tr3 := NewTrack3([]Key[vec3.F]{
+		{Time: 0, Value: vec3.F{}, Interpolation: Cubic},
+		{Time: 1, Value: vec3.F{X: 2, Y: 4, Z: 6}},
+	})
+	assert.Equal(t, vec3.F{X: 1, Y: 2, Z: 3}, tr3.Sample(0.5))
+
+	angles := NewTrackAngle([]Key[float32]{{Time: 0, Value: 3}, {Time: 1, Value: -3}})
+	// the short way crosses Pi
+	mid := angles.Sample(0.5)
+	assert.InDelta(t, math.Pi, mid, 0.0001)
+}
+
+func TestTrackJSON(t *testing.T) {
+	data := `{
+		"keys": [
+			{"time": 1, "value": [10, 0], "easing": "easeOutQuad"},
+			{"time": 0, "value": [0, 0], "interpolation": "step"},
+			{"time": 2, "value": [10, 10]}
+		],
+		"post": "pingPong"
+	}`
+	var tr Track[vec2.F]
+	assert.NoError(t, json.Unmarshal([]byte(data), &tr))
+	assert.Equal(t, float32(0), tr.Keys[0].Time)
+	assert.Equal(t, Step, tr.Keys[0].Interpolation)
+	assert.Equal(t, PingPong, tr.Post)
+	assert.Equal(t, vec2.F{X: 10, Y: 7.5}, tr.Sample(1.5))
+	assert.Equal(t, vec2.F{X: 10, Y: 7.5}, tr.Sample(2.5))
+
+	encoded, err := json.Marshal(&tr)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"keys": [
+			{"time": 0, "value": [0, 0], "interpolation": "step"},
+			{"time": 1, "value": [10, 0], "easing": "easeOutQuad"},
+			{"time": 2, "value": [10, 10]}
+		],
+		"post": "pingPong"
+	}`, string(encoded))
+
+	// the interpolator survives unmarshalling
+	angles := NewTrackAngle(nil)
+	assert.NoError(t, json.Unmarshal([]byte(`{"keys": [{"time": 0, "value": 3}, {"time": 1, "value": -3}]}`), angles))
+	assert.InDelta(t, math.Pi, angles.Sample(0.5), 0.0001)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"keys": [], "pre": "bounce"}`), &tr))
+	assert.Error(t, json.Unmarshal([]byte(`{"keys": [{"time": 0, "value": [0, 0], "interpolation": "quadratic"}]}`), &tr))
+
+	// other types need an interpolator
+	var ints Track[int]
+	assert.Error(t, json.Unmarshal([]byte(`{"keys": [{"time": 0, "value": 1}, {"time": 1, "value": 2}]}`), &ints))
+	assert.Panics(t, func() {
+		(&Track[int]{Keys: []Key[int]{{Time: 0, Value: 1}, {Time: 1, Value: 2}}}).Sample(0.5)
+	})
+}
+
+func TestTrackNoAllocations(t *testing.T) {
+	tr := testTrack()
+	tr.Post = Loop
+	allocs := testing.AllocsPerRun(10, func() {
+		for x := float32(0); x < 5; x += 0.1 {
+			tr.Sample(x)
+		}
+	})
+	assert.Equal(t, float64(0), allocs)
+}
+
+func BenchmarkTrackSequential(b *testing.B) {
+	keys := make([]Key[float32], 100)
+	for i := range keys {
+		keys[i] = Key[float32]{Time: float32(i), Value: float32(i % 7)}
+	}
+	tr := NewTrackFloat(keys)
+	for i := 0; i < b.N; i++ {
+		tr.Sample(float32(i%9900) / 100)
+	}
+}
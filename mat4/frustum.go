@@ -0,0 +1,102 @@
+package mat4
+
+import "github.com/Lundis/go-gmath/vec3"
+
+// Plane is the set of points p where Normal.Dot(p) + D == 0
+type Plane struct {
+	Normal vec3.F
+	D      float32
+}
+
+// Distance returns the signed distance from the plane to p. It's positive on the side Normal points towards.
+func (p Plane) Distance(point vec3.F) float32 {
+	return p.Normal.Dot(point) + p.D
+}
+
+func (p Plane) Normalized() Plane {
+	m := p.Normal.Magnitude()
+	if m == 0 {
+		return p
+	}
+	return Plane{Normal: p.Normal.DivScalar(m), D: p.D / m}
+}
+
+const (
+	PlaneLeft = iota
+	PlaneRight
+	PlaneBottom
+	PlaneTop
+	PlaneNear
+	PlaneFar
+)
+
+// Frustum holds the six clipping planes of a view volume, indexed by the Plane* constants.
+// All normals point into the volume.
+type Frustum [6]Plane
+
+// NewFrustum extracts the clipping planes from a combined projection * view matrix.
+// The planes are in world space, or in the space the matrix transforms from.
+func NewFrustum(viewProjection F) Frustum {
+	m := viewProjection
+	row := func(i int) (float32, float32, float32, float32) {
+		return m[i], m[4+i], m[8+i], m[12+i]
+	}
+	r0x, r0y, r0z, r0w := row(0)
+	r1x, r1y, r1z, r1w := row(1)
+	r2x, r2y, r2z, r2w := row(2)
+	r3x, r3y, r3z, r3w := row(3)
+
+	plane := func(x, y, z, w float32) Plane {
+		return Plane{Normal: vec3.F{X: x, Y: y, Z: z}, D: w}.Normalized()
+	}
+	var f Frustum
+	f[PlaneLeft] = plane(r3x+r0x, r3y+r0y, r3z+r0z, r3w+r0w)
+	f[PlaneRight] = plane(r3x-r0x, r3y-r0y, r3z-r0z, r3w-r0w)
+	f[PlaneBottom] = plane(r3x+r1x, r3y+r1y, r3z+r1z, r3w+r1w)
+	f[PlaneTop] = plane(r3x-r1x, r3y-r1y, r3z-r1z, r3w-r1w)
+	f[PlaneNear] = plane(r3x+r2x, r3y+r2y, r3z+r2z, r3w+r2w)
+	f[PlaneFar] = plane(r3x-r2x, r3y-r2y, r3z-r2z, r3w-r2w)
+	return f
+}
+
+func (f Frustum) ContainsPoint(p vec3.F) bool {
+	for _, plane := range f {
+		if plane.Distance(p) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IntersectsSphere returns true if the sphere is at least partially inside the frustum.
+// It may return true for some spheres near the corners that are actually outside.
+func (f Frustum) IntersectsSphere(center vec3.F, radius float32) bool {
+	for _, plane := range f {
+		if plane.Distance(center) < -radius {
+			return false
+		}
+	}
+	return true
+}
+
+// IntersectsAABB returns true if the axis-aligned box between low and high is at least partially inside the frustum.
+// It may return true for some boxes near the corners that are actually outside.
+func (f Frustum) IntersectsAABB(low, high vec3.F) bool {
+	for _, plane := range f {
+		// test the corner furthest along the plane normal
+		p := low
+		if plane.Normal.X >= 0 {
+			p.X = high.X
+		}
+		if plane.Normal.Y >= 0 {
+			p.Y = high.Y
+		}
+		if plane.Normal.Z >= 0 {
+			p.Z = high.Z
+		}
+		if plane.Distance(p) < 0 {
+			return false
+		}
+	}
+	return true
+}
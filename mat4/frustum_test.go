@@ -0,0 +1,45 @@
+package mat4
+
+import (
+	"math"
+	"testing"
+
+	"github.com/Lundis/go-gmath/vec3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlaneDistance(t *testing.T) {
+	p := Plane{Normal: vec3.F{Y: 2}, D: -4}.Normalized()
+	assert.InDelta(t, 3, p.Distance(vec3.F{X: 7, Y: 5}), 0.0001)
+	assert.InDelta(t, -2, p.Distance(vec3.F{Y: 0}), 0.0001)
+}
+
+func TestFrustumPerspective(t *testing.T) {
+	proj := NewPerspective(math.Pi/2, 1, 1, 100)
+	view := NewLookAt(vec3.F{Z: -10}, vec3.F{}, vec3.F{Y: -1})
+	f := NewFrustum(proj.Mul(view))
+
+	assert.True(t, f.ContainsPoint(vec3.F{}))
+	assert.False(t, f.ContainsPoint(vec3.F{Z: -20}))
+	assert.False(t, f.ContainsPoint(vec3.F{Z: 100}))
+	assert.False(t, f.ContainsPoint(vec3.F{X: 15}))
+	assert.True(t, f.ContainsPoint(vec3.F{X: 9}))
+
+	assert.InDelta(t, 1, f[PlaneNear].Distance(vec3.F{Z: -8}), 0.001)
+	assert.InDelta(t, 80, f[PlaneFar].Distance(vec3.F{Z: 10}), 0.01)
+
+	assert.True(t, f.IntersectsSphere(vec3.F{X: 12}, 3))
+	assert.False(t, f.IntersectsSphere(vec3.F{X: 12}, 1))
+
+	assert.True(t, f.IntersectsAABB(vec3.F{X: 9, Y: -1, Z: -1}, vec3.F{X: 20, Y: 1, Z: 1}))
+	assert.False(t, f.IntersectsAABB(vec3.F{X: 15, Y: -1, Z: -1}, vec3.F{X: 20, Y: 1, Z: 1}))
+}
+
+func TestFrustumOrtho(t *testing.T) {
+	f := NewFrustum(NewOrtho(0, 800, 0, 600, -1, 1))
+	assert.True(t, f.ContainsPoint(vec3.F{X: 10, Y: 10}))
+	assert.False(t, f.ContainsPoint(vec3.F{X: 10, Y: -10}))
+	assert.True(t, f[PlaneTop].Distance(vec3.F{Y: 10}) > 0)
+	assert.InDelta(t, 10, f[PlaneTop].Distance(vec3.F{Y: 10}), 0.001)
+	assert.InDelta(t, 590, f[PlaneBottom].Distance(vec3.F{Y: 10}), 0.01)
+}
@@ -0,0 +1,210 @@
+package mat4
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Lundis/go-gmath/vec2"
+	"github.com/Lundis/go-gmath/vec3"
+)
+
+// F is a 4x4 float32 matrix in column-major order, so element (row, col) is stored at index col*4 + row.
+// Vectors are treated as columns and multiplied from the right.
+type F [16]float32
+
+func NewIdentity() F {
+	return F{
+		1, 0, 0, 0,
+		0, 1, 0, 0,
+		0, 0, 1, 0,
+		0, 0, 0, 1,
+	}
+}
+
+func NewTranslation(offset vec3.F) F {
+	m := NewIdentity()
+	m[12], m[13], m[14] = offset.X, offset.Y, offset.Z
+	return m
+}
+
+func NewScale(scale vec3.F) F {
+	return F{
+		scale.X, 0, 0, 0,
+		0, scale.Y, 0, 0,
+		0, 0, scale.Z, 0,
+		0, 0, 0, 1,
+	}
+}
+
+// NewRotation creates a rotation matrix from q, which must be normalized
+func NewRotation(q vec3.Quat) F {
+	return q.Matrix4()
+}
+
+// NewTRS creates a matrix that scales, then rotates and finally translates
+func NewTRS(translation vec3.F, rotation vec3.Quat, scale vec3.F) F {
+	m := rotation.Matrix4()
+	for i := 0; i < 3; i++ {
+		m[i] *= scale.X
+		m[4+i] *= scale.Y
+		m[8+i] *= scale.Z
+	}
+	m[12], m[13], m[14] = translation.X, translation.Y, translation.Z
+	return m
+}
+
+// NewFromAffine2 embeds a 2D transform in the XY plane
+func NewFromAffine2(a vec2.Affine2) F {
+	return F{
+		a.M00, a.M10, 0, 0,
+		a.M01, a.M11, 0, 0,
+		0, 0, 1, 0,
+		a.M02, a.M12, 0, 1,
+	}
+}
+
+func (m F) At(row, col int) float32 {
+	return m[col*4+row]
+}
+
+func (m F) Set(row, col int, value float32) F {
+	m[col*4+row] = value
+	return m
+}
+
+func (m F) Equals(other F) bool {
+	return m == other
+}
+
+func (m F) String() string {
+	var sb strings.Builder
+	for row := 0; row < 4; row++ {
+		sb.WriteString("[")
+		for col := 0; col < 4; col++ {
+			if col > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(strconv.FormatFloat(float64(m.At(row, col)), 'f', 4, 32))
+		}
+		sb.WriteString("]")
+		if row < 3 {
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// Mul returns the matrix product m * other, i.e. a transform that first applies other and then m
+func (m F) Mul(other F) F {
+	var res F
+	for col := 0; col < 4; col++ {
+		b0, b1, b2, b3 := other[col*4], other[col*4+1], other[col*4+2], other[col*4+3]
+		res[col*4] = m[0]*b0 + m[4]*b1 + m[8]*b2 + m[12]*b3
+		res[col*4+1] = m[1]*b0 + m[5]*b1 + m[9]*b2 + m[13]*b3
+		res[col*4+2] = m[2]*b0 + m[6]*b1 + m[10]*b2 + m[14]*b3
+		res[col*4+3] = m[3]*b0 + m[7]*b1 + m[11]*b2 + m[15]*b3
+	}
+	return res
+}
+
+func (m F) Transpose() F {
+	return F{
+		m[0], m[4], m[8], m[12],
+		m[1], m[5], m[9], m[13],
+		m[2], m[6], m[10], m[14],
+		m[3], m[7], m[11], m[15],
+	}
+}
+
+// cofactors calculates the 2x2 sub-determinants shared by Determinant and Inverse.
+// The formula is written for row-major matrices. Applied to the column-major storage it inverts the transpose, and as the
+// inverse of the transpose is the transpose of the inverse, Inverse writes out the column-major inverse directly.
+func (m F) cofactors() (s [6]float32, c [6]float32) {
+	s[0] = m[0]*m[5] - m[4]*m[1]
+	s[1] = m[0]*m[6] - m[4]*m[2]
+	s[2] = m[0]*m[7] - m[4]*m[3]
+	s[3] = m[1]*m[6] - m[5]*m[2]
+	s[4] = m[1]*m[7] - m[5]*m[3]
+	s[5] = m[2]*m[7] - m[6]*m[3]
+
+	c[5] = m[10]*m[15] - m[14]*m[11]
+	c[4] = m[9]*m[15] - m[13]*m[11]
+	c[3] = m[9]*m[14] - m[13]*m[10]
+	c[2] = m[8]*m[15] - m[12]*m[11]
+	c[1] = m[8]*m[14] - m[12]*m[10]
+	c[0] = m[8]*m[13] - m[12]*m[9]
+	return
+}
+
+func (m F) Determinant() float32 {
+	s, c := m.cofactors()
+	return s[0]*c[5] - s[1]*c[4] + s[2]*c[3] + s[3]*c[2] - s[4]*c[1] + s[5]*c[0]
+}
+
+// Inverse returns the inverse matrix. ok is false if m is not invertible.
+func (m F) Inverse() (inverse F, ok bool) {
+	s, c := m.cofactors()
+	det := s[0]*c[5] - s[1]*c[4] + s[2]*c[3] + s[3]*c[2] - s[4]*c[1] + s[5]*c[0]
+	if det == 0 {
+		return F{}, false
+	}
+	invDet := 1 / det
+
+	inverse[0] = (m[5]*c[5] - m[6]*c[4] + m[7]*c[3]) * invDet
+	inverse[1] = (-m[1]*c[5] + m[2]*c[4] - m[3]*c[3]) * invDet
+	inverse[2] = (m[13]*s[5] - m[14]*s[4] + m[15]*s[3]) * invDet
+	inverse[3] = (-m[9]*s[5] + m[10]*s[4] - m[11]*s[3]) * invDet
+
+	inverse[4] = (-m[4]*c[5] + m[6]*c[2] - m[7]*c[1]) * invDet
+	inverse[5] = (m[0]*c[5] - m[2]*c[2] + m[3]*c[1]) * invDet
+	inverse[6] = (-m[12]*s[5] + m[14]*s[2] - m[15]*s[1]) * invDet
+	inverse[7] = (m[8]*s[5] - m[10]*s[2] + m[11]*s[1]) * invDet
+
+	inverse[8] = (m[4]*c[4] - m[5]*c[2] + m[7]*c[0]) * invDet
+	inverse[9] = (-m[0]*c[4] + m[1]*c[2] - m[3]*c[0]) * invDet
+	inverse[10] = (m[12]*s[4] - m[13]*s[2] + m[15]*s[0]) * invDet
+	inverse[11] = (-m[8]*s[4] + m[9]*s[2] - m[11]*s[0]) * invDet
+
+	inverse[12] = (-m[4]*c[3] + m[5]*c[1] - m[6]*c[0]) * invDet
+	inverse[13] = (m[0]*c[3] - m[1]*c[1] + m[2]*c[0]) * invDet
+	inverse[14] = (-m[12]*s[3] + m[13]*s[1] - m[14]*s[0]) * invDet
+	inverse[15] = (m[8]*s[3] - m[9]*s[1] + m[10]*s[0]) * invDet
+	return inverse, true
+}
+
+func (m F) Translation() vec3.F {
+	return vec3.F{X: m[12], Y: m[13], Z: m[14]}
+}
+
+// TransformPoint transforms p as a point (w = 1), including the perspective division for projection matrices
+func (m F) TransformPoint(p vec3.F) vec3.F {
+	res := vec3.F{
+		X: m[0]*p.X + m[4]*p.Y + m[8]*p.Z + m[12],
+		Y: m[1]*p.X + m[5]*p.Y + m[9]*p.Z + m[13],
+		Z: m[2]*p.X + m[6]*p.Y + m[10]*p.Z + m[14],
+	}
+	w := m[3]*p.X + m[7]*p.Y + m[11]*p.Z + m[15]
+	if w != 1 && w != 0 {
+		res = res.DivScalar(w)
+	}
+	return res
+}
+
+// TransformDirection transforms d as a direction (w = 0), so translation is ignored
+func (m F) TransformDirection(d vec3.F) vec3.F {
+	return vec3.F{
+		X: m[0]*d.X + m[4]*d.Y + m[8]*d.Z,
+		Y: m[1]*d.X + m[5]*d.Y + m[9]*d.Z,
+		Z: m[2]*d.X + m[6]*d.Y + m[10]*d.Z,
+	}
+}
+
+// TransformPoints transforms every point in src and stores the result in dst, which must be at least as long as src.
+// dst and src may be the same slice. Returns dst[:len(src)].
+func (m F) TransformPoints(dst, src []vec3.F) []vec3.F {
+	dst = dst[:len(src)]
+	for i, p := range src {
+		dst[i] = m.TransformPoint(p)
+	}
+	return dst
+}
@@ -0,0 +1,129 @@
+package mat4
+
+import (
+	"math"
+	"testing"
+
+	"github.com/Lundis/go-gmath/vec2"
+	"github.com/Lundis/go-gmath/vec3"
+	"github.com/stretchr/testify/assert"
+)
+
+func assertFInDelta(t *testing.T, expected, actual vec3.F) {
+	t.Helper()
+	assert.InDelta(t, expected.X, actual.X, 0.001)
+	assert.InDelta(t, expected.Y, actual.Y, 0.001)
+	assert.InDelta(t, expected.Z, actual.Z, 0.001)
+}
+
+func assertMatInDelta(t *testing.T, expected, actual F) {
+	t.Helper()
+	for i := range expected {
+		assert.InDelta(t, expected[i], actual[i], 0.001, "index %d", i)
+	}
+}
+
+func TestIdentity(t *testing.T) {
+	m := NewIdentity()
+	p := vec3.F{X: 1, Y: 2, Z: 3}
+	assert.Equal(t, p, m.TransformPoint(p))
+	assert.Equal(t, float32(1), m.Determinant())
+	assert.Equal(t, m, m.Mul(m))
+}
+
+func TestAt(t *testing.T) {
+	m := NewTranslation(vec3.F{X: 1, Y: 2, Z: 3})
+	assert.Equal(t, float32(1), m.At(0, 3))
+	assert.Equal(t, float32(3), m.At(2, 3))
+	assert.Equal(t, float32(5), m.Set(3, 0, 5)[3])
+}
+
+func TestTranslationScale(t *testing.T) {
+	m := NewTranslation(vec3.F{X: 1, Y: 2, Z: 3}).Mul(NewScale(vec3.F{X: 2, Y: 3, Z: 4}))
+	assert.Equal(t, vec3.F{X: 3, Y: 5, Z: 7}, m.TransformPoint(vec3.F{X: 1, Y: 1, Z: 1}))
+	assert.Equal(t, vec3.F{X: 2, Y: 3, Z: 4}, m.TransformDirection(vec3.F{X: 1, Y: 1, Z: 1}))
+	assert.Equal(t, vec3.F{X: 1, Y: 2, Z: 3}, m.Translation())
+	assert.Equal(t, float32(24), m.Determinant())
+}
+
+func TestTRS(t *testing.T) {
+	translation := vec3.F{X: 1, Y: -2, Z: 3}
+	rotation := vec3.NewQuatEuler(0.3, 1.2, -0.5)
+	scale := vec3.F{X: 2, Y: 0.5, Z: 3}
+	m := NewTRS(translation, rotation, scale)
+	expected := NewTranslation(translation).Mul(NewRotation(rotation)).Mul(NewScale(scale))
+	assertMatInDelta(t, expected, m)
+
+	p := vec3.F{X: 4, Y: 5, Z: 6}
+	assertFInDelta(t, rotation.Rotate(p.Mul(scale)).Add(translation), m.TransformPoint(p))
+}
+
+func TestInverse(t *testing.T) {
+	m := NewTRS(vec3.F{X: 1, Y: -2, Z: 3}, vec3.NewQuatEuler(0.3, 1.2, -0.5), vec3.F{X: 2, Y: 0.5, Z: 3})
+	inv, ok := m.Inverse()
+	assert.True(t, ok)
+	assertMatInDelta(t, NewIdentity(), m.Mul(inv))
+	assertMatInDelta(t, NewIdentity(), inv.Mul(m))
+
+	proj := NewPerspective(1, 1.5, 0.1, 100)
+	inv, ok = proj.Inverse()
+	assert.True(t, ok)
+	assertMatInDelta(t, NewIdentity(), proj.Mul(inv))
+
+	_, ok = NewScale(vec3.F{X: 1, Y: 0, Z: 1}).Inverse()
+	assert.False(t, ok)
+}
+
+func TestTranspose(t *testing.T) {
+	m := NewTranslation(vec3.F{X: 1, Y: 2, Z: 3})
+	tr := m.Transpose()
+	assert.Equal(t, float32(1), tr[3])
+	assert.Equal(t, m, tr.Transpose())
+	assert.InDelta(t, m.Determinant(), tr.Determinant(), 0.0001)
+}
+
+func TestFromAffine2(t *testing.T) {
+	a := vec2.NewAffine2TRS(vec2.F{X: 3, Y: 4}, 0.5, vec2.F{X: 2, Y: 1})
+	m := NewFromAffine2(a)
+	p := a.TransformPoint(vec2.F{X: 1, Y: 2})
+	assertFInDelta(t, vec3.F{X: p.X, Y: p.Y, Z: 7}, m.TransformPoint(vec3.F{X: 1, Y: 2, Z: 7}))
+}
+
+func TestTransformPoints(t *testing.T) {
+	m := NewTranslation(vec3.F{X: 1})
+	points := []vec3.F{{}, {X: 1}}
+	m.TransformPoints(points, points)
+	assert.Equal(t, []vec3.F{{X: 1}, {X: 2}}, points)
+}
+
+func TestOrtho(t *testing.T) {
+	m := NewOrtho(0, 800, 0, 600, -1, 1)
+	// Y down: the top left corner of the screen is at NDC (-1, 1)
+	assertFInDelta(t, vec3.F{X: -1, Y: 1, Z: 0}, m.TransformPoint(vec3.F{}))
+	assertFInDelta(t, vec3.F{X: 1, Y: -1, Z: 0}, m.TransformPoint(vec3.F{X: 800, Y: 600}))
+	assertFInDelta(t, vec3.F{X: 0, Y: 0, Z: -1}, m.TransformPoint(vec3.F{X: 400, Y: 300, Z: -1}))
+}
+
+func TestPerspective(t *testing.T) {
+	near, far := float32(1), float32(100)
+	m := NewPerspective(math.Pi/2, 2, near, far)
+	assert.InDelta(t, -1, m.TransformPoint(vec3.F{Z: near}).Z, 0.001)
+	assert.InDelta(t, 1, m.TransformPoint(vec3.F{Z: far}).Z, 0.001)
+	// positive Y is down on the screen
+	assert.InDelta(t, -1, m.TransformPoint(vec3.F{Y: 10, Z: 10}).Y, 0.001)
+	assert.InDelta(t, 1, m.TransformPoint(vec3.F{X: 20, Z: 10}).X, 0.001)
+}
+
+func TestLookAt(t *testing.T) {
+	eye := vec3.F{X: 1, Y: -5, Z: -10}
+	target := vec3.F{X: 1, Y: -5, Z: 0}
+	view := NewLookAt(eye, target, vec3.F{Y: -1})
+	assertFInDelta(t, vec3.F{Z: 10}, view.TransformPoint(target))
+	// a point below the target in the world is below it on screen as well
+	assertFInDelta(t, vec3.F{Y: 2, Z: 10}, view.TransformPoint(target.AddScalars(0, 2, 0)))
+	assertFInDelta(t, vec3.F{X: 3, Z: 10}, view.TransformPoint(target.AddScalars(3, 0, 0)))
+
+	view = NewLookAt(vec3.F{}, vec3.F{X: 5}, vec3.F{Y: -1})
+	assertFInDelta(t, vec3.F{Z: 5}, view.TransformPoint(vec3.F{X: 5}))
+	assert.InDelta(t, 1, view.Determinant(), 0.001)
+}
@@ -0,0 +1,53 @@
+package mat4
+
+import (
+	"math"
+
+	"github.com/Lundis/go-gmath/vec3"
+)
+
+// The projections below follow the library's convention where Y points down.
+// View space has X to the right, Y down and the camera looking along +Z.
+// The results are in OpenGL clip space, where NDC Y points up and depth is in the range [-1, 1].
+
+// NewOrtho creates an orthographic projection that maps left/right to the horizontal edges of the screen,
+// top/bottom to the vertical edges and near/far to the depth range.
+// With Y pointing down, top is usually smaller than bottom, e.g. NewOrtho(0, width, 0, height, -1, 1).
+func NewOrtho(left, right, top, bottom, near, far float32) F {
+	var m F
+	m[0] = 2 / (right - left)
+	m[5] = 2 / (top - bottom)
+	m[10] = 2 / (far - near)
+	m[12] = -(right + left) / (right - left)
+	m[13] = -(top + bottom) / (top - bottom)
+	m[14] = -(far + near) / (far - near)
+	m[15] = 1
+	return m
+}
+
+// NewPerspective creates a perspective projection with a vertical field of view of fovY radians.
+// aspect is width / height. Points in front of the camera have a positive Z.
+func NewPerspective(fovY, aspect, near, far float32) F {
+	f := float32(1 / math.Tan(float64(fovY)/2))
+	var m F
+	m[0] = f / aspect
+	m[5] = -f
+	m[10] = (far + near) / (far - near)
+	m[11] = 1
+	m[14] = -2 * far * near / (far - near)
+	return m
+}
+
+// NewLookAt creates a view matrix for a camera at eye looking towards target.
+// up is the world direction that should point up on the screen, which is usually vec3.F{Y: -1}.
+func NewLookAt(eye, target, up vec3.F) F {
+	z := target.Sub(eye).Normalized()
+	x := up.MulScalar(-1).Cross(z).Normalized()
+	y := z.Cross(x)
+	return F{
+		x.X, y.X, z.X, 0,
+		x.Y, y.Y, z.Y, 0,
+		x.Z, y.Z, z.Z, 0,
+		-x.Dot(eye), -y.Dot(eye), -z.Dot(eye), 1,
+	}
+}
@@ -1,46 +1,29 @@
 package matheval
 
 import (
-	"errors"
 	"fmt"
-	"math"
-	"slices"
 	"strings"
 )
 
+// builtinFunc is a call to a function from a FunctionSet
 type builtinFunc struct {
 	id     string
+	fn     *Func
 	params []*Node
 }
 
-var oneParamFuncs = []string{"cos", "sin", "sqrt", "abs"}
-
-var twoParamFuncs = []string{"mod", "pow"}
-
-var anyParamFuncs = []string{"min", "max"}
-
-func newBuiltinFunc(id string, params []*Node) (*builtinFunc, error) {
-	expectedParams := 0
-	if slices.Index(oneParamFuncs, id) >= 0 {
-		expectedParams = 1
-	} else if slices.Index(twoParamFuncs, id) >= 0 {
-		expectedParams = 2
-	} else if slices.Index(anyParamFuncs, id) >= 0 {
-		expectedParams = 99
-	}
-	if expectedParams == 0 {
-		return nil, errors.New(fmt.Sprintf("Unknown function: %v", id))
-	}
-
-	if expectedParams <= 2 && len(params) != expectedParams {
-		return nil, fmt.Errorf("Wrong number of parameters for function %v: %v", id, len(params))
+func newBuiltinFunc(funcs *FunctionSet, id string, params []*Node) (*builtinFunc, error) {
+	fn, ok := funcs.Lookup(id)
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrUnknownFunction, id)
 	}
-	if expectedParams == 99 && len(params) < 2 {
-		return nil, fmt.Errorf("Function %v requires at least two parameters", id)
+	if err := fn.checkArity(len(params)); err != nil {
+		return nil, err
 	}
 
 	f := new(builtinFunc)
 	f.id = id
+	f.fn = fn
 	f.params = params
 	return f, nil
 }
@@ -60,41 +43,25 @@ func (self *builtinFunc) String() string {
 }
 
 func (self *builtinFunc) Evaluate(vars map[string]float64) float64 {
-	r := 0.0
-	params := make([]float64, len(self.params))
-	for i, n := range self.params {
-		params[i] = n.Evaluate(vars)
-	}
-	switch self.id {
-	case "cos":
-		r = math.Cos(params[0])
-	case "sin":
-		r = math.Sin(params[0])
-	case "abs":
-		r = math.Abs(params[0])
-	case "sqrt":
-		r = math.Sqrt(params[0])
-	case "mod":
-		r = math.Mod(params[0], params[1])
-	case "pow":
-		r = math.Pow(params[0], params[1])
-	case "min":
-		r := params[0]
+	fn, params := self.fn, self.params
+	switch {
+	case fn.f1 != nil:
+		return fn.f1(params[0].Evaluate(vars))
+	case fn.f2 != nil:
+		return fn.f2(params[0].Evaluate(vars), params[1].Evaluate(vars))
+	case fn.f3 != nil:
+		return fn.f3(params[0].Evaluate(vars), params[1].Evaluate(vars), params[2].Evaluate(vars))
+	case fn.fold != nil:
+		r := params[0].Evaluate(vars)
 		for _, p := range params[1:] {
-			if p < r {
-				r = p
-			}
-		}
-
-	case "max":
-		r := params[0]
-		for _, p := range params[1:] {
-			if p > r {
-				r = p
-			}
+			r = fn.fold(r, p.Evaluate(vars))
 		}
+		return r
 	default:
-		panic("not implemented")
+		args := make([]float64, len(params))
+		for i, p := range params {
+			args[i] = p.Evaluate(vars)
+		}
+		return fn.fn(args)
 	}
-	return r
 }
@@ -0,0 +1,248 @@
+package matheval
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+)
+
+// Program is an expression compiled for evaluating many times with different variables.
+// Variables are resolved to slot indices when compiling, and evaluation reads them from a []float64 without allocating.
+// A Program can be evaluated from several goroutines at once, as long as the functions it calls allow it.
+type Program struct {
+	eval  evalFunc
+	slots []string
+}
+
+type evalFunc func(vars []float64) float64
+
+// Compile compiles the expression rooted at node.
+// The variables in vars get the first slots in the given order, and any other variables used by the expression get the
+// following slots in the order they appear. The constants pi and e are folded unless they are listed in vars.
+func Compile(node *Node, vars ...string) *Program {
+	p := &Program{slots: slices.Clone(vars)}
+	p.eval, _ = p.compile(node)
+	return p
+}
+
+// CompileString parses and compiles expr
+func CompileString(expr string, vars ...string) (*Program, error) {
+	node, err := Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+	return Compile(node, vars...), nil
+}
+
+// Slots returns the names of the variables in slot order
+func (p *Program) Slots() []string {
+	return p.slots
+}
+
+// Slot returns the slot of the variable name, or -1 if the program doesn't have it
+func (p *Program) Slot(name string) int {
+	return slices.Index(p.slots, name)
+}
+
+// Evaluate evaluates the program. vars holds the value of each slot and must be at least len(p.Slots()) long.
+func (p *Program) Evaluate(vars []float64) float64 {
+	return p.eval(vars)
+}
+
+// Bind fills the slots from a map like the one passed to Node.Evaluate, and returns dst.
+// dst is reused if it has room for all slots. Variables missing from the map are 0.
+func (p *Program) Bind(vars map[string]float64, dst []float64) []float64 {
+	dst = slices.Grow(dst[:0], len(p.slots))[:len(p.slots)]
+	for i, name := range p.slots {
+		dst[i] = vars[name]
+	}
+	return dst
+}
+
+func (p *Program) slot(name string) int {
+	i := p.Slot(name)
+	if i < 0 {
+		i = len(p.slots)
+		p.slots = append(p.slots, name)
+	}
+	return i
+}
+
+// compile turns node into a closure. Subtrees without variables are folded into constants, except for calls to
+// functions outside the default library, which may give a different result every time like they do in Node.Evaluate.
+func (p *Program) compile(node *Node) (f evalFunc, constant bool) {
+	f, constant = p.compileNode(node)
+	if constant {
+		v := f(nil)
+		return func([]float64) float64 { return v }, true
+	}
+	return f, false
+}
+
+func (p *Program) compileNode(node *Node) (evalFunc, bool) {
+	switch node.op {
+	case PLUS:
+		children, constant := p.compileAll(node.nodes)
+		if len(children) == 2 {
+			a, b := children[0], children[1]
+			return func(vars []float64) float64 { return a(vars) + b(vars) }, constant
+		}
+		return func(vars []float64) float64 {
+			sum := 0.0
+			for _, c := range children {
+				sum += c(vars)
+			}
+			return sum
+		}, constant
+	case MINUS:
+		a, constant := p.compile(node.nodes[0])
+		return func(vars []float64) float64 { return -a(vars) }, constant
+	case MULT:
+		children, constant := p.compileAll(node.nodes)
+		if len(children) == 2 {
+			a, b := children[0], children[1]
+			return func(vars []float64) float64 { return a(vars) * b(vars) }, constant
+		}
+		return func(vars []float64) float64 {
+			prod := 1.0
+			for _, c := range children {
+				prod *= c(vars)
+			}
+			return prod
+		}, constant
+	case DIV:
+		children, constant := p.compileAll(node.nodes)
+		a, b := children[0], children[1]
+		return func(vars []float64) float64 { return divide(a(vars), b(vars)) }, constant
+	case POW:
+		children, constant := p.compileAll(node.nodes)
+		a, b := children[0], children[1]
+		return func(vars []float64) float64 { return math.Pow(a(vars), b(vars)) }, constant
+	case LT, LE, GT, GE, EQ, NE:
+		children, constant := p.compileAll(node.nodes)
+		return compileComparison(node.op, children[0], children[1]), constant
+	case AND:
+		children, constant := p.compileAll(node.nodes)
+		a, b := children[0], children[1]
+		return func(vars []float64) float64 { return boolValue(a(vars) != 0 && b(vars) != 0) }, constant
+	case OR:
+		children, constant := p.compileAll(node.nodes)
+		a, b := children[0], children[1]
+		return func(vars []float64) float64 { return boolValue(a(vars) != 0 || b(vars) != 0) }, constant
+	case NOT:
+		a, constant := p.compile(node.nodes[0])
+		return func(vars []float64) float64 { return boolValue(a(vars) == 0) }, constant
+	case COND:
+		condition, constant := p.compile(node.nodes[0])
+		a, aConstant := p.compile(node.nodes[1])
+		b, bConstant := p.compile(node.nodes[2])
+		if constant {
+			// only one of the branches can be taken
+			if condition(nil) != 0 {
+				return a, aConstant
+			}
+			return b, bConstant
+		}
+		return func(vars []float64) float64 {
+			if condition(vars) != 0 {
+				return a(vars)
+			}
+			return b(vars)
+		}, false
+	case ATOM:
+		return p.compileLiteral(node.data.(*Literal))
+	case FUNC:
+		return p.compileFunc(node.data.(*builtinFunc))
+	default:
+		panic(fmt.Sprintf("Unknown NodeImpl type in Compile(): %v", node.op))
+	}
+}
+
+func compileComparison(op Operator, a, b evalFunc) evalFunc {
+	switch op {
+	case LT:
+		return func(vars []float64) float64 { return boolValue(a(vars) < b(vars)) }
+	case LE:
+		return func(vars []float64) float64 { return boolValue(a(vars) <= b(vars)) }
+	case GT:
+		return func(vars []float64) float64 { return boolValue(a(vars) > b(vars)) }
+	case GE:
+		return func(vars []float64) float64 { return boolValue(a(vars) >= b(vars)) }
+	case EQ:
+		return func(vars []float64) float64 { return boolValue(a(vars) == b(vars)) }
+	default:
+		return func(vars []float64) float64 { return boolValue(a(vars) != b(vars)) }
+	}
+}
+
+func (p *Program) compileAll(nodes []*Node) (fs []evalFunc, constant bool) {
+	fs = make([]evalFunc, len(nodes))
+	constant = true
+	for i, n := range nodes {
+		var c bool
+		fs[i], c = p.compile(n)
+		constant = constant && c
+	}
+	return fs, constant
+}
+
+func (p *Program) compileLiteral(l *Literal) (evalFunc, bool) {
+	if l.variable == "" {
+		v := l.val
+		return func([]float64) float64 { return v }, true
+	}
+	if p.Slot(l.variable) < 0 {
+		if v, ok := commonConstants[l.variable]; ok {
+			return func([]float64) float64 { return v }, true
+		}
+	}
+	i := p.slot(l.variable)
+	return func(vars []float64) float64 { return vars[i] }, false
+}
+
+func (p *Program) compileFunc(f *builtinFunc) (evalFunc, bool) {
+	params, constant := p.compileAll(f.params)
+	fn := f.fn
+	constant = constant && isDefaultFunc(fn)
+	switch {
+	case fn.f1 != nil:
+		a := params[0]
+		return func(vars []float64) float64 { return fn.f1(a(vars)) }, constant
+	case fn.f2 != nil:
+		a, b := params[0], params[1]
+		return func(vars []float64) float64 { return fn.f2(a(vars), b(vars)) }, constant
+	case fn.f3 != nil:
+		a, b, c := params[0], params[1], params[2]
+		return func(vars []float64) float64 { return fn.f3(a(vars), b(vars), c(vars)) }, constant
+	case fn.fold != nil:
+		return func(vars []float64) float64 {
+			r := params[0](vars)
+			for _, param := range params[1:] {
+				r = fn.fold(r, param(vars))
+			}
+			return r
+		}, constant
+	default:
+		buffers := newArgPool(len(params))
+		return func(vars []float64) float64 {
+			buf := buffers.Get().(*[]float64)
+			args := *buf
+			for i, param := range params {
+				args[i] = param(vars)
+			}
+			r := fn.fn(args)
+			buffers.Put(buf)
+			return r
+		}, constant
+	}
+}
+
+// newArgPool creates a pool of n long argument buffers for the calls of a RegisterVariadic function, which are shared
+// between evaluations, including concurrent ones
+func newArgPool(n int) *sync.Pool {
+	return &sync.Pool{New: func() any {
+		args := make([]float64, n)
+		return &args
+	}}
+}
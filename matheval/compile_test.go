@@ -0,0 +1,157 @@
+package matheval
+
+import (
+	"math"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/Lundis/go-gmath/fastmath"
+)
+
+var compileTestVars = map[string]float64{
+	"a":     5,
+	"b":     -23,
+	"x":     0.75,
+	"level": 12,
+}
+
+func TestCompileMatchesEvaluate(t *testing.T) {
+	exprs := []string{
+		"1 + 2 + 3",
+		"a + b",
+		"a - b - x",
+		"a * b * x",
+		"a / b",
+		"x / 0",
+		"-x / 0",
+		"(a + b) / (x * 2)",
+		"pi * x + e",
+		"abs(b) + sqrt(a) + sin(x) + cos(x)",
+		"pow(x, 2) + mod(a, 3)",
+		"min(a, b, x) + max(a, b, x)",
+		"level * 1.5 + pow(level, 2) / (a + 10)",
+		"missing + 1",
+	}
+	for _, expr := range exprs {
+		node, err := Parse(expr)
+		if err != nil {
+			t.Fatalf("Failed to parse %v: %v", expr, err)
+		}
+		p := Compile(node)
+		expected := node.Evaluate(compileTestVars)
+		result := p.Evaluate(p.Bind(compileTestVars, nil))
+		if !fastmath.Equald(result, expected, 0.0001) && !(math.IsInf(result, 0) && result == expected) {
+			t.Errorf("Compiled %v. Got %v. Expected %v", expr, result, expected)
+		}
+	}
+}
+
+func TestCompileSlots(t *testing.T) {
+	p, err := CompileString("c + a * b + a", "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(p.Slots(), []string{"b", "c", "a"}) {
+		t.Errorf("Unexpected slots %v", p.Slots())
+	}
+	if p.Slot("a") != 2 || p.Slot("d") != -1 {
+		t.Errorf("Unexpected slot lookup: a=%v, d=%v", p.Slot("a"), p.Slot("d"))
+	}
+	if result := p.Evaluate([]float64{2, 3, 4}); result != 3+4*2+4 {
+		t.Errorf("Expected %v, got %v", 3+4*2+4, result)
+	}
+}
+
+func TestCompileConstants(t *testing.T) {
+	p, _ := CompileString("2 * pi")
+	if len(p.Slots()) != 0 || p.Evaluate(nil) != 2*math.Pi {
+		t.Errorf("Expected pi to be folded, got slots %v", p.Slots())
+	}
+	// listing a constant makes it a variable, like passing it to Node.Evaluate
+	p, _ = CompileString("2 * pi", "pi")
+	if result := p.Evaluate([]float64{3}); result != 6 {
+		t.Errorf("Expected 6, got %v", result)
+	}
+}
+
+func TestCompileImpureFunctions(t *testing.T) {
+	calls := 0.0
+	funcs := DefaultFunctions()
+	funcs.RegisterVariadic("counter", 0, 0, func([]float64) float64 {
+		calls++
+		return calls
+	})
+	node, err := Parser{Functions: funcs}.Parse("counter() + sqrt(4)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := Compile(node)
+	for i := 1.0; i <= 3; i++ {
+		if result := p.Evaluate(nil); result != i+2 {
+			t.Errorf("Expected %v, got %v", i+2, result)
+		}
+	}
+	if result := node.Evaluate(nil); result != 6 {
+		t.Errorf("Expected 6, got %v", result)
+	}
+}
+
+func TestCompileConcurrent(t *testing.T) {
+	funcs := DefaultFunctions()
+	funcs.RegisterVariadic("sum", 1, Variadic, func(args []float64) float64 {
+		total := 0.0
+		for _, a := range args {
+			total += a
+		}
+		return total
+	})
+	node, err := Parser{Functions: funcs}.Parse("sum(x, 2 * x, 3 * x) + max(x, 0)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := Compile(node, "x")
+	var wg sync.WaitGroup
+	for g := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			x := float64(g)
+			for range 1000 {
+				if result := p.Evaluate([]float64{x}); result != 7*x {
+					t.Errorf("Expected %v, got %v", 7*x, result)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestCompileNoAllocations(t *testing.T) {
+	p, _ := CompileString("level * 1.5 + pow(level, 2) / (a + 10) - min(a, b, x)")
+	vars := p.Bind(compileTestVars, nil)
+	allocs := testing.AllocsPerRun(100, func() {
+		p.Evaluate(vars)
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocations, got %v", allocs)
+	}
+}
+
+const benchmarkExpr = "level * 1.5 + pow(level, 2) / (a + 10) - min(a, b, x) + sin(x * pi)"
+
+func BenchmarkNodeEvaluate(b *testing.B) {
+	node, _ := Parse(benchmarkExpr)
+	for b.Loop() {
+		node.Evaluate(compileTestVars)
+	}
+}
+
+func BenchmarkProgramEvaluate(b *testing.B) {
+	p, _ := CompileString(benchmarkExpr)
+	vars := p.Bind(compileTestVars, nil)
+	for b.Loop() {
+		p.Evaluate(vars)
+	}
+}
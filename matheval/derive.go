@@ -0,0 +1,235 @@
+package matheval
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrNotDifferentiable is returned by Derive for expressions that call functions without a known derivative
+var ErrNotDifferentiable = errors.New("Not differentiable")
+
+// Derive returns the simplified derivative of the expression with respect to variable.
+// All operators and the functions in the default library are supported. Piecewise functions such as abs, min, max
+// and clamp use the derivative of the active piece, and take the average of the pieces where they meet, as hypot does
+// at the origin.
+// Conditionals use the derivative of the branch they take, and comparisons and logical operators have a derivative of 0.
+// floor, ceil, round and sign have a derivative of 0. Functions registered by the caller return an error wrapping
+// ErrNotDifferentiable.
+func Derive(node *Node, variable string) (*Node, error) {
+	d, err := derive(node, variable)
+	if err != nil {
+		return nil, err
+	}
+	return Simplify(d), nil
+}
+
+func derive(n *Node, v string) (*Node, error) {
+	if !dependsOn(n, v) {
+		return NewLiteralNode(0), nil
+	}
+	switch n.op {
+	case ATOM:
+		// only the variable itself depends on it
+		return NewLiteralNode(1), nil
+	case PLUS:
+		terms, err := deriveAll(n.nodes, v)
+		if err != nil {
+			return nil, err
+		}
+		return NewPlusNode(terms), nil
+	case MINUS:
+		d, err := derive(n.nodes[0], v)
+		if err != nil {
+			return nil, err
+		}
+		return NewMinusNode(d), nil
+	case MULT:
+		// product rule: the sum of the products where one factor at a time is derived
+		ds, err := deriveAll(n.nodes, v)
+		if err != nil {
+			return nil, err
+		}
+		terms := make([]*Node, len(n.nodes))
+		for i := range n.nodes {
+			factors := make([]*Node, len(n.nodes))
+			copy(factors, n.nodes)
+			factors[i] = ds[i]
+			terms[i] = NewMultNode(factors)
+		}
+		return NewPlusNode(terms), nil
+	case DIV:
+		ds, err := deriveAll(n.nodes, v)
+		if err != nil {
+			return nil, err
+		}
+		a, b := n.nodes[0], n.nodes[1]
+		// (a'b - ab') / b^2
+		numerator := sub(mul(ds[0], b), mul(a, ds[1]))
+		return NewDivNode(numerator, NewPowNode(b, NewLiteralNode(2))), nil
+	case POW:
+		return derivePower(n.nodes[0], n.nodes[1], v)
+	case FUNC:
+		return deriveFunction(n.data.(*builtinFunc), v)
+	case LT, LE, GT, GE, EQ, NE, AND, OR, NOT:
+		// piecewise constant
+		return NewLiteralNode(0), nil
+	case COND:
+		ds, err := deriveAll(n.nodes[1:], v)
+		if err != nil {
+			return nil, err
+		}
+		return NewCondNode(n.nodes[0], ds[0], ds[1]), nil
+	default:
+		panic(fmt.Sprintf("Unknown NodeImpl type in Derive(): %v", n.op))
+	}
+}
+
+func deriveAll(nodes []*Node, v string) ([]*Node, error) {
+	ds := make([]*Node, len(nodes))
+	for i, n := range nodes {
+		var err error
+		if ds[i], err = derive(n, v); err != nil {
+			return nil, err
+		}
+	}
+	return ds, nil
+}
+
+func derivePower(base, exponent *Node, v string) (*Node, error) {
+	db, err := derive(base, v)
+	if err != nil {
+		return nil, err
+	}
+	de, err := derive(exponent, v)
+	if err != nil {
+		return nil, err
+	}
+	if !dependsOn(exponent, v) {
+		// e * b^(e-1) * b'
+		return mul(exponent, NewPowNode(base, sub(exponent, NewLiteralNode(1))), db), nil
+	}
+	power := NewPowNode(base, exponent)
+	if !dependsOn(base, v) {
+		// b^e * ln(b) * e'
+		return mul(power, call("log", base), de), nil
+	}
+	// b^e * (e' ln(b) + e b' / b)
+	return mul(power, NewPlusNode([]*Node{mul(de, call("log", base)), NewDivNode(mul(exponent, db), base)})), nil
+}
+
+func deriveFunction(f *builtinFunc, v string) (*Node, error) {
+	if !isDefaultFunc(f.fn) {
+		return nil, fmt.Errorf("%w: function %v has no known derivative", ErrNotDifferentiable, f.id)
+	}
+	ds, err := deriveAll(f.params, v)
+	if err != nil {
+		return nil, err
+	}
+	p := f.params
+	// the derivatives of functions of one parameter, without the inner derivative of the chain rule
+	var outer *Node
+	switch f.id {
+	case "sin":
+		outer = call("cos", p[0])
+	case "cos":
+		outer = NewMinusNode(call("sin", p[0]))
+	case "tan":
+		outer = NewDivNode(NewLiteralNode(1), NewPowNode(call("cos", p[0]), NewLiteralNode(2)))
+	case "asin":
+		outer = NewDivNode(NewLiteralNode(1), call("sqrt", sub(NewLiteralNode(1), NewPowNode(p[0], NewLiteralNode(2)))))
+	case "acos":
+		outer = NewMinusNode(NewDivNode(NewLiteralNode(1), call("sqrt", sub(NewLiteralNode(1), NewPowNode(p[0], NewLiteralNode(2))))))
+	case "atan":
+		outer = NewDivNode(NewLiteralNode(1), NewPlusNode([]*Node{NewLiteralNode(1), NewPowNode(p[0], NewLiteralNode(2))}))
+	case "sqrt":
+		outer = NewDivNode(NewLiteralNode(1), mul(NewLiteralNode(2), call("sqrt", p[0])))
+	case "abs":
+		outer = call("sign", p[0])
+	case "exp":
+		outer = call("exp", p[0])
+	case "log":
+		outer = NewDivNode(NewLiteralNode(1), p[0])
+	case "log2":
+		outer = NewDivNode(NewLiteralNode(1), mul(p[0], NewLiteralNode(math.Ln2)))
+	case "log10":
+		outer = NewDivNode(NewLiteralNode(1), mul(p[0], NewLiteralNode(math.Ln10)))
+	case "floor", "ceil", "round", "sign":
+		return NewLiteralNode(0), nil
+	case "pow":
+		return derivePower(p[0], p[1], v)
+	case "atan2":
+		// (x y' - y x') / (x^2 + y^2)
+		y, x := p[0], p[1]
+		squares := NewPlusNode([]*Node{NewPowNode(x, NewLiteralNode(2)), NewPowNode(y, NewLiteralNode(2))})
+		return NewDivNode(sub(mul(x, ds[0]), mul(y, ds[1])), squares), nil
+	case "mod":
+		// mod(a, b) = a - trunc(a / b) * b, where trunc(a / b) = (a - mod(a, b)) / b
+		a, b := p[0], p[1]
+		quotient := NewDivNode(sub(a, call("mod", a, b)), b)
+		return sub(ds[0], mul(quotient, ds[1])), nil
+	case "lerp":
+		// a + (b - a) t
+		a, b, t := p[0], p[1], p[2]
+		return NewPlusNode([]*Node{ds[0], mul(sub(ds[1], ds[0]), t), mul(sub(b, a), ds[2])}), nil
+	case "hypot":
+		// the sum of p * p' over hypot, and the average of the directional slopes, 0, at the origin
+		terms := make([]*Node, len(p))
+		for i := range p {
+			terms[i] = mul(p[i], ds[i])
+		}
+		h := call("hypot", p...)
+		origin := NewComparisonNode(EQ, h, NewLiteralNode(0))
+		return NewCondNode(origin, NewLiteralNode(0), NewDivNode(NewPlusNode(terms), h)), nil
+	case "min", "max":
+		acc, d := p[0], ds[0]
+		for i := 1; i < len(p); i++ {
+			acc, d = call(f.id, acc, p[i]), derivePick(f.id == "max", acc, p[i], d, ds[i])
+		}
+		return d, nil
+	case "clamp":
+		// clamp(x, low, high) = min(max(x, low), high)
+		x, low, high := p[0], p[1], p[2]
+		d := derivePick(true, x, low, ds[0], ds[1])
+		return derivePick(false, call("max", x, low), high, d, ds[2]), nil
+	default:
+		return nil, fmt.Errorf("%w: function %v has no known derivative", ErrNotDifferentiable, f.id)
+	}
+	return mul(outer, ds[0]), nil
+}
+
+// derivePick returns the derivative of max(a, b) if pickLarger is true, or min(a, b) otherwise, given the derivatives
+// da and db. It uses the step function (sign(a - b) + 1) / 2 to select between da and db.
+func derivePick(pickLarger bool, a, b, da, db *Node) *Node {
+	difference := sub(a, b)
+	if !pickLarger {
+		difference = sub(b, a)
+	}
+	step := NewDivNode(NewPlusNode([]*Node{call("sign", difference), NewLiteralNode(1)}), NewLiteralNode(2))
+	return NewPlusNode([]*Node{mul(da, step), mul(db, sub(NewLiteralNode(1), step))})
+}
+
+// dependsOn reports whether the expression uses the variable v
+func dependsOn(n *Node, v string) bool {
+	found := false
+	n.walk(func(c *Node) {
+		if l, ok := c.data.(*Literal); ok && l.variable == v {
+			found = true
+		}
+	})
+	return found
+}
+
+func mul(factors ...*Node) *Node {
+	return NewMultNode(factors)
+}
+
+func sub(a, b *Node) *Node {
+	return NewPlusNode([]*Node{a, NewMinusNode(b)})
+}
+
+// call creates a call to a function from the default library
+func call(name string, params ...*Node) *Node {
+	fn, _ := defaultFunctions.Lookup(name)
+	return NewFunctionNode(&builtinFunc{id: name, fn: fn, params: params})
+}
@@ -0,0 +1,116 @@
+package matheval
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestDerive(t *testing.T) {
+	tests := map[string]string{
+		"5":             "0",
+		"x":             "1",
+		"y":             "0",
+		"3 * x + 2":     "3",
+		"x^3":           "3 * x^2",
+		"x^n":           "n * x^(n - 1)",
+		"sin(x)":        "cos(x)",
+		"cos(2 * x)":    "-(2 * sin(2 * x))",
+		"exp(x) * y":    "exp(x) * y",
+		"log(x)":        "1 / x",
+		"x * y * x":     "2 * x * y",
+		"-x":            "-1",
+		"x - y":         "1",
+		"pow(x, 2) + 1": "2 * x",
+	}
+	for expr, expected := range tests {
+		node, err := Parse(expr)
+		if err != nil {
+			t.Fatalf("Failed to parse %v: %v", expr, err)
+		}
+		d, err := Derive(node, "x")
+		if err != nil {
+			t.Errorf("Failed to derive %v: %v", expr, err)
+		} else if d.String() != expected {
+			t.Errorf("Derived %v to %v, expected %v", expr, d, expected)
+		}
+	}
+}
+
+// TestDeriveNumerically compares derivatives of all operators and builtins with central differences
+func TestDeriveNumerically(t *testing.T) {
+	exprs := []string{
+		"x^2 * y - x / (y + x)",
+		"2^x + x^x + x^y",
+		"sin(x) + cos(x * y) + tan(x)",
+		"asin(x / 4) + acos(x / 4) + atan(x * y)",
+		"atan2(x, y) + atan2(y, x^2)",
+		"sqrt(x) + abs(x - 3) + exp(-x)",
+		"log(x) + log2(x * y) + log10(x)",
+		"floor(x) + ceil(x) + round(x) + sign(x) + x",
+		"pow(x, y) + pow(y, x)",
+		"mod(x * 3, y) + mod(y, x)",
+		"lerp(x, y * x, x / 4)",
+		"hypot(x, y, 2 * x)",
+		"min(x, y, 1) + max(x^2, y, 1)",
+		"clamp(x * 3, y, 4) + clamp(x, 0, y) + clamp(y, x, 2 * x)",
+		"-(x - y) * -(x + 1)",
+	}
+	for _, expr := range exprs {
+		node, err := Parse(expr)
+		if err != nil {
+			t.Fatalf("Failed to parse %v: %v", expr, err)
+		}
+		d, err := Derive(node, "x")
+		if err != nil {
+			t.Errorf("Failed to derive %v: %v", expr, err)
+			continue
+		}
+		for _, x := range []float64{1.3, 2.7} {
+			const h = 1e-6
+			vars := map[string]float64{"x": x, "y": 2.1}
+			expected := (evalAt(node, vars, x+h) - evalAt(node, vars, x-h)) / (2 * h)
+			if result := d.Evaluate(vars); math.Abs(result-expected) > 1e-4*math.Max(1, math.Abs(expected)) {
+				t.Errorf("d/dx %v = %v at x=%v gives %v, expected %v", expr, d, x, result, expected)
+			}
+		}
+	}
+}
+
+func TestDerivePiecewiseJoins(t *testing.T) {
+	// like abs, hypot has the average slope 0 where the pieces meet
+	for _, expr := range []string{"abs(x)", "hypot(x, y)", "hypot(x, 2 * x)"} {
+		node, _ := Parse(expr)
+		d, err := Derive(node, "x")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v := d.Evaluate(map[string]float64{"x": 0, "y": 0}); v != 0 {
+			t.Errorf("d/dx %v = %v gives %v at the origin, expected 0", expr, d, v)
+		}
+	}
+}
+
+func TestDeriveCustomFunction(t *testing.T) {
+	funcs := DefaultFunctions()
+	funcs.Register1("twice", func(x float64) float64 { return 2 * x })
+	node, _ := Parser{Functions: funcs}.Parse("twice(x) + sin(x)")
+	if _, err := Derive(node, "x"); !errors.Is(err, ErrNotDifferentiable) {
+		t.Errorf("Expected a not differentiable error, got %v", err)
+	}
+	// it doesn't matter when the function doesn't depend on the variable
+	if d, err := Derive(node, "y"); err != nil || d.String() != "0" {
+		t.Errorf("Expected 0, got %v, %v", d, err)
+	}
+}
+
+func evalAt(node *Node, vars map[string]float64, x float64) float64 {
+	old := vars["x"]
+	vars["x"] = x
+	defer func() { vars["x"] = old }()
+	return node.Evaluate(vars)
+}
+
+func closeTo(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*math.Max(1, math.Abs(b))
+}
@@ -0,0 +1,87 @@
+package matheval
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// The kinds of parse errors. Every ParseError wraps one of them, so they can be checked with errors.Is.
+var (
+	// ErrSyntax is the kind of errors that don't have a more specific kind, such as unexpected tokens
+	ErrSyntax                = errors.New("Syntax error")
+	ErrUnknownFunction       = errors.New("Unknown function")
+	ErrArity                 = errors.New("Wrong number of parameters")
+	ErrUnbalancedParentheses = errors.New("Unbalanced parentheses")
+	ErrBadNumber             = errors.New("Bad number")
+)
+
+// ParseError describes where and why an expression failed to parse.
+// Printing it with %+v shows the offending line with the token marked by carets.
+type ParseError struct {
+	// Err describes the error and wraps one of the error kinds
+	Err  error
+	Expr string
+	// Offset is the byte offset of the offending token in Expr
+	Offset int
+	// Line and Column are 1-based, and Column counts runes
+	Line, Column int
+	// Token is the offending token, or empty at the end of the expression
+	Token string
+	// Expected lists the tokens that would have been valid instead, if known
+	Expected []string
+}
+
+func newParseError(expr string, offset int, token string, err error, expected ...string) *ParseError {
+	e := &ParseError{Err: err, Expr: expr, Offset: offset, Token: token, Expected: expected}
+	lineStart := strings.LastIndexByte(expr[:offset], '\n') + 1
+	e.Line = strings.Count(expr[:lineStart], "\n") + 1
+	e.Column = utf8.RuneCountInString(expr[lineStart:offset]) + 1
+	return e
+}
+
+func (e *ParseError) Error() string {
+	msg := fmt.Sprintf("%v at line %v, column %v", e.Err, e.Line, e.Column)
+	if len(e.Expected) == 1 {
+		msg += ", expected " + e.Expected[0]
+	} else if len(e.Expected) > 1 {
+		msg += ", expected one of " + strings.Join(e.Expected, ", ")
+	}
+	return msg
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Format implements fmt.Formatter. %+v adds the offending line and a caret marker below the error message, and the
+// other verbs format the message like a string:
+//
+//	Unknown function: foo at line 1, column 5
+//	x + foo(1)
+//	    ^^^
+func (e *ParseError) Format(f fmt.State, verb rune) {
+	if verb != 'v' || !f.Flag('+') {
+		fmt.Fprintf(f, fmt.FormatString(f, verb), e.Error())
+		return
+	}
+	io.WriteString(f, e.Error())
+	lineStart := strings.LastIndexByte(e.Expr[:e.Offset], '\n') + 1
+	lineEnd := strings.IndexByte(e.Expr[e.Offset:], '\n')
+	if lineEnd < 0 {
+		lineEnd = len(e.Expr)
+	} else {
+		lineEnd += e.Offset
+	}
+	// keep tabs so that the carets line up with the line above
+	indent := []rune(e.Expr[lineStart:e.Offset])
+	for i, c := range indent {
+		if c != '\t' {
+			indent[i] = ' '
+		}
+	}
+	width := max(utf8.RuneCountInString(e.Token), 1)
+	fmt.Fprintf(f, "\n%v\n%v%v", e.Expr[lineStart:lineEnd], string(indent), strings.Repeat("^", width))
+}
@@ -0,0 +1,82 @@
+package matheval
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestParseErrorKinds(t *testing.T) {
+	tests := []struct {
+		expr   string
+		kind   error
+		offset int
+		token  string
+	}{
+		{"1 + foo(2)", ErrUnknownFunction, 4, "foo"},
+		{"1 + pow(2)", ErrArity, 4, "pow"},
+		{"min(2)", ErrArity, 0, "min"},
+		{"(1 + 2", ErrUnbalancedParentheses, 6, ""},
+		{"abs(1", ErrUnbalancedParentheses, 5, ""},
+		{"(1 + 2))", ErrUnbalancedParentheses, 7, ")"},
+		{"1 + 2..5", ErrBadNumber, 4, "2..5"},
+		{"1 + * 2", ErrSyntax, 4, "*"},
+		{"1 +", ErrSyntax, 3, ""},
+		{"1 $ 2", ErrSyntax, 2, "$"},
+		{"x\n + y z", ErrSyntax, 7, "z"},
+	}
+	for _, test := range tests {
+		_, err := Parse(test.expr)
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Errorf("Parsing %q: expected a ParseError, got %v", test.expr, err)
+			continue
+		}
+		if !errors.Is(err, test.kind) {
+			t.Errorf("Parsing %q: expected kind %v, got %v", test.expr, test.kind, err)
+		}
+		if perr.Offset != test.offset || perr.Token != test.token {
+			t.Errorf("Parsing %q: expected %q at %v, got %q at %v", test.expr, test.token, test.offset, perr.Token, perr.Offset)
+		}
+	}
+}
+
+func TestParseErrorPosition(t *testing.T) {
+	_, err := Parse("a +\n\tb * (c +)")
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("Expected a ParseError, got %v", err)
+	}
+	if perr.Line != 2 || perr.Column != 10 {
+		t.Errorf("Expected line 2, column 10, got line %v, column %v", perr.Line, perr.Column)
+	}
+	if !slices.Equal(perr.Expected, operandTokens) {
+		t.Errorf("Unexpected expected tokens %v", perr.Expected)
+	}
+	expected := "Syntax error: unexpected \")\" at line 2, column 10, expected one of number, identifier, \"(\", \"-\", \"+\", \"!\"\n" +
+		"\tb * (c +)\n" +
+		"\t        ^"
+	if s := fmt.Sprintf("%+v", err); s != expected {
+		t.Errorf("Expected\n%v\ngot\n%v", expected, s)
+	}
+}
+
+func TestParseErrorFormat(t *testing.T) {
+	_, err := Parse("x + foo(1)")
+	expected := "Unknown function: foo at line 1, column 5\n" +
+		"x + foo(1)\n" +
+		"    ^^^"
+	if s := fmt.Sprintf("%+v", err); s != expected {
+		t.Errorf("Expected\n%v\ngot\n%v", expected, s)
+	}
+	if s := fmt.Sprintf("%v", err); s != "Unknown function: foo at line 1, column 5" {
+		t.Errorf("Unexpected message %v", s)
+	}
+	if s := fmt.Sprintf("%q", err); s != `"Unknown function: foo at line 1, column 5"` {
+		t.Errorf("Unexpected quoted message %v", s)
+	}
+	if s := fmt.Sprintf("%.7s %.3x", err, err); s != "Unknown 556e6b" {
+		t.Errorf("Unexpected formatting %v", s)
+	}
+}
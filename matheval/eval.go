@@ -23,3 +23,12 @@ func EvalVariables(expr string, vars map[string]float64) (float64, error) {
 		return node.Evaluate(vars), nil
 	}
 }
+
+// EvalVariablesStrict is like EvalVariables, but returns an error for variables that are missing from vars
+func EvalVariablesStrict(expr string, vars map[string]float64) (float64, error) {
+	node, err := Parse(expr)
+	if err != nil {
+		return 0, err
+	}
+	return node.EvaluateStrict(vars)
+}
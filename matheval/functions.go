@@ -0,0 +1,175 @@
+package matheval
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+)
+
+// Variadic is the MaxParams of functions that take any number of parameters
+const Variadic = -1
+
+// Func is a Go function that expressions can call
+type Func struct {
+	Name string
+	// MinParams and MaxParams are the allowed number of parameters. MaxParams is Variadic if there is no upper limit.
+	MinParams, MaxParams int
+
+	// exactly one of these is set
+	f1   func(float64) float64
+	f2   func(float64, float64) float64
+	f3   func(float64, float64, float64) float64
+	fold func(acc, x float64) float64
+	fn   func(args []float64) float64
+}
+
+// Call calls f. The number of arguments must be allowed by MinParams and MaxParams.
+func (f *Func) Call(args ...float64) float64 {
+	switch {
+	case f.f1 != nil:
+		return f.f1(args[0])
+	case f.f2 != nil:
+		return f.f2(args[0], args[1])
+	case f.f3 != nil:
+		return f.f3(args[0], args[1], args[2])
+	case f.fold != nil:
+		r := args[0]
+		for _, a := range args[1:] {
+			r = f.fold(r, a)
+		}
+		return r
+	default:
+		return f.fn(args)
+	}
+}
+
+// checkArity returns an error wrapping ErrArity if f can't be called with n parameters
+func (f *Func) checkArity(n int) error {
+	switch {
+	case f.MinParams == f.MaxParams && n != f.MinParams:
+		return fmt.Errorf("%w for function %v: %v, expected %v", ErrArity, f.Name, n, f.MinParams)
+	case n < f.MinParams:
+		return fmt.Errorf("%w for function %v: %v, expected at least %v", ErrArity, f.Name, n, f.MinParams)
+	case f.MaxParams != Variadic && n > f.MaxParams:
+		return fmt.Errorf("%w for function %v: %v, expected at most %v", ErrArity, f.Name, n, f.MaxParams)
+	}
+	return nil
+}
+
+// FunctionSet holds the functions that expressions can call. Function names are checked and resolved when parsing,
+// so registering functions doesn't affect expressions that have already been parsed.
+type FunctionSet struct {
+	funcs map[string]*Func
+}
+
+// NewFunctionSet creates an empty function set
+func NewFunctionSet() *FunctionSet {
+	return &FunctionSet{funcs: make(map[string]*Func)}
+}
+
+// DefaultFunctions creates a function set with the default library, which can be extended with more functions:
+// sin, cos, tan, asin, acos, atan, atan2, sqrt, abs, exp, log, log2, log10, pow, mod, floor, ceil, round, sign,
+// clamp(x, min, max), lerp(a, b, t), hypot, min and max. hypot, min and max take two or more parameters.
+func DefaultFunctions() *FunctionSet {
+	return defaultFunctions.Clone()
+}
+
+func (s *FunctionSet) Clone() *FunctionSet {
+	return &FunctionSet{funcs: maps.Clone(s.funcs)}
+}
+
+// Lookup returns the function called name
+func (s *FunctionSet) Lookup(name string) (*Func, bool) {
+	f, ok := s.funcs[name]
+	return f, ok
+}
+
+// Names returns the names of all functions in alphabetical order
+func (s *FunctionSet) Names() []string {
+	return slices.Sorted(maps.Keys(s.funcs))
+}
+
+// Register1 registers a function with one parameter, replacing any function with the same name
+func (s *FunctionSet) Register1(name string, f func(float64) float64) {
+	s.funcs[name] = &Func{Name: name, MinParams: 1, MaxParams: 1, f1: f}
+}
+
+// Register2 registers a function with two parameters
+func (s *FunctionSet) Register2(name string, f func(float64, float64) float64) {
+	s.funcs[name] = &Func{Name: name, MinParams: 2, MaxParams: 2, f2: f}
+}
+
+// Register3 registers a function with three parameters
+func (s *FunctionSet) Register3(name string, f func(float64, float64, float64) float64) {
+	s.funcs[name] = &Func{Name: name, MinParams: 3, MaxParams: 3, f3: f}
+}
+
+// RegisterFold registers a function that takes minParams or more parameters, and combines them from left to right
+// with f like min and max do
+func (s *FunctionSet) RegisterFold(name string, minParams int, f func(acc, x float64) float64) {
+	s.funcs[name] = &Func{Name: name, MinParams: max(minParams, 1), MaxParams: Variadic, fold: f}
+}
+
+// RegisterVariadic registers a function that takes between minParams and maxParams parameters.
+// maxParams can be Variadic. Compiled programs reuse the argument slice between calls, so f must not keep it.
+func (s *FunctionSet) RegisterVariadic(name string, minParams, maxParams int, f func(args []float64) float64) {
+	s.funcs[name] = &Func{Name: name, MinParams: minParams, MaxParams: maxParams, fn: f}
+}
+
+var defaultFunctions = newDefaultFunctions()
+
+func newDefaultFunctions() *FunctionSet {
+	s := NewFunctionSet()
+	s.Register1("sin", math.Sin)
+	s.Register1("cos", math.Cos)
+	s.Register1("tan", math.Tan)
+	s.Register1("asin", math.Asin)
+	s.Register1("acos", math.Acos)
+	s.Register1("atan", math.Atan)
+	s.Register2("atan2", math.Atan2)
+	s.Register1("sqrt", math.Sqrt)
+	s.Register1("abs", math.Abs)
+	s.Register1("exp", math.Exp)
+	s.Register1("log", math.Log)
+	s.Register1("log2", math.Log2)
+	s.Register1("log10", math.Log10)
+	s.Register2("pow", math.Pow)
+	s.Register2("mod", math.Mod)
+	s.Register1("floor", math.Floor)
+	s.Register1("ceil", math.Ceil)
+	s.Register1("round", math.Round)
+	s.Register1("sign", sign)
+	s.Register3("clamp", clamp)
+	s.Register3("lerp", func(a, b, t float64) float64 { return a + (b-a)*t })
+	s.RegisterFold("hypot", 2, math.Hypot)
+	s.RegisterFold("min", 2, func(acc, x float64) float64 {
+		if x < acc {
+			return x
+		}
+		return acc
+	})
+	s.RegisterFold("max", 2, func(acc, x float64) float64 {
+		if x > acc {
+			return x
+		}
+		return acc
+	})
+	return s
+}
+
+func sign(x float64) float64 {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	default:
+		// keeps 0 and NaN
+		return x
+	}
+}
+
+func clamp(x, low, high float64) float64 {
+	return math.Max(low, math.Min(high, x))
+}
@@ -0,0 +1,102 @@
+package matheval
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/Lundis/go-gmath/fastmath"
+)
+
+func TestDefaultFunctions(t *testing.T) {
+	tests := map[string]float64{
+		"tan(1)":                math.Tan(1),
+		"asin(0.5)":             math.Asin(0.5),
+		"acos(0.5)":             math.Acos(0.5),
+		"atan(2)":               math.Atan(2),
+		"atan2(1, -1)":          math.Atan2(1, -1),
+		"exp(2)":                math.Exp(2),
+		"log(e)":                1,
+		"log2(8)":               3,
+		"log10(1000)":           3,
+		"floor(-1.5)":           -2,
+		"ceil(1.2)":             2,
+		"round(2.5)":            3,
+		"sign(-3) + sign(0)":    -1,
+		"clamp(5, 0, 2)":        2,
+		"clamp(-5, 0, 2)":       0,
+		"clamp(1, 0, 2)":        1,
+		"lerp(10, 20, 0.25)":    12.5,
+		"hypot(3, 4)":           5,
+		"hypot(1, 2, 2)":        3,
+		"min(3, 1, 2)":          1,
+		"max(3, 1, 2)":          3,
+		"mod(7, 3) + pow(2, 3)": 9,
+	}
+	for expr, expected := range tests {
+		result, err := Eval(expr)
+		if err != nil {
+			t.Errorf("Failed to evaluate %v: %v", expr, err)
+		} else if !fastmath.Equald(result, expected, 1e-9) {
+			t.Errorf("Evaluated %v. Got %v. Expected %v", expr, result, expected)
+		}
+	}
+}
+
+func TestFunctionSetRegister(t *testing.T) {
+	funcs := DefaultFunctions()
+	funcs.Register2("level_scale", func(base, level float64) float64 { return base * math.Pow(1.1, level) })
+	funcs.RegisterVariadic("avg", 1, Variadic, func(args []float64) float64 {
+		sum := 0.0
+		for _, a := range args {
+			sum += a
+		}
+		return sum / float64(len(args))
+	})
+	p := Parser{Functions: funcs}
+
+	node, err := p.Parse("level_scale(10, level) + avg(1, 2, level)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vars := map[string]float64{"level": 3}
+	expected := 10*math.Pow(1.1, 3) + 2
+	if result := node.Evaluate(vars); !fastmath.Equald(result, expected, 1e-9) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+	prog := Compile(node)
+	if result := prog.Evaluate(prog.Bind(vars, nil)); !fastmath.Equald(result, expected, 1e-9) {
+		t.Errorf("Compiled: expected %v, got %v", expected, result)
+	}
+
+	// registering doesn't change the default library
+	if _, err := Parse("level_scale(10, 2)"); !errors.Is(err, ErrUnknownFunction) {
+		t.Errorf("Expected an unknown function error, got %v", err)
+	}
+	if _, err := p.Parse("avg()"); !errors.Is(err, ErrArity) {
+		t.Errorf("Expected an arity error, got %v", err)
+	}
+	if _, err := p.Parse("level_scale(1, 2, 3)"); !errors.Is(err, ErrArity) {
+		t.Errorf("Expected an arity error, got %v", err)
+	}
+}
+
+func TestFunctionSetEmpty(t *testing.T) {
+	funcs := NewFunctionSet()
+	if _, err := (Parser{Functions: funcs}).Parse("sin(x)"); !errors.Is(err, ErrUnknownFunction) {
+		t.Errorf("Expected an unknown function error, got %v", err)
+	}
+	funcs.Register1("double", func(x float64) float64 { return 2 * x })
+	funcs.RegisterVariadic("between", 2, 3, func(args []float64) float64 { return args[len(args)-1] })
+	if !slices.Equal(funcs.Names(), []string{"between", "double"}) {
+		t.Errorf("Unexpected names %v", funcs.Names())
+	}
+	f, _ := funcs.Lookup("double")
+	if f.Call(4) != 8 {
+		t.Errorf("Expected 8, got %v", f.Call(4))
+	}
+	if _, err := (Parser{Functions: funcs}).Parse("between(1, 2, 3, 4)"); !errors.Is(err, ErrArity) {
+		t.Errorf("Expected an arity error, got %v", err)
+	}
+}
@@ -0,0 +1,168 @@
+package matheval
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var operatorNames = []string{
+	PLUS:  "plus",
+	MINUS: "minus",
+	MULT:  "mult",
+	DIV:   "div",
+	ATOM:  "atom",
+	FUNC:  "func",
+	POW:   "pow",
+	LT:    "lt",
+	LE:    "le",
+	GT:    "gt",
+	GE:    "ge",
+	EQ:    "eq",
+	NE:    "ne",
+	AND:   "and",
+	OR:    "or",
+	NOT:   "not",
+	COND:  "cond",
+	// only used in typed expressions, which can't be stored as JSON
+	COMPONENT: "component",
+}
+
+func (o Operator) String() string {
+	if int(o) < len(operatorNames) {
+		return operatorNames[o]
+	}
+	return fmt.Sprintf("Operator(%d)", int(o))
+}
+
+func (o Operator) MarshalText() ([]byte, error) {
+	return []byte(o.String()), nil
+}
+
+func (o *Operator) UnmarshalText(text []byte) error {
+	i := slices.Index(operatorNames, string(text))
+	if i < 0 {
+		return fmt.Errorf("Unknown operator: %v", string(text))
+	}
+	*o = Operator(i)
+	return nil
+}
+
+// nodeJSON is the JSON form of operators and function calls
+type nodeJSON struct {
+	Op   *Operator         `json:"op,omitempty"`
+	Func string            `json:"func,omitempty"`
+	Args []json.RawMessage `json:"args"`
+}
+
+// MarshalJSON stores the parsed expression. Numbers are stored as JSON numbers, variables as strings, and operators and
+// function calls as objects such as {"op": "plus", "args": [1, "x"]} and {"func": "max", "args": ["x", 2]}.
+func (self *Node) MarshalJSON() ([]byte, error) {
+	switch self.op {
+	case ATOM:
+		l := self.data.(*Literal)
+		if l.variable != "" {
+			return json.Marshal(l.variable)
+		}
+		return json.Marshal(l.val)
+	case FUNC:
+		f := self.data.(*builtinFunc)
+		return json.Marshal(struct {
+			Func string  `json:"func"`
+			Args []*Node `json:"args"`
+		}{f.id, f.params})
+	default:
+		return json.Marshal(struct {
+			Op   Operator `json:"op"`
+			Args []*Node  `json:"args"`
+		}{self.op, self.nodes})
+	}
+}
+
+// UnmarshalJSON loads an expression stored by MarshalJSON. Function calls are resolved with the default library,
+// use Parser.ParseJSON for other functions.
+func (self *Node) UnmarshalJSON(data []byte) error {
+	n, err := Parser{}.ParseJSON(data)
+	if err != nil {
+		return err
+	}
+	*self = *n
+	return nil
+}
+
+// ParseJSON loads an expression stored by Node.MarshalJSON, resolving function calls with the parser's functions
+func (self Parser) ParseJSON(data []byte) (*Node, error) {
+	if self.Functions == nil {
+		self.Functions = defaultFunctions
+	}
+	return self.parseJSON(data)
+}
+
+func (self Parser) parseJSON(data []byte) (*Node, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, errors.New("Empty expression")
+	}
+	switch data[0] {
+	case '"':
+		var name string
+		if err := json.Unmarshal(data, &name); err != nil {
+			return nil, err
+		}
+		return NewVarNode(name), nil
+	case '{':
+		var j nodeJSON
+		if err := json.Unmarshal(data, &j); err != nil {
+			return nil, err
+		}
+		args := make([]*Node, len(j.Args))
+		for i, a := range j.Args {
+			var err error
+			if args[i], err = self.parseJSON(a); err != nil {
+				return nil, err
+			}
+		}
+		if j.Op == nil {
+			f, err := newBuiltinFunc(self.Functions, j.Func, args)
+			if err != nil {
+				return nil, err
+			}
+			return NewFunctionNode(f), nil
+		}
+		return newOperatorNode(*j.Op, args)
+	default:
+		var num float64
+		if err := json.Unmarshal(data, &num); err != nil {
+			return nil, err
+		}
+		return NewLiteralNode(num), nil
+	}
+}
+
+func newOperatorNode(op Operator, args []*Node) (*Node, error) {
+	switch {
+	case op == PLUS && len(args) > 0:
+		return NewPlusNode(args), nil
+	case op == MULT && len(args) > 0:
+		return NewMultNode(args), nil
+	case op == MINUS && len(args) == 1:
+		return NewMinusNode(args[0]), nil
+	case op == DIV && len(args) == 2:
+		return NewDivNode(args[0], args[1]), nil
+	case op == POW && len(args) == 2:
+		return NewPowNode(args[0], args[1]), nil
+	case (op == LT || op == LE || op == GT || op == GE || op == EQ || op == NE) && len(args) == 2:
+		return NewComparisonNode(op, args[0], args[1]), nil
+	case op == AND && len(args) == 2:
+		return NewAndNode(args[0], args[1]), nil
+	case op == OR && len(args) == 2:
+		return NewOrNode(args[0], args[1]), nil
+	case op == NOT && len(args) == 1:
+		return NewNotNode(args[0]), nil
+	case op == COND && len(args) == 3:
+		return NewCondNode(args[0], args[1], args[2]), nil
+	}
+	return nil, fmt.Errorf("Wrong number of arguments for operator %v: %v", op, len(args))
+}
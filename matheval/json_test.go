@@ -0,0 +1,58 @@
+package matheval
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestNodeJSON(t *testing.T) {
+	node, _ := Parse("2 * x - max(y, 1.5)^-3")
+	data, err := json.Marshal(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"op":"plus","args":[{"op":"mult","args":[2,"x"]},{"op":"minus","args":[` +
+		`{"op":"pow","args":[{"func":"max","args":["y",1.5]},{"op":"minus","args":[3]}]}]}]}`
+	if string(data) != expected {
+		t.Errorf("Expected\n%v\ngot\n%v", expected, string(data))
+	}
+	var loaded Node
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	if !sameTree(node, &loaded) {
+		t.Errorf("Loaded %v, expected %v", &loaded, node)
+	}
+}
+
+func TestNodeJSONErrors(t *testing.T) {
+	var n Node
+	if err := json.Unmarshal([]byte(`{"func":"nope","args":[1]}`), &n); !errors.Is(err, ErrUnknownFunction) {
+		t.Errorf("Expected an unknown function error, got %v", err)
+	}
+	if err := json.Unmarshal([]byte(`{"func":"pow","args":[1]}`), &n); !errors.Is(err, ErrArity) {
+		t.Errorf("Expected an arity error, got %v", err)
+	}
+	if err := json.Unmarshal([]byte(`{"op":"div","args":[1]}`), &n); err == nil {
+		t.Errorf("Expected an error for a division with one argument")
+	}
+	if err := json.Unmarshal([]byte(`{"op":"modulo","args":[1, 2]}`), &n); err == nil {
+		t.Errorf("Expected an error for an unknown operator")
+	}
+	if err := json.Unmarshal([]byte(`-2`), &n); err != nil || n.String() != "-2" {
+		t.Errorf("Expected -2, got %v, %v", &n, err)
+	}
+}
+
+func TestParseJSONFunctions(t *testing.T) {
+	funcs := DefaultFunctions()
+	funcs.Register1("twice", func(x float64) float64 { return 2 * x })
+	node, err := Parser{Functions: funcs}.ParseJSON([]byte(`{"func": "twice", "args": ["x"]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := node.Evaluate(map[string]float64{"x": 4}); result != 8 {
+		t.Errorf("Expected 8, got %v", result)
+	}
+}
@@ -0,0 +1,103 @@
+package matheval
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenIdent
+	// tokenOperator covers operators and punctuation such as parentheses and commas
+	tokenOperator
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	// pos is the byte offset of the token in the expression
+	pos int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// operators lists the operators and punctuation, with longer operators before the ones they start with
+var operators = []string{"<=", ">=", "==", "!=", "&&", "||", "+", "-", "*", "/", "^", "(", ")", ",", "<", ">", "!", "?", ":", ".", "=", ";"}
+
+// lex splits expr into tokens, ending with a tokenEOF
+func lex(expr string) ([]token, error) {
+	tokens := make([]token, 0, len(expr)/2+1)
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		start := i
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+			continue
+		case isDigit(c) || c == '.' && i+1 < len(expr) && isDigit(expr[i+1]):
+			i = scanNumber(expr, i)
+			tokens = append(tokens, token{kind: tokenNumber, text: expr[start:i], pos: start})
+		case isIdentStart(c):
+			for i++; i < len(expr) && isIdentPart(expr[i]); i++ {
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: expr[start:i], pos: start})
+		case scanOperator(expr[i:]) != "":
+			i += len(scanOperator(expr[i:]))
+			tokens = append(tokens, token{kind: tokenOperator, text: expr[start:i], pos: start})
+		default:
+			r, size := utf8.DecodeRuneInString(expr[i:])
+			return nil, newParseError(expr, i, expr[i:i+size], fmt.Errorf("%w: unexpected character %q", ErrSyntax, r))
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(expr)}), nil
+}
+
+// scanNumber returns the end of the number starting at i, which may have a fraction and an exponent.
+// An e that isn't followed by digits is not part of the number, so "2e" is 2 followed by the constant e.
+func scanNumber(expr string, i int) int {
+	for i < len(expr) && (isDigit(expr[i]) || expr[i] == '.') {
+		i++
+	}
+	if i < len(expr) && (expr[i] == 'e' || expr[i] == 'E') {
+		j := i + 1
+		if j < len(expr) && (expr[j] == '+' || expr[j] == '-') {
+			j++
+		}
+		if j < len(expr) && isDigit(expr[j]) {
+			for i = j; i < len(expr) && isDigit(expr[i]); i++ {
+			}
+		}
+	}
+	return i
+}
+
+// scanOperator returns the operator at the start of s, or an empty string if there is none
+func scanOperator(s string) string {
+	for _, op := range operators {
+		if strings.HasPrefix(s, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || c == '_'
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || isDigit(c)
+}
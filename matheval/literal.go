@@ -1,9 +1,5 @@
 package matheval
 
-import (
-	"fmt"
-)
-
 // A Literal represents a number or a variable
 type Literal struct {
 	val      float64
@@ -20,9 +16,9 @@ func NewLiteralVar(v string) *Literal {
 
 func (self *Literal) String() string {
 	if self.variable != "" {
-		return fmt.Sprintf("%s", self.variable)
+		return self.variable
 	} else {
-		return fmt.Sprintf("%.2f", self.val)
+		return formatNumber(self.val)
 	}
 }
 
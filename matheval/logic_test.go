@@ -0,0 +1,200 @@
+package matheval
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/Lundis/go-gmath/fastmath"
+)
+
+func TestLogicOperators(t *testing.T) {
+	vars := map[string]float64{"a": 1, "b": 0, "c": 0, "x": 2.5}
+	tests := map[string]float64{
+		"1 < 2":                  1,
+		"2 <= 2":                 1,
+		"3 > 4":                  0,
+		"x >= 2.5":               1,
+		"x == 2.5":               1,
+		"x != 2.5":               0,
+		"1 + 2 < 4 && 3 == 3":    1,
+		"a || b && c":            1,
+		"(a || b) && c":          0,
+		"1 < 2 == 3 < 4":         1,
+		"!a":                     0,
+		"!b":                     1,
+		"!!x":                    1,
+		"-!b":                    -1,
+		"a ? 10 : 20":            10,
+		"b ? 10 : 20":            20,
+		"b ? 1 : c ? 2 : 3":      3,
+		"a ? b ? 1 : 2 : 3":      2,
+		"x > 2 ? x * 2 : -x":     5,
+		"1 + (a ? 1 : 2) * 3":    4,
+		"if(x < 0, -x, x)":       2.5,
+		"if(b, 1, if(a, 2, 3))":  2,
+		"max(a > b, 0.5) + !!3":  2,
+		"2 * (x > 1) + (x <= 1)": 2,
+	}
+	for expr, expected := range tests {
+		node, err := Parse(expr)
+		if err != nil {
+			t.Fatalf("Failed to parse %v: %v", expr, err)
+		}
+		if result := node.Evaluate(vars); result != expected {
+			t.Errorf("%v: expected %v, got %v", expr, expected, result)
+		}
+		prog := Compile(node, "a", "b", "c", "x")
+		if result := prog.Evaluate(prog.Bind(vars, nil)); result != expected {
+			t.Errorf("%v compiled: expected %v, got %v", expr, expected, result)
+		}
+		if result := Simplify(node).Evaluate(vars); result != expected {
+			t.Errorf("%v simplified to %v: expected %v, got %v", expr, Simplify(node), expected, result)
+		}
+	}
+}
+
+func TestLogicShortCircuit(t *testing.T) {
+	calls := 0
+	funcs := DefaultFunctions()
+	funcs.Register1("count", func(x float64) float64 {
+		calls++
+		return x
+	})
+	p := Parser{Functions: funcs}
+	tests := map[string]int{
+		"0 && count(x)":                          0,
+		"1 && count(x)":                          1,
+		"1 || count(x)":                          0,
+		"0 || count(x)":                          1,
+		"1 ? count(x) : count(x)":                1,
+		"if(0, count(x), 3)":                     0,
+		"x < 0 && count(x)":                      0,
+		"x > 0 || count(x)":                      0,
+		"x > 0 ? count(x) : count(x) + count(x)": 1,
+	}
+	for expr, expected := range tests {
+		node, err := p.Parse(expr)
+		if err != nil {
+			t.Fatalf("Failed to parse %v: %v", expr, err)
+		}
+		calls = 0
+		node.Evaluate(map[string]float64{"x": 1})
+		if calls != expected {
+			t.Errorf("%v: expected %v calls, got %v", expr, expected, calls)
+		}
+		calls = 0
+		Compile(node, "x").Evaluate([]float64{1})
+		if calls != expected {
+			t.Errorf("%v compiled: expected %v calls, got %v", expr, expected, calls)
+		}
+	}
+}
+
+func TestLogicParseErrors(t *testing.T) {
+	tests := map[string]error{
+		"a ? b":       ErrSyntax,
+		"a ? b : ":    ErrSyntax,
+		"a < < b":     ErrSyntax,
+		"if(a, b)":    ErrArity,
+		"!":           ErrSyntax,
+		"a = b":       ErrSyntax,
+		"(a ? b) : c": ErrSyntax,
+	}
+	for expr, expected := range tests {
+		if _, err := Parse(expr); !errors.Is(err, expected) {
+			t.Errorf("%v: expected %v, got %v", expr, expected, err)
+		}
+	}
+}
+
+func TestLogicString(t *testing.T) {
+	exprs := map[string]string{
+		"a<b":                    "a < b",
+		"a + 1 >= b * 2":         "a + 1 >= b * 2",
+		"(a < b) < c":            "a < b < c",
+		"a < (b < c)":            "a < (b < c)",
+		"a == b != c":            "a == b != c",
+		"a && b || c && d":       "a && b || c && d",
+		"a && (b || c)":          "a && (b || c)",
+		"!(a && b)":              "!(a && b)",
+		"!a && -b":               "!a && -b",
+		"a ? b : c ? d : e":      "a ? b : c ? d : e",
+		"(a ? b : c) ? d : e":    "(a ? b : c) ? d : e",
+		"a ? (b ? c : d) : e":    "a ? b ? c : d : e",
+		"(a ? b : c) + 1":        "(a ? b : c) + 1",
+		"if(x > 0, x, -x)":       "x > 0 ? x : -x",
+		"max(a ? b : c, d || e)": "max(a ? b : c, d || e)",
+	}
+	for expr, expected := range exprs {
+		node, err := Parse(expr)
+		if err != nil {
+			t.Fatalf("Failed to parse %v: %v", expr, err)
+		}
+		s := node.String()
+		if s != expected {
+			t.Errorf("Printed %v as %v, expected %v", expr, s, expected)
+		}
+		reparsed, err := Parse(s)
+		if err != nil {
+			t.Errorf("Failed to parse %v: %v", s, err)
+		} else if !sameTree(node, reparsed) {
+			t.Errorf("%v parsed back as a different tree", s)
+		}
+	}
+}
+
+func TestLogicSimplifyDerive(t *testing.T) {
+	node, _ := Parse("1 < 2 ? x * 2 : x")
+	if s := Simplify(node).String(); s != "2 * x" {
+		t.Errorf("Expected 2 * x, got %v", s)
+	}
+	node, _ = Parse("x > 0 ? x^2 : -x")
+	d, err := Derive(node, "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := d.String(); s != "x > 0 ? 2 * x : -1" {
+		t.Errorf("Expected x > 0 ? 2 * x : -1, got %v", s)
+	}
+	node, _ = Parse("(x > 1) * x")
+	d, _ = Derive(node, "x")
+	if s := d.String(); s != "x > 1" {
+		t.Errorf("Expected x > 1, got %v", s)
+	}
+	// the base and the exponent are piecewise constant, so only 2 / -x has a slope
+	node, _ = Parse("2 / -x - pow(x == 2 && y, (x > 0) + 1)")
+	d, _ = Derive(node, "x")
+	if v := d.Evaluate(map[string]float64{"x": 0.7, "y": 1}); !fastmath.Equald(v, 2/0.49, 1e-9) {
+		t.Errorf("Expected %v, got %v from %v", 2/0.49, v, d)
+	}
+}
+
+func TestLogicJSONAndRendering(t *testing.T) {
+	node, _ := Parse("x <= 1 && !y ? 0 : 2")
+	data, err := json.Marshal(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"op":"cond","args":[{"op":"and","args":[{"op":"le","args":["x",1]},{"op":"not","args":["y"]}]},0,2]}`
+	if string(data) != expected {
+		t.Errorf("Expected\n%v\ngot\n%v", expected, string(data))
+	}
+	var loaded Node
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	if !sameTree(node, &loaded) {
+		t.Errorf("Loaded %v, expected %v", &loaded, node)
+	}
+
+	latex := `\begin{cases}0 & \text{if } x \le 1 \land \lnot y \\ 2 & \text{otherwise}\end{cases}`
+	if s := node.LaTeX(); s != latex {
+		t.Errorf("Rendered as\n%v\nexpected\n%v", s, latex)
+	}
+	node, _ = Parse("a != b")
+	mathML := `<math xmlns="http://www.w3.org/1998/Math/MathML"><mrow><mi>a</mi><mo>&#x2260;</mo><mi>b</mi></mrow></math>`
+	if s := node.MathML(); s != mathML {
+		t.Errorf("Rendered as\n%v\nexpected\n%v", s, mathML)
+	}
+}
@@ -0,0 +1,424 @@
+package matheval
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// MaxModuleDepth is how deeply the definitions of a Module can be nested, where a definition is one level deeper than
+// the deepest function or binding it uses
+const MaxModuleDepth = 64
+
+// MaxModuleCalls is how many calls of the functions of a Module one evaluation of a definition or the result may expand
+// to, counting the calls that the called functions make in turn. It keeps modules like
+//
+//	f0(x) = x + 1; f1(x) = f0(x) + f0(x); f2(x) = f1(x) + f1(x); ...
+//
+// from taking exponential time within MaxModuleDepth.
+const MaxModuleCalls = 1 << 20
+
+// ErrRecursion is returned by ParseModule for functions that call themselves, and definitions that are nested deeper
+// than MaxModuleDepth or expand to more than MaxModuleCalls calls
+var ErrRecursion = errors.New("Recursive definition")
+
+// Module is a block of definitions, such as
+//
+//	f(x) = x^2 + 1; g = f(level) * 3; g + f(2)
+//
+// Statements are separated by semicolons. name(params) = expression defines a function, and name = expression binds a
+// name to a value. The last statement may be an expression, which is the result of the module.
+//
+// Scoping is lexical: definitions can only use the functions and bindings defined before them, which also rules out
+// recursion. A binding can refine a variable of the same name, as in level = max(level, 1), where the level on the right
+// is the variable passed to Evaluate. Function bodies can use their parameters and the bindings that don't depend on
+// other variables, and parameters hide bindings with the same name. Functions may hide functions of the parser's library.
+//
+// Bindings are evaluated once for every evaluation of the result or call of a function that uses them, however many
+// times they are used.
+type Module struct {
+	functions *FunctionSet
+	// names are the functions defined by the module, in order of definition
+	names    []string
+	bindings map[string]*binding
+	// calls are the expanded number of calls that a call of each function of the module makes, including itself
+	calls  map[*Func]int
+	result *Node
+	// program evaluates the result
+	program *Program
+}
+
+type binding struct {
+	name string
+	// node is the value as written
+	node *Node
+	// linked is node with the bindings it uses replaced by their slots
+	linked *Node
+	// uses are the bindings that node uses directly
+	uses []*binding
+	// calls are the expanded calls of functions of the module that node makes, without the bindings it uses
+	calls int
+	// index is the position of the binding in the module, which orders it after the bindings it uses
+	index int
+}
+
+// bindingPrefix starts the slots of bindings in the programs of a module. Identifiers can't contain it, so parameters
+// and variables can't clash with bindings.
+const bindingPrefix = "$"
+
+// ParseModule parses a module with the default syntax
+func ParseModule(src string) (*Module, error) {
+	return Parser{}.ParseModule(src)
+}
+
+// ParseModule parses a module once, after which its functions can be called and used in other expressions.
+// Syntax errors are returned as a *ParseError. Errors in definitions wrap ErrRecursion, ErrUnknownVariable for
+// function bodies that use other variables than their parameters, or ErrSyntax.
+func (self Parser) ParseModule(src string) (*Module, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	if self.Functions == nil {
+		self.Functions = defaultFunctions
+	}
+	m := &Module{
+		functions: self.Functions.Clone(),
+		bindings:  make(map[string]*binding),
+		calls:     make(map[*Func]int),
+	}
+	// depths are the nesting depths of the definitions, with functions stored by their name followed by "("
+	depths := make(map[string]int)
+	// expression is the start of the result expression
+	var expression token
+	for start := 0; start < len(tokens)-1; {
+		end := start
+		for tokens[end].kind != tokenEOF && tokens[end].text != ";" {
+			end++
+		}
+		statement := append(slices.Clip(tokens[start:end]), token{kind: tokenEOF, pos: tokens[end].pos})
+		start = end + 1
+		if len(statement) == 1 {
+			// an empty statement
+			continue
+		}
+		if m.result != nil {
+			return nil, newParseError(src, expression.pos, expression.text,
+				fmt.Errorf("%w: only the last statement can be an expression", ErrSyntax))
+		}
+		if err := m.parseStatement(Parser{ImplicitMultiplication: self.ImplicitMultiplication, Functions: m.functions},
+			src, statement, depths); err != nil {
+			return nil, err
+		}
+		expression = statement[0]
+	}
+	return m, nil
+}
+
+// parseStatement parses a definition or the result expression
+func (self *Module) parseStatement(p Parser, src string, statement []token, depths map[string]int) error {
+	name, params, body, err := splitDefinition(src, statement)
+	if err != nil {
+		return err
+	}
+	if name.kind == tokenEOF {
+		node, err := p.parseTokens(src, statement, false)
+		if err != nil {
+			return err
+		}
+		program, calls := self.compile(node, nil)
+		if calls > MaxModuleCalls {
+			return newParseError(src, statement[0].pos, statement[0].text,
+				fmt.Errorf("%w: the result makes more than %v calls", ErrRecursion, MaxModuleCalls))
+		}
+		self.result = node
+		self.program = program
+		return nil
+	}
+	isFunction := params != nil
+	key := name.text
+	if isFunction {
+		key += "("
+	}
+	if _, defined := depths[key]; defined {
+		return newParseError(src, name.pos, name.text, fmt.Errorf("%w: %v is already defined", ErrSyntax, name.text))
+	}
+	for i, param := range params {
+		if slices.Contains(params[:i], param) {
+			return newParseError(src, name.pos, name.text,
+				fmt.Errorf("%w: parameter %v of %v is repeated", ErrSyntax, param, name.text))
+		}
+	}
+	for i, t := range body[:len(body)-1] {
+		if isFunction && t.kind == tokenIdent && t.text == name.text && body[i+1].text == "(" {
+			return newParseError(src, t.pos, t.text, fmt.Errorf("%w: %v calls itself", ErrRecursion, name.text))
+		}
+	}
+	node, err := p.parseTokens(src, body, false)
+	if err != nil {
+		return err
+	}
+
+	depth := 1
+	variables, functions := node.Variables()
+	for _, v := range variables {
+		if !slices.Contains(params, v) {
+			depth = max(depth, depths[v]+1)
+		}
+	}
+	for _, f := range functions {
+		depth = max(depth, depths[f+"("]+1)
+	}
+	if depth > MaxModuleDepth {
+		return newParseError(src, name.pos, name.text,
+			fmt.Errorf("%w: %v is nested more than %v definitions deep", ErrRecursion, name.text, MaxModuleDepth))
+	}
+	depths[key] = depth
+
+	if !isFunction {
+		uses := make(map[*binding]bool)
+		b := &binding{
+			name:   name.text,
+			node:   node,
+			linked: self.link(node, nil, uses),
+			uses:   slices.Collect(maps.Keys(uses)),
+			calls:  self.countCalls(node),
+			index:  len(self.bindings),
+		}
+		calls := b.calls
+		for _, u := range self.closure(uses) {
+			calls += u.calls
+		}
+		if calls > MaxModuleCalls {
+			return tooManyCalls(src, name)
+		}
+		self.bindings[name.text] = b
+		return nil
+	}
+	program, calls := self.compile(node, params)
+	if calls > MaxModuleCalls {
+		return tooManyCalls(src, name)
+	}
+	var missing []string
+	for _, slot := range program.Slots()[len(params):] {
+		if !strings.HasPrefix(slot, bindingPrefix) {
+			missing = append(missing, slot)
+		}
+	}
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		return newParseError(src, name.pos, name.text,
+			fmt.Errorf("%w in function %v: %v", ErrUnknownVariable, name.text, strings.Join(missing, ", ")))
+	}
+	// the arguments are copied into a buffer with room for the bindings
+	buffers := newArgPool(len(program.Slots()))
+	self.functions.RegisterVariadic(name.text, len(params), len(params), func(args []float64) float64 {
+		buf := buffers.Get().(*[]float64)
+		vars := *buf
+		copy(vars, args)
+		r := program.Evaluate(vars)
+		buffers.Put(buf)
+		return r
+	})
+	f, _ := self.functions.Lookup(name.text)
+	self.calls[f] = calls + 1
+	self.names = append(self.names, name.text)
+	return nil
+}
+
+func tooManyCalls(src string, name token) error {
+	return newParseError(src, name.pos, name.text,
+		fmt.Errorf("%w: %v makes more than %v calls", ErrRecursion, name.text, MaxModuleCalls))
+}
+
+// splitDefinition splits a statement of the form name = body or name(params) = body. params is nil for bindings, and
+// name is a tokenEOF if the statement isn't a definition.
+func splitDefinition(src string, statement []token) (name token, params []string, body []token, err error) {
+	if statement[0].kind != tokenIdent {
+		return token{kind: tokenEOF}, nil, nil, nil
+	}
+	i := 1
+	if statement[i].text == "(" {
+		end := i
+		for statement[end].kind != tokenEOF && statement[end].text != ")" {
+			end++
+		}
+		if statement[end].kind == tokenEOF || statement[end+1].text != "=" {
+			return token{kind: tokenEOF}, nil, nil, nil
+		}
+		params = []string{}
+		for j := i + 1; j < end; j++ {
+			if len(params) > 0 {
+				if statement[j].text != "," {
+					return token{}, nil, nil, newParseError(src, statement[j].pos, statement[j].text,
+						fmt.Errorf("%w: unexpected %v in the parameters of %v", ErrSyntax, statement[j], statement[0].text),
+						`","`, `")"`)
+				}
+				j++
+			}
+			if statement[j].kind != tokenIdent {
+				return token{}, nil, nil, newParseError(src, statement[j].pos, statement[j].text,
+					fmt.Errorf("%w: unexpected %v in the parameters of %v", ErrSyntax, statement[j], statement[0].text),
+					"identifier")
+			}
+			params = append(params, statement[j].text)
+		}
+		i = end + 1
+	}
+	if statement[i].text != "=" {
+		return token{kind: tokenEOF}, nil, nil, nil
+	}
+	return statement[0], params, statement[i+1:], nil
+}
+
+// link replaces the bindings used by n with variables named after their slots, except for the names in hidden, and adds
+// them to uses
+func (self *Module) link(n *Node, hidden []string, uses map[*binding]bool) *Node {
+	switch n.op {
+	case ATOM:
+		if l := n.data.(*Literal); l.variable != "" && !slices.Contains(hidden, l.variable) {
+			if b, ok := self.bindings[l.variable]; ok {
+				uses[b] = true
+				return NewVarNode(bindingPrefix + l.variable)
+			}
+		}
+		return n
+	case FUNC:
+		f := n.data.(*builtinFunc)
+		params := make([]*Node, len(f.params))
+		for i, p := range f.params {
+			params[i] = self.link(p, hidden, uses)
+		}
+		return NewFunctionNode(&builtinFunc{id: f.id, fn: f.fn, params: params})
+	default:
+		nodes := make([]*Node, len(n.nodes))
+		for i, c := range n.nodes {
+			nodes[i] = self.link(c, hidden, uses)
+		}
+		return &Node{op: n.op, nodes: nodes, data: n.data}
+	}
+}
+
+// countCalls returns the expanded number of calls of the module's functions that n makes
+func (self *Module) countCalls(n *Node) int {
+	calls := 0
+	n.walk(func(n *Node) {
+		if f, ok := n.data.(*builtinFunc); ok {
+			calls += self.calls[f.fn]
+		}
+	})
+	return calls
+}
+
+// closure returns the bindings in uses and the bindings they use in turn, in order of definition. It adds them to uses.
+func (self *Module) closure(uses map[*binding]bool) []*binding {
+	var needed []*binding
+	for pending := slices.Collect(maps.Keys(uses)); len(pending) > 0; {
+		b := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		needed = append(needed, b)
+		for _, u := range b.uses {
+			if !uses[u] {
+				uses[u] = true
+				pending = append(pending, u)
+			}
+		}
+	}
+	slices.SortFunc(needed, func(a, b *binding) int { return a.index - b.index })
+	return needed
+}
+
+// compile compiles node with params in the first slots. The bindings it uses, directly or through other bindings, are
+// evaluated into their slots before node, in order of definition, so the values of params and variables must be
+// followed by room for them. calls is the expanded number of calls of the module's functions in an evaluation.
+func (self *Module) compile(node *Node, params []string) (program *Program, calls int) {
+	uses := make(map[*binding]bool)
+	linked := self.link(node, params, uses)
+	needed := self.closure(uses)
+	calls = self.countCalls(node)
+	for _, b := range needed {
+		calls += b.calls
+	}
+
+	p := &Program{slots: slices.Clone(params)}
+	type let struct {
+		slot int
+		eval evalFunc
+	}
+	lets := make([]let, len(needed))
+	for i, b := range needed {
+		eval, _ := p.compile(b.linked)
+		lets[i] = let{slot: p.slot(bindingPrefix + b.name), eval: eval}
+	}
+	body, _ := p.compile(linked)
+	p.eval = func(vars []float64) float64 {
+		for _, l := range lets {
+			vars[l.slot] = l.eval(vars)
+		}
+		return body(vars)
+	}
+	return p, calls
+}
+
+// Func returns the function called name as a Go function. It panics if it's called with the wrong number of arguments.
+func (self *Module) Func(name string) (func(args ...float64) float64, bool) {
+	if !slices.Contains(self.names, name) {
+		return nil, false
+	}
+	f, _ := self.functions.Lookup(name)
+	return func(args ...float64) float64 {
+		if len(args) != f.MinParams {
+			panic(fmt.Sprintf("%v takes %v arguments, got %v", name, f.MinParams, len(args)))
+		}
+		return f.Call(args...)
+	}, true
+}
+
+// FunctionNames returns the names of the functions defined by the module, in order of definition
+func (self *Module) FunctionNames() []string {
+	return slices.Clone(self.names)
+}
+
+// Functions returns a copy of the parser's functions with the module's functions added, for parsing other expressions
+// that use them
+func (self *Module) Functions() *FunctionSet {
+	return self.functions.Clone()
+}
+
+// Binding returns the value bound to name as it was written, which uses other bindings by name
+func (self *Module) Binding(name string) (*Node, bool) {
+	if b, ok := self.bindings[name]; ok {
+		return b.node, true
+	}
+	return nil, false
+}
+
+// Result returns the expression at the end of the module as it was written, or nil if it ends with a definition
+func (self *Module) Result() *Node {
+	return self.result
+}
+
+// Variables returns the free variables of the result, including the ones it uses through bindings, sorted
+func (self *Module) Variables() []string {
+	if self.program == nil {
+		return nil
+	}
+	var variables []string
+	for _, slot := range self.program.Slots() {
+		if !strings.HasPrefix(slot, bindingPrefix) {
+			variables = append(variables, slot)
+		}
+	}
+	slices.Sort(variables)
+	return variables
+}
+
+// Evaluate evaluates the result of the module
+func (self *Module) Evaluate(vars map[string]float64) (float64, error) {
+	if self.program == nil {
+		return 0, errors.New("The module has no result expression")
+	}
+	return self.program.Evaluate(self.program.Bind(vars, nil)), nil
+}
@@ -0,0 +1,261 @@
+package matheval
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Lundis/go-gmath/fastmath"
+)
+
+func TestModule(t *testing.T) {
+	m, err := ParseModule("f(x) = x^2 + 1; g = f(level) * 3; g + f(2)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := m.Evaluate(map[string]float64{"level": 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != 20 {
+		t.Errorf("Expected 20, got %v", result)
+	}
+	f, ok := m.Func("f")
+	if !ok {
+		t.Fatal("f is missing")
+	}
+	if v := f(3); v != 10 {
+		t.Errorf("Expected f(3) = 10, got %v", v)
+	}
+	if _, ok := m.Func("g"); ok {
+		t.Error("The binding g was returned as a function")
+	}
+	if s := m.Result().String(); s != "g + f(2)" {
+		t.Errorf("Expected the result g + f(2), got %v", s)
+	}
+	if g, ok := m.Binding("g"); !ok || g.String() != "f(level) * 3" {
+		t.Errorf("Expected g = f(level) * 3, got %v", g)
+	}
+}
+
+func TestModuleScoping(t *testing.T) {
+	src := `
+		scale = 2;
+		offset = scale + 1;
+		lengthSq(x, y) = x^2 + y^2;
+		dist(x, y) = sqrt(lengthSq(x, y)) * scale;
+		shadow(scale) = scale + offset;
+		none() = pi;
+	`
+	m, err := ParseModule(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := m.FunctionNames(); fmt.Sprint(names) != "[lengthSq dist shadow none]" {
+		t.Errorf("Unexpected functions %v", names)
+	}
+	if m.Result() != nil {
+		t.Errorf("Expected no result, got %v", m.Result())
+	}
+	if _, err := m.Evaluate(nil); err == nil {
+		t.Error("Evaluated a module without a result")
+	}
+	tests := map[string]struct {
+		args     []float64
+		expected float64
+	}{
+		"lengthSq": {[]float64{3, 4}, 25},
+		"dist":     {[]float64{3, 4}, 10},
+		"shadow":   {[]float64{10}, 13},
+		"none":     {nil, math.Pi},
+	}
+	for name, test := range tests {
+		f, ok := m.Func(name)
+		if !ok {
+			t.Fatalf("%v is missing", name)
+		}
+		if v := f(test.args...); !fastmath.Equald(v, test.expected, 1e-9) {
+			t.Errorf("%v%v: expected %v, got %v", name, test.args, test.expected, v)
+		}
+	}
+
+	// other expressions can use the module's functions
+	node, err := Parser{Functions: m.Functions()}.Parse("dist(1, 0) + sin(0)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := node.Evaluate(nil); v != 2 {
+		t.Errorf("Expected 2, got %v", v)
+	}
+	if _, err := Parse("dist(1, 0)"); !errors.Is(err, ErrUnknownFunction) {
+		t.Errorf("The module changed the default library: %v", err)
+	}
+}
+
+func TestModuleLibrary(t *testing.T) {
+	funcs := DefaultFunctions()
+	funcs.Register1("double", func(x float64) float64 { return 2 * x })
+	m, err := Parser{Functions: funcs, ImplicitMultiplication: true}.ParseModule("sin(x) = 2x; h(x) = double(sin(x)); h(3)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := m.Evaluate(nil); v != 12 {
+		t.Errorf("Expected 12, got %v", v)
+	}
+	// the library of the parser isn't changed
+	if _, ok := funcs.Lookup("h"); ok {
+		t.Error("The module registered h in the parser's functions")
+	}
+}
+
+func TestModuleErrors(t *testing.T) {
+	tests := map[string]error{
+		"f(x) = f(x - 1)":         ErrRecursion,
+		"f(x) = x; f(y) = y":      ErrSyntax,
+		"a = 1; a = 2":            ErrSyntax,
+		"f(x, x) = x":             ErrSyntax,
+		"1 + 2; a = 3":            ErrSyntax,
+		"f(x) = x + level":        ErrUnknownVariable,
+		"a = level; f(x) = x * a": ErrUnknownVariable,
+		"f(x) = g(x); g(x) = x":   ErrUnknownFunction,
+		"f(x) = x; f(1, 2)":       ErrArity,
+		"f(x) = ; 1":              ErrSyntax,
+		"f(x) = 1; f(1) = 2":      ErrSyntax,
+		"a = (1; 2)":              ErrUnbalancedParentheses,
+		"f(x) = x $ 2":            ErrSyntax,
+		"a == 1 = 2":              ErrSyntax,
+	}
+	for src, expected := range tests {
+		if _, err := ParseModule(src); !errors.Is(err, expected) {
+			t.Errorf("%v: expected %v, got %v", src, expected, err)
+		}
+	}
+
+	var perr *ParseError
+	_, err := ParseModule("f(x) = x;\ng(y) = f(y) + g(y)")
+	if !errors.As(err, &perr) || perr.Line != 2 || perr.Column != 15 {
+		t.Errorf("Expected an error at line 2, column 15, got %+v", err)
+	}
+	// the error points at the expression that should have been last
+	_, err = ParseModule("a = 1; 2 + 3; 4")
+	if !errors.As(err, &perr) || perr.Column != 8 || perr.Token != "2" {
+		t.Errorf("Expected an error at column 8, got %+v", err)
+	}
+
+	params := map[string]string{
+		"f(x y) = x + y": "y",
+		"f(x,) = x":      ")",
+		"f(, x) = x":     ",",
+		"f(x,, y) = x":   ",",
+		"f(1) = 1":       "1",
+	}
+	for src, token := range params {
+		_, err := ParseModule(src)
+		if !errors.As(err, &perr) || !errors.Is(err, ErrSyntax) || perr.Token != token {
+			t.Errorf("%v: expected a syntax error at %q, got %+v", src, token, err)
+		}
+	}
+}
+
+func TestModuleShadowing(t *testing.T) {
+	m, err := ParseModule("level = max(level, 1); scale = level * 2; level + scale")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for level, expected := range map[float64]float64{-5: 3, 4: 12} {
+		if v, _ := m.Evaluate(map[string]float64{"level": level}); v != expected {
+			t.Errorf("level %v: expected %v, got %v", level, expected, v)
+		}
+	}
+	if vars := m.Variables(); fmt.Sprint(vars) != "[level]" {
+		t.Errorf("Expected the variable level, got %v", vars)
+	}
+}
+
+func TestModuleDepth(t *testing.T) {
+	var src strings.Builder
+	src.WriteString("f0(x) = x + 1;")
+	for i := 1; i < MaxModuleDepth; i++ {
+		fmt.Fprintf(&src, "f%v(x) = f%v(x) + 1;", i, i-1)
+	}
+	m, err := ParseModule(src.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, _ := m.Func(fmt.Sprintf("f%v", MaxModuleDepth-1))
+	if v := f(0); v != MaxModuleDepth {
+		t.Errorf("Expected %v, got %v", MaxModuleDepth, v)
+	}
+	fmt.Fprintf(&src, "a = f%v(1)", MaxModuleDepth-1)
+	if _, err := ParseModule(src.String()); !errors.Is(err, ErrRecursion) {
+		t.Errorf("Expected a recursion error, got %v", err)
+	}
+}
+
+func TestModuleCalls(t *testing.T) {
+	// every function calls the previous one twice, so a call of fN makes 2^(N+1) - 1 calls
+	var src strings.Builder
+	src.WriteString("f0(x) = x + 1;")
+	for i := 1; i <= 18; i++ {
+		fmt.Fprintf(&src, "f%v(x) = f%v(x) + f%v(x);", i, i-1, i-1)
+	}
+	m, err := ParseModule(src.String() + "f18(1) + f18(1)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := m.Evaluate(nil); v != 0x1p20 {
+		t.Errorf("Expected %v, got %v", 0x1p20, v)
+	}
+	tests := []string{
+		"f19(x) = f18(x) + f18(x); f20(x) = f19(x) + f19(x)",
+		"a = f18(1) + f18(2) + f18(3)",
+		"a = f18(1); b = f18(2); c = f18(3); a + b + c",
+		"f18(1) + f18(2) + f18(3)",
+	}
+	for _, test := range tests {
+		if _, err := ParseModule(src.String() + test); !errors.Is(err, ErrRecursion) {
+			t.Errorf("%v: expected a recursion error, got %v", test, err)
+		}
+	}
+}
+
+func TestModuleBindingsAreEvaluatedOnce(t *testing.T) {
+	// every binding uses the previous one twice, so inlining them would take 2^60 steps
+	var src strings.Builder
+	src.WriteString("a0 = 1; b0 = level;")
+	for i := 1; i <= 60; i++ {
+		fmt.Fprintf(&src, "a%v = a%v + a%v; b%v = b%v + b%v;", i, i-1, i-1, i, i-1, i-1)
+	}
+	src.WriteString("f(x) = x * a60; b60 + f(1)")
+	start := time.Now()
+	m, err := ParseModule(src.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := m.Evaluate(map[string]float64{"level": 2}); v != 0x1p61+0x1p60 {
+		t.Errorf("Expected %v, got %v", 0x1p61+0x1p60, v)
+	}
+	if vars := m.Variables(); fmt.Sprint(vars) != "[level]" {
+		t.Errorf("Expected the variable level, got %v", vars)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Took %v", elapsed)
+	}
+
+	calls := 0
+	funcs := DefaultFunctions()
+	funcs.RegisterVariadic("count", 0, 0, func([]float64) float64 {
+		calls++
+		return 1
+	})
+	m, err = Parser{Functions: funcs}.ParseModule("c = count(); unused = count(); f(x) = x * c + c; c + c + f(c)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := m.Evaluate(nil); v != 4 || calls != 2 {
+		t.Errorf("Expected 4 from 2 calls, got %v from %v calls", v, calls)
+	}
+}
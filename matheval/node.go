@@ -1,9 +1,7 @@
 package matheval
 
 import (
-	"fmt"
 	"math"
-	"strings"
 )
 
 type Operator int
@@ -15,6 +13,22 @@ const (
 	DIV
 	ATOM
 	FUNC
+	POW
+	// the comparisons LT, LE, GT, GE, EQ and NE give 1 if they're true and 0 otherwise
+	LT
+	LE
+	GT
+	GE
+	EQ
+	NE
+	// the logical operators AND, OR and NOT treat 0 as false and anything else as true, and give 1 or 0
+	AND
+	OR
+	NOT
+	// COND is the conditional operator c ? a : b
+	COND
+	// COMPONENT reads the x, y or z component of a vector in a TypedExpr
+	COMPONENT
 )
 
 type Node struct {
@@ -23,33 +37,6 @@ type Node struct {
 	data  Atom
 }
 
-func (self *Node) childrenStrings() []string {
-	strs := make([]string, len(self.nodes))
-	for i := range strs {
-		strs[i] = self.nodes[i].String()
-	}
-	return strs
-}
-
-func (self *Node) String() string {
-	switch self.op {
-	case PLUS:
-		return "(" + strings.Join(self.childrenStrings(), " + ") + ")"
-	case MINUS:
-		return " - (" + self.nodes[0].String() + ")"
-	case MULT:
-		return strings.Join(self.childrenStrings(), " * ")
-	case DIV:
-		return self.nodes[0].String() + " / " + self.nodes[1].String()
-	case ATOM:
-		return self.data.String()
-	case FUNC:
-		return self.data.String() + strings.Join(self.childrenStrings(), ", ") + ")"
-	default:
-		panic(fmt.Sprintf("Unknown NodeImpl type in String(): %v", self.op))
-	}
-}
-
 func NewPlusNode(nodes []*Node) *Node {
 	n := new(Node)
 	n.op = PLUS
@@ -82,6 +69,51 @@ func NewDivNode(n1, n2 *Node) *Node {
 	return m
 }
 
+// NewPowNode raises base to the power of exponent
+func NewPowNode(base, exponent *Node) *Node {
+	m := new(Node)
+	m.op = POW
+	m.nodes = []*Node{base, exponent}
+	return m
+}
+
+// NewComparisonNode compares a and b. op must be one of LT, LE, GT, GE, EQ and NE.
+func NewComparisonNode(op Operator, a, b *Node) *Node {
+	m := new(Node)
+	m.op = op
+	m.nodes = []*Node{a, b}
+	return m
+}
+
+func NewAndNode(a, b *Node) *Node {
+	m := new(Node)
+	m.op = AND
+	m.nodes = []*Node{a, b}
+	return m
+}
+
+func NewOrNode(a, b *Node) *Node {
+	m := new(Node)
+	m.op = OR
+	m.nodes = []*Node{a, b}
+	return m
+}
+
+func NewNotNode(n *Node) *Node {
+	m := new(Node)
+	m.op = NOT
+	m.nodes = []*Node{n}
+	return m
+}
+
+// NewCondNode creates a conditional that gives a if condition isn't 0, and b otherwise
+func NewCondNode(condition, a, b *Node) *Node {
+	m := new(Node)
+	m.op = COND
+	m.nodes = []*Node{condition, a, b}
+	return m
+}
+
 func NewFunctionNode(a Atom) *Node {
 	n := new(Node)
 	n.op = FUNC
@@ -89,7 +121,12 @@ func NewFunctionNode(a Atom) *Node {
 	return n
 }
 
+// NewLiteralNode creates a number. Negative numbers become a negated literal like the parser produces for them,
+// so that the tree prints in a form that parses back to the same tree.
 func NewLiteralNode(num float64) *Node {
+	if math.Signbit(num) {
+		return NewMinusNode(NewLiteralNode(-num))
+	}
 	m := new(Node)
 	m.op = ATOM
 	m.data = NewLiteralVal(num)
@@ -120,15 +157,32 @@ func (self *Node) Evaluate(vars map[string]float64) float64 {
 		}
 		return prod
 	case DIV:
-		first := self.nodes[0].Evaluate(vars)
-		second := self.nodes[1].Evaluate(vars)
-		if second == 0 {
-			return math.Copysign(math.Inf(1), first)
+		return divide(self.nodes[0].Evaluate(vars), self.nodes[1].Evaluate(vars))
+	case POW:
+		return math.Pow(self.nodes[0].Evaluate(vars), self.nodes[1].Evaluate(vars))
+	case LT:
+		return boolValue(self.nodes[0].Evaluate(vars) < self.nodes[1].Evaluate(vars))
+	case LE:
+		return boolValue(self.nodes[0].Evaluate(vars) <= self.nodes[1].Evaluate(vars))
+	case GT:
+		return boolValue(self.nodes[0].Evaluate(vars) > self.nodes[1].Evaluate(vars))
+	case GE:
+		return boolValue(self.nodes[0].Evaluate(vars) >= self.nodes[1].Evaluate(vars))
+	case EQ:
+		return boolValue(self.nodes[0].Evaluate(vars) == self.nodes[1].Evaluate(vars))
+	case NE:
+		return boolValue(self.nodes[0].Evaluate(vars) != self.nodes[1].Evaluate(vars))
+	case AND:
+		return boolValue(self.nodes[0].Evaluate(vars) != 0 && self.nodes[1].Evaluate(vars) != 0)
+	case OR:
+		return boolValue(self.nodes[0].Evaluate(vars) != 0 || self.nodes[1].Evaluate(vars) != 0)
+	case NOT:
+		return boolValue(self.nodes[0].Evaluate(vars) == 0)
+	case COND:
+		if self.nodes[0].Evaluate(vars) != 0 {
+			return self.nodes[1].Evaluate(vars)
 		}
-		if math.IsInf(second, 0) {
-			return 0
-		}
-		return first / second
+		return self.nodes[2].Evaluate(vars)
 	case ATOM:
 		fallthrough
 	case FUNC:
@@ -137,3 +191,21 @@ func (self *Node) Evaluate(vars map[string]float64) float64 {
 		return 0
 	}
 }
+
+// divide divides a by b, giving an infinity with the sign of a when b is 0 and 0 when b is infinite
+func divide(a, b float64) float64 {
+	if b == 0 {
+		return math.Copysign(math.Inf(1), a)
+	}
+	if math.IsInf(b, 0) {
+		return 0
+	}
+	return a / b
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -1,196 +1,352 @@
 package matheval
 
 import (
-	"errors"
 	"fmt"
+	"slices"
 	"strconv"
-	"strings"
 )
 
+// Parser parses expressions. The zero value parses the default syntax.
+//
+// Operators, from lowest to highest precedence, are the conditional c ? a : b, then ||, &&, == and !=, the comparisons
+// <, <=, > and >=, + and -, * and /, the unary -, + and !, and last ^. This is the same order as in C, with ^ added.
+// Binary operators are left-associative except for ^, so a/b/c is (a/b)/c and a^b^c is a^(b^c).
+// Unary operators may appear anywhere an operand is expected, as in 2*-3 or 2^-x, and -x^2 is -(x^2).
+// Comparisons and logical operators give 1 for true and 0 for false, and treat any value except 0 as true.
+// &&, || and the conditional only evaluate the operands they need. if(c, a, b) is another way to write c ? a : b.
+type Parser struct {
+	// ImplicitMultiplication allows leaving out * between factors, as in "2x", "2 pi" or "3(a + b)".
+	// Factors after the first must be identifiers or parenthesized, so "2 3" is an error rather than 6.
+	// An identifier directly followed by parentheses is always a function call.
+	ImplicitMultiplication bool
+	// Functions are the functions that expressions can call. The default library is used if it's nil.
+	Functions *FunctionSet
+}
+
+// Parse parses expr with the default syntax
 func Parse(expr string) (*Node, error) {
-	// remove spaces
-	chars := strings.Replace(expr, " ", "", -1)
-	top_node, err := parse(chars)
-	return top_node, err
+	return Parser{}.Parse(expr)
 }
 
-func parse(expr string) (*Node, error) {
-	if len(expr) == 0 {
-		return nil, errors.New("Syntax Error!")
-	}
-	return parsePlus(expr)
+func (self Parser) Parse(expr string) (*Node, error) {
+	return self.parse(expr, false)
 }
 
-func parsePlus(expr string) (*Node, error) {
-	plus, minus := tokenize(expr, '+', '-')
-	if len(plus) == 1 && len(minus) == 0 {
-		// Nothing was found, move on to multiplication
-		return parseMult(expr)
-	}
-	nodes_plus, err := parseExpressions(plus)
+// parse parses expr, with component access and the vectorFunctions if typed is true
+func (self Parser) parse(expr string, typed bool) (*Node, error) {
+	tokens, err := lex(expr)
 	if err != nil {
 		return nil, err
 	}
-	nodes_minus, err := parseExpressions(minus)
+	return self.parseTokens(expr, tokens, typed)
+}
+
+// parseTokens parses the tokens of an expression in expr, which end with a tokenEOF
+func (self Parser) parseTokens(expr string, tokens []token, typed bool) (*Node, error) {
+	if self.Functions == nil {
+		self.Functions = defaultFunctions
+	}
+	p := parser{Parser: self, expr: expr, tokens: tokens, typed: typed}
+	node, err := p.parseExpression()
 	if err != nil {
 		return nil, err
 	}
+	if t := p.peek(); t.kind != tokenEOF {
+		if t.text == ")" {
+			return nil, p.errorAt(t, fmt.Errorf("%w: unmatched %v", ErrUnbalancedParentheses, t))
+		}
+		return nil, p.unexpected(t, "operator", "end of expression")
+	}
+	return node, nil
+}
+
+type parser struct {
+	Parser
+	expr   string
+	tokens []token
+	i      int
+	// depth is the number of open parentheses
+	depth int
+	typed bool
+}
 
-	for _, node := range nodes_minus {
-		nodes_plus = append(nodes_plus, NewMinusNode(node))
+// operandTokens are the tokens that can start an operand
+var operandTokens = []string{"number", "identifier", `"("`, `"-"`, `"+"`, `"!"`}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokenEOF {
+		p.i++
 	}
-	return NewPlusNode(nodes_plus), nil
+	return t
+}
 
+// accept consumes the next token if it is the operator op
+func (p *parser) accept(op string) bool {
+	if t := p.peek(); t.kind == tokenOperator && t.text == op {
+		p.i++
+		return true
+	}
+	return false
 }
 
-// Parses multiplication and division
-func parseMult(expr string) (*Node, error) {
-	mult, div := tokenize(expr, '*', '/')
-	if len(mult) == 1 && len(div) == 0 {
-		return parseParentheses(expr)
+// expect consumes the operator op, or returns an error listing the expected operators
+func (p *parser) expect(op string, expected ...string) error {
+	if p.accept(op) {
+		return nil
+	}
+	t := p.peek()
+	if t.kind == tokenEOF && p.depth > 0 {
+		return p.errorAt(t, fmt.Errorf("%w: missing \")\"", ErrUnbalancedParentheses), expected...)
 	}
-	nodes_mult, err := parseExpressions(mult)
+	return p.unexpected(t, expected...)
+}
+
+func (p *parser) unexpected(t token, expected ...string) error {
+	return p.errorAt(t, fmt.Errorf("%w: unexpected %v", ErrSyntax, t), expected...)
+}
+
+func (p *parser) errorAt(t token, err error, expected ...string) error {
+	return newParseError(p.expr, t.pos, t.text, err, expected...)
+}
+
+// parseExpression parses a conditional expression, which is right-associative
+func (p *parser) parseExpression() (*Node, error) {
+	condition, err := p.parseBinary(precedenceOr)
 	if err != nil {
 		return nil, err
 	}
-	n1 := nodes_mult[0]
-	if len(nodes_mult) != 1 {
-		n1 = NewMultNode(nodes_mult)
+	if !p.accept("?") {
+		return condition, nil
 	}
+	a, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":", `":"`); err != nil {
+		return nil, err
+	}
+	b, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	return NewCondNode(condition, a, b), nil
+}
 
-	if len(div) == 0 {
-		return n1, nil
-	} else {
-		nodes_div, err := parseExpressions(div)
-		if err != nil {
-			return nil, err
+// binaryOperators maps binary operators to their precedence and the operator of their node.
+// + and - are both PLUS, as a - b is a + -b.
+var binaryOperators = map[string]struct {
+	precedence int
+	op         Operator
+}{
+	"||": {precedenceOr, OR},
+	"&&": {precedenceAnd, AND},
+	"==": {precedenceEquality, EQ},
+	"!=": {precedenceEquality, NE},
+	"<":  {precedenceComparison, LT},
+	"<=": {precedenceComparison, LE},
+	">":  {precedenceComparison, GT},
+	">=": {precedenceComparison, GE},
+	"+":  {precedenceSum, PLUS},
+	"-":  {precedenceSum, PLUS},
+	"*":  {precedenceProduct, MULT},
+	"/":  {precedenceProduct, DIV},
+}
+
+// binaryPrecedence returns the operator that the next token continues an expression with, and its precedence.
+// The precedence is 0 if the next token isn't a binary operator. implicit is true for implicit multiplication,
+// where the token is the start of the next factor and must not be consumed.
+func (p *parser) binaryPrecedence() (op string, precedence int, implicit bool) {
+	t := p.peek()
+	switch t.kind {
+	case tokenOperator:
+		if b, ok := binaryOperators[t.text]; ok {
+			return t.text, b.precedence, false
+		}
+		if t.text == "(" && p.ImplicitMultiplication {
+			return "*", precedenceProduct, true
 		}
-		n2 := nodes_div[0]
-		if len(div) != 1 {
-			n2 = NewMultNode(nodes_div)
+	case tokenIdent:
+		// a number can't be a later factor, so that "1 000" is an error rather than 1 * 0
+		if p.ImplicitMultiplication {
+			return "*", precedenceProduct, true
 		}
-		div_node := NewDivNode(n1, n2)
-		return div_node, nil
 	}
+	return "", 0, false
 }
 
-func parseParentheses(expr string) (*Node, error) {
-	if expr[0] == '(' && expr[len(expr)-1] == ')' {
-		return parse(expr[1 : len(expr)-1])
-	} else {
-		return parseAtom(expr)
+// parseBinary parses operands joined by binary operators with at least minPrecedence using precedence climbing.
+// Chains of + and - become a single PLUS node and chains of * a single MULT node.
+func (p *parser) parseBinary(minPrecedence int) (*Node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	// chained is true when left is a PLUS or MULT node built by this loop, which further operands are added to
+	chained := false
+	for {
+		op, precedence, implicit := p.binaryPrecedence()
+		if precedence == 0 || precedence < minPrecedence {
+			return left, nil
+		}
+		if !implicit {
+			p.next()
+		}
+		right, err := p.parseBinary(precedence + 1)
+		if err != nil {
+			return nil, err
+		}
+		switch op {
+		case "+", "-":
+			if op == "-" {
+				right = NewMinusNode(right)
+			}
+			if chained && left.op == PLUS {
+				left.nodes = append(left.nodes, right)
+			} else {
+				left = NewPlusNode([]*Node{left, right})
+			}
+			chained = true
+		case "*":
+			if chained && left.op == MULT {
+				left.nodes = append(left.nodes, right)
+			} else {
+				left = NewMultNode([]*Node{left, right})
+			}
+			chained = true
+		default:
+			left = &Node{op: binaryOperators[op].op, nodes: []*Node{left, right}}
+			chained = false
+		}
 	}
 }
 
-func parseAtom(expr string) (*Node, error) {
-	if strings.Contains(expr, "(") {
-		return parseFunction(expr)
-	} else if '0' <= expr[0] && expr[0] <= '9' || expr[0] == '.' {
-		return parseNumber(expr)
-	} else {
-		return parseVariable(expr)
+func (p *parser) parseUnary() (*Node, error) {
+	if p.accept("-") {
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return NewMinusNode(n), nil
+	}
+	if p.accept("+") {
+		return p.parseUnary()
 	}
+	if p.accept("!") {
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return NewNotNode(n), nil
+	}
+	return p.parsePower()
 }
 
-func parseFunction(expr string) (*Node, error) {
-	first_par := strings.Index(expr, "(")
-	id := expr[:first_par]
-	if first_par+1 > len(expr)-1 {
-		return nil, errors.New(fmt.Sprintf("Function %v has no parameters", id))
-	}
-	internals, err := parseGroup(expr[first_par+1 : len(expr)-1])
+// parsePower parses a factor with an optional exponent. The exponent is parsed as a unary expression,
+// which makes ^ right-associative and binds it tighter than a unary minus in front of the base.
+func (p *parser) parsePower() (*Node, error) {
+	base, err := p.parsePrimary()
 	if err != nil {
 		return nil, err
 	}
-	a, err := newBuiltinFunc(id, internals)
+	if !p.accept("^") {
+		return base, nil
+	}
+	exponent, err := p.parseUnary()
 	if err != nil {
 		return nil, err
 	}
-	return NewFunctionNode(a), nil
+	return NewPowNode(base, exponent), nil
 }
 
-// a group is a set of nodes separated by commas
-func parseGroup(expr string) ([]*Node, error) {
-	if len(expr) == 0 {
-		return nil, errors.New("Empty parameter list")
-	}
-	exprs, _ := tokenize(expr, ',', ',')
-	return parseExpressions(exprs)
-}
-
-func parseNumber(expr string) (*Node, error) {
-
-	num, err := strconv.ParseFloat(expr, 64)
+// parsePrimary parses an operand, followed by component accesses such as v.x in typed expressions
+func (p *parser) parsePrimary() (*Node, error) {
+	n, err := p.parseOperand()
 	if err != nil {
-		return nil, errors.New(fmt.Sprintf("Failed to convert %v to a number", expr))
-	} else {
-		return NewLiteralNode(num), nil
+		return nil, err
 	}
-}
-
-func parseVariable(expr string) (*Node, error) {
-	for _, c := range expr {
-		if !(('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')) {
-			message := fmt.Sprintf("Variable %v is not an alphabetic sentence.", string(expr))
-			return nil, errors.New(message)
+	for p.typed && p.accept(".") {
+		t := p.next()
+		c := slices.Index(componentNames, t.text)
+		if t.kind != tokenIdent || c < 0 {
+			return nil, p.unexpected(t, `"x"`, `"y"`, `"z"`)
 		}
+		n = &Node{op: COMPONENT, nodes: []*Node{n}, data: component(c)}
 	}
-	return NewVarNode(expr), nil
+	return n, nil
 }
 
-// Returns substrings split by op1 and op2, considering parentheses.
-// Special case: if op1 == op2, only op1 will be considered
-func tokenize(expr string, op1, op2 rune) (indices1 []string, indices2 []string) {
-	depth := 0
-	indices1 = make([]string, 0, len(expr)/2)
-	indices2 = make([]string, 0, len(expr)/2)
-	start := 0
-	which := 1
-	// this is for the special case when there's a minus first
-	if rune(expr[0]) == op2 && op2 == '-' {
-		which = 2
-		expr = expr[1:]
+func (p *parser) parseOperand() (*Node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenNumber:
+		num, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, p.errorAt(t, fmt.Errorf("%w: %v", ErrBadNumber, t.text))
+		}
+		return NewLiteralNode(num), nil
+	case tokenIdent:
+		if p.accept("(") {
+			return p.parseFunction(t)
+		}
+		return NewVarNode(t.text), nil
+	case tokenOperator:
+		if t.text == "(" {
+			p.depth++
+			defer func() { p.depth-- }()
+			n, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")", `")"`); err != nil {
+				return nil, err
+			}
+			return n, nil
+		}
 	}
+	return nil, p.unexpected(t, operandTokens...)
+}
 
-	for i, v := range expr {
-		if v == '(' {
-			depth++
-		} else if v == ')' {
-			depth--
-		} else if depth == 0 && (v == op1 || v == op2) {
-			if which == 1 {
-				indices1 = append(indices1, expr[start:i])
-			} else {
-				indices2 = append(indices2, expr[start:i])
+// parseFunction parses the parameters of a function call whose opening parenthesis has been consumed
+func (p *parser) parseFunction(id token) (*Node, error) {
+	var params []*Node
+	p.depth++
+	defer func() { p.depth-- }()
+	if !p.accept(")") {
+		for {
+			n, err := p.parseExpression()
+			if err != nil {
+				return nil, err
 			}
-
-			if v == op1 {
-				which = 1
-			} else {
-				which = 2
+			params = append(params, n)
+			if p.accept(")") {
+				break
+			}
+			if err := p.expect(",", `","`, `")"`); err != nil {
+				return nil, err
 			}
-			start = i + 1
-
 		}
 	}
-	// last one
-	if which == 1 {
-		indices1 = append(indices1, expr[start:])
-	} else {
-		indices2 = append(indices2, expr[start:])
+	if id.text == "if" {
+		// if is a conditional rather than a function, so that only one of the branches is evaluated
+		if len(params) != 3 {
+			return nil, p.errorAt(id, fmt.Errorf("%w for function if: %v, expected 3", ErrArity, len(params)))
+		}
+		return NewCondNode(params[0], params[1], params[2]), nil
 	}
-
-	return indices1, indices2
-}
-
-func parseExpressions(exprs []string) ([]*Node, error) {
-	nodes := make([]*Node, len(exprs))
-	var err error
-	for i, expr := range exprs {
-		nodes[i], err = parse(expr)
-		if err != nil {
-			return nil, err
+	if fn, ok := vectorFunctions[id.text]; ok && p.typed {
+		if err := fn.checkArity(len(params)); err != nil {
+			return nil, p.errorAt(id, err)
 		}
+		return NewFunctionNode(&builtinFunc{id: id.text, fn: fn, params: params}), nil
 	}
-	return nodes, nil
+	a, err := newBuiltinFunc(p.Functions, id.text, params)
+	if err != nil {
+		return nil, p.errorAt(id, err)
+	}
+	return NewFunctionNode(a), nil
 }
@@ -1,12 +1,14 @@
 package matheval
 
 import (
+	"errors"
+	"math"
 	"testing"
 )
 
 // This test just makes sure that the expressions are parsed without throwing any errors.
 func TestParse(t *testing.T) {
-	exprs := []string{"1 + 3", "1 * 3", "3 * (1 + 3)", "1 / 5", "1 + 3 * (1 + 3) / 5", "1 000 000", "-1", "abs(-5)", "pow(x, 2)"}
+	exprs := []string{"1 + 3", "1 * 3", "3 * (1 + 3)", "1 / 5", "1 + 3 * (1 + 3) / 5", "-1", "abs(-5)", "pow(x, 2)"}
 	for _, v := range exprs {
 		expr, err := Parse(v)
 		if err != nil {
@@ -17,7 +19,8 @@ func TestParse(t *testing.T) {
 
 // Checks that the parser throws error for faulty expressions
 func TestParseFaulty(t *testing.T) {
-	exprs := []string{"1 +", "* 3", "() / 5", "(1 + 3 * (1 + 3) / 5", "() + ()", "sin(,x)", "abs(-5,)", "isudf(1)", "x^", "^x"}
+	exprs := []string{"1 +", "* 3", "() / 5", "(1 + 3 * (1 + 3) / 5", "() + ()", "sin(,x)", "abs(-5,)", "isudf(1)", "x^", "^x",
+		"1 000 000", "2 pi", "2x", "1e", "1..2", "x $ y", "(1 + 2))", "pow(1, 2"}
 	for _, v := range exprs {
 		expr, err := Parse(v)
 		if err == nil {
@@ -25,3 +28,61 @@ func TestParseFaulty(t *testing.T) {
 		}
 	}
 }
+
+func TestParseOperators(t *testing.T) {
+	vars := map[string]float64{"a": 2, "b": 8, "c": 4, "x": 3}
+	assertEquals(t, "a/b/c", 2.0/8/4, vars)
+	assertEquals(t, "b-a-c", 8-2-4, vars)
+	assertEquals(t, "a - (b - c)", 2-(8-4), vars)
+	assertEquals(t, "b/c*a", 8.0/4*2, vars)
+	assertEquals(t, "2^3^2", 512, nil)
+	assertEquals(t, "(2^3)^2", 64, nil)
+	assertEquals(t, "-2^2", -4, nil)
+	assertEquals(t, "(-2)^2", 4, nil)
+	assertEquals(t, "2^-1", 0.5, nil)
+	assertEquals(t, "2*-3", -6, nil)
+	assertEquals(t, "2 * +3", 6, nil)
+	assertEquals(t, "--x", 3, vars)
+	assertEquals(t, "1 - -x", 4, vars)
+	assertEquals(t, "2 * x^2 + 1", 19, vars)
+}
+
+func TestParseNumbers(t *testing.T) {
+	assertEquals(t, "1e-3", 0.001, nil)
+	assertEquals(t, "2.5E2", 250, nil)
+	assertEquals(t, "1e+2 * .5", 50, nil)
+}
+
+func TestParseImplicitMultiplication(t *testing.T) {
+	p := Parser{ImplicitMultiplication: true}
+	tests := map[string]float64{
+		"2 pi":        2 * math.Pi,
+		"2x":          6,
+		"2e":          2 * math.E,
+		"2x^2":        18,
+		"3(x + 1)":    12,
+		"(x + 1)(x)":  12,
+		"x abs(-2)":   6,
+		"1 / 2x":      1.5,
+		"-2x + 4":     -2,
+		"level_2 x":   21,
+		"2 sqrt(4) x": 12,
+	}
+	vars := map[string]float64{"x": 3, "pi": math.Pi, "level_2": 7}
+	for expr, expected := range tests {
+		node, err := p.Parse(expr)
+		if err != nil {
+			t.Errorf("Failed to parse %v: %v", expr, err)
+			continue
+		}
+		if result := node.Evaluate(vars); math.Abs(result-expected) > 1e-9 {
+			t.Errorf("Evaluated %v. Got %v. Expected %v", expr, result, expected)
+		}
+	}
+	// numbers are only implicitly multiplied when they come first
+	for _, expr := range []string{"2 3", "1 000 000", "x 2", "(x) 2"} {
+		if _, err := p.Parse(expr); !errors.Is(err, ErrSyntax) {
+			t.Errorf("%v: expected a syntax error, got %v", expr, err)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package matheval
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Printing precedences. A child is put in parentheses when its precedence is too low for where it appears.
+const (
+	precedenceConditional = iota + 1
+	precedenceOr
+	precedenceAnd
+	precedenceEquality
+	precedenceComparison
+	precedenceSum
+	precedenceProduct
+	precedenceUnary
+	precedencePower
+	precedencePrimary
+)
+
+func precedence(n *Node) int {
+	switch n.op {
+	case COND:
+		return precedenceConditional
+	case OR:
+		return precedenceOr
+	case AND:
+		return precedenceAnd
+	case EQ, NE:
+		return precedenceEquality
+	case LT, LE, GT, GE:
+		return precedenceComparison
+	case PLUS:
+		return precedenceSum
+	case MULT, DIV:
+		return precedenceProduct
+	case MINUS, NOT:
+		return precedenceUnary
+	case POW:
+		return precedencePower
+	}
+	return precedencePrimary
+}
+
+// String prints the expression in a canonical form with as few parentheses as possible.
+// Trees from Parse, Simplify and Derive parse back to the same tree, as long as all numbers in them are finite.
+func (self *Node) String() string {
+	var b strings.Builder
+	writeNode(&b, self)
+	return b.String()
+}
+
+func writeNode(b *strings.Builder, n *Node) {
+	switch n.op {
+	case PLUS:
+		for i, c := range n.nodes {
+			if i == 0 {
+				writeChild(b, c, precedence(c) <= precedenceSum)
+			} else if c.op == MINUS {
+				// the parser turns a - b into a + -b
+				b.WriteString(" - ")
+				writeChild(b, c.nodes[0], precedence(c.nodes[0]) <= precedenceSum)
+			} else {
+				b.WriteString(" + ")
+				writeChild(b, c, precedence(c) <= precedenceSum)
+			}
+		}
+	case MINUS:
+		b.WriteString("-")
+		writeChild(b, n.nodes[0], precedence(n.nodes[0]) < precedenceUnary)
+	case MULT:
+		for i, c := range n.nodes {
+			if i == 0 {
+				// a / b * c parses as (a / b) * c, but a * b * c would be a single product
+				writeChild(b, c, precedence(c) < precedenceProduct || c.op == MULT)
+			} else {
+				b.WriteString(" * ")
+				writeChild(b, c, precedence(c) <= precedenceProduct)
+			}
+		}
+	case DIV:
+		writeChild(b, n.nodes[0], precedence(n.nodes[0]) < precedenceProduct)
+		b.WriteString(" / ")
+		writeChild(b, n.nodes[1], precedence(n.nodes[1]) <= precedenceProduct)
+	case POW:
+		writeChild(b, n.nodes[0], precedence(n.nodes[0]) <= precedencePower)
+		b.WriteString("^")
+		writeChild(b, n.nodes[1], precedence(n.nodes[1]) < precedenceUnary)
+	case LT, LE, GT, GE, EQ, NE, AND, OR:
+		// left-associative like division
+		p := precedence(n)
+		writeChild(b, n.nodes[0], precedence(n.nodes[0]) < p)
+		b.WriteString(" " + operatorSymbols[n.op] + " ")
+		writeChild(b, n.nodes[1], precedence(n.nodes[1]) <= p)
+	case NOT:
+		b.WriteString("!")
+		writeChild(b, n.nodes[0], precedence(n.nodes[0]) < precedenceUnary)
+	case COND:
+		// right-associative, so only a conditional as the condition needs parentheses
+		writeChild(b, n.nodes[0], precedence(n.nodes[0]) <= precedenceConditional)
+		b.WriteString(" ? ")
+		writeNode(b, n.nodes[1])
+		b.WriteString(" : ")
+		writeNode(b, n.nodes[2])
+	case COMPONENT:
+		writeChild(b, n.nodes[0], precedence(n.nodes[0]) < precedencePrimary)
+		b.WriteString("." + n.data.String())
+	case ATOM:
+		b.WriteString(n.data.String())
+	case FUNC:
+		f := n.data.(*builtinFunc)
+		b.WriteString(f.id)
+		b.WriteString("(")
+		for i, p := range f.params {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writeNode(b, p)
+		}
+		b.WriteString(")")
+	default:
+		panic(fmt.Sprintf("Unknown NodeImpl type in String(): %v", n.op))
+	}
+}
+
+var operatorSymbols = map[Operator]string{
+	LT:  "<",
+	LE:  "<=",
+	GT:  ">",
+	GE:  ">=",
+	EQ:  "==",
+	NE:  "!=",
+	AND: "&&",
+	OR:  "||",
+}
+
+func writeChild(b *strings.Builder, n *Node, parentheses bool) {
+	if parentheses {
+		b.WriteString("(")
+	}
+	writeNode(b, n)
+	if parentheses {
+		b.WriteString(")")
+	}
+}
+
+func formatNumber(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,98 @@
+package matheval
+
+import (
+	"testing"
+)
+
+func TestStringRoundTrip(t *testing.T) {
+	exprs := map[string]string{
+		"1 + 2":               "1 + 2",
+		"1.25e-7 * x":         "1.25e-07 * x",
+		"a - b + c":           "a - b + c",
+		"a - (b + c)":         "a - (b + c)",
+		"(a + b) + c":         "(a + b) + c",
+		"a + (b + c)":         "a + (b + c)",
+		"a + -b":              "a - b",
+		"-a + b":              "-a + b",
+		"a * b * c":           "a * b * c",
+		"(a * b) * c":         "(a * b) * c",
+		"a * (b * c)":         "a * (b * c)",
+		"a / b * c":           "a / b * c",
+		"a * (b / c)":         "a * (b / c)",
+		"a * b / c":           "a * b / c",
+		"a / (b * c)":         "a / (b * c)",
+		"a / b / c":           "a / b / c",
+		"a / (b / c)":         "a / (b / c)",
+		"-(a * b)":            "-(a * b)",
+		"-a * b":              "-a * b",
+		"a * -b":              "a * -b",
+		"--a":                 "--a",
+		"-a^2":                "-a^2",
+		"(-a)^2":              "(-a)^2",
+		"a^b^c":               "a^b^c",
+		"(a^b)^c":             "(a^b)^c",
+		"a^-b":                "a^-b",
+		"a^(b * c)":           "a^(b * c)",
+		"(a + 1)^2":           "(a + 1)^2",
+		"2 ^ (a/b)":           "2^(a / b)",
+		"max(a - b, -c, 3)":   "max(a - b, -c, 3)",
+		"sin(x)^2":            "sin(x)^2",
+		"-(a - b) - -(c * d)": "-(a - b) - -(c * d)",
+		"0.1 + 1000000":       "0.1 + 1e+06",
+	}
+	for expr, expected := range exprs {
+		node, err := Parse(expr)
+		if err != nil {
+			t.Fatalf("Failed to parse %v: %v", expr, err)
+		}
+		s := node.String()
+		if s != expected {
+			t.Errorf("Printed %v as %v, expected %v", expr, s, expected)
+		}
+		reparsed, err := Parse(s)
+		if err != nil {
+			t.Errorf("Failed to parse %v: %v", s, err)
+		} else if !sameTree(node, reparsed) {
+			t.Errorf("%v parsed back as a different tree", s)
+		}
+	}
+}
+
+func TestStringNegativeLiteral(t *testing.T) {
+	node := NewPlusNode([]*Node{NewVarNode("x"), NewLiteralNode(-2.5)})
+	if s := node.String(); s != "x - 2.5" {
+		t.Errorf("Expected x - 2.5, got %v", s)
+	}
+	reparsed, _ := Parse(node.String())
+	if !sameTree(node, reparsed) {
+		t.Errorf("%v parsed back as a different tree", node)
+	}
+}
+
+// sameTree compares the structure and values of two trees
+func sameTree(a, b *Node) bool {
+	if a.op != b.op || len(a.nodes) != len(b.nodes) {
+		return false
+	}
+	for i := range a.nodes {
+		if !sameTree(a.nodes[i], b.nodes[i]) {
+			return false
+		}
+	}
+	switch da := a.data.(type) {
+	case *Literal:
+		db, ok := b.data.(*Literal)
+		return ok && *da == *db
+	case *builtinFunc:
+		db, ok := b.data.(*builtinFunc)
+		if !ok || da.id != db.id || len(da.params) != len(db.params) {
+			return false
+		}
+		for i := range da.params {
+			if !sameTree(da.params[i], db.params[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,329 @@
+package matheval
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// LaTeX renders the expression as LaTeX math for documentation, such as \frac{x^{2}}{2} + \sin\left(\pi \cdot t\right).
+// Divisions are rendered as fractions, log as the natural logarithm \ln and multi-letter variables upright.
+func (self *Node) LaTeX() string {
+	var b strings.Builder
+	writeLaTeX(&b, self)
+	return b.String()
+}
+
+// MathML renders the expression as a MathML <math> element for documentation
+func (self *Node) MathML() string {
+	var b strings.Builder
+	b.WriteString(`<math xmlns="http://www.w3.org/1998/Math/MathML">`)
+	writeMathML(&b, self)
+	b.WriteString(`</math>`)
+	return b.String()
+}
+
+// renderParentheses reports whether the child at index of parent needs parentheses when rendering.
+// This is looser than in String, as rendered expressions never have to be parsed. Fractions and exponents are
+// grouped by their layout, and parentheses are only added where they change the meaning or help reading.
+func renderParentheses(parent *Node, index int, child *Node) bool {
+	p := renderPrecedence(child)
+	switch parent.op {
+	case PLUS:
+		// comparisons and logical operators in a sum, and sums after the first term, as in a - (b + c)
+		if index == 0 {
+			return p < precedenceSum
+		}
+		return p <= precedenceSum
+	case MINUS:
+		return p < precedenceUnary
+	case MULT:
+		if index == 0 {
+			return p < precedenceProduct
+		}
+		return p <= precedenceUnary
+	case POW:
+		return index == 0 && p <= precedencePower
+	case LT, LE, GT, GE, EQ, NE, AND, OR:
+		if index == 0 {
+			return p < precedence(parent)
+		}
+		return p <= precedence(parent)
+	case NOT:
+		return p < precedenceUnary
+	}
+	return false
+}
+
+func renderPrecedence(n *Node) int {
+	if n.op == DIV {
+		// fractions are grouped by their layout
+		return precedencePrimary
+	}
+	return precedence(n)
+}
+
+// latexFunctions maps functions to LaTeX commands. Other functions are rendered with \operatorname.
+var latexFunctions = map[string]string{
+	"sin":   `\sin`,
+	"cos":   `\cos`,
+	"tan":   `\tan`,
+	"asin":  `\arcsin`,
+	"acos":  `\arccos`,
+	"atan":  `\arctan`,
+	"exp":   `\exp`,
+	"log":   `\ln`,
+	"log2":  `\log_{2}`,
+	"log10": `\log_{10}`,
+	"min":   `\min`,
+	"max":   `\max`,
+}
+
+var latexOperators = map[Operator]string{
+	LT:  "<",
+	LE:  `\le`,
+	GT:  ">",
+	GE:  `\ge`,
+	EQ:  "=",
+	NE:  `\ne`,
+	AND: `\land`,
+	OR:  `\lor`,
+}
+
+func writeLaTeX(b *strings.Builder, n *Node) {
+	child := func(i int, c *Node) {
+		if renderParentheses(n, i, c) {
+			b.WriteString(`\left(`)
+			writeLaTeX(b, c)
+			b.WriteString(`\right)`)
+		} else {
+			writeLaTeX(b, c)
+		}
+	}
+	switch n.op {
+	case PLUS:
+		for i, c := range n.nodes {
+			if i > 0 && c.op == MINUS {
+				b.WriteString(" - ")
+				child(i, c.nodes[0])
+				continue
+			}
+			if i > 0 {
+				b.WriteString(" + ")
+			}
+			child(i, c)
+		}
+	case MINUS:
+		b.WriteString("-")
+		child(0, n.nodes[0])
+	case MULT:
+		for i, c := range n.nodes {
+			if i > 0 {
+				b.WriteString(` \cdot `)
+			}
+			child(i, c)
+		}
+	case DIV:
+		b.WriteString(`\frac{`)
+		writeLaTeX(b, n.nodes[0])
+		b.WriteString("}{")
+		writeLaTeX(b, n.nodes[1])
+		b.WriteString("}")
+	case POW:
+		child(0, n.nodes[0])
+		b.WriteString("^{")
+		writeLaTeX(b, n.nodes[1])
+		b.WriteString("}")
+	case ATOM:
+		l := n.data.(*Literal)
+		if l.variable == "" {
+			mantissa, exponent, found := strings.Cut(formatNumber(l.val), "e")
+			b.WriteString(mantissa)
+			if found {
+				e, _ := strconv.Atoi(exponent)
+				fmt.Fprintf(b, ` \times 10^{%v}`, e)
+			}
+		} else {
+			b.WriteString(latexIdentifier(l.variable))
+		}
+	case FUNC:
+		f := n.data.(*builtinFunc)
+		switch f.id {
+		case "sqrt":
+			b.WriteString(`\sqrt{`)
+			writeLaTeX(b, f.params[0])
+			b.WriteString("}")
+			return
+		case "abs":
+			b.WriteString(`\left|`)
+			writeLaTeX(b, f.params[0])
+			b.WriteString(`\right|`)
+			return
+		case "floor", "ceil":
+			b.WriteString(`\left\l` + f.id)
+			writeLaTeX(b, f.params[0])
+			b.WriteString(`\right\r` + f.id)
+			return
+		}
+		if name, ok := latexFunctions[f.id]; ok {
+			b.WriteString(name)
+		} else {
+			b.WriteString(`\operatorname{` + latexEscape(f.id) + "}")
+		}
+		b.WriteString(`\left(`)
+		for i, p := range f.params {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			writeLaTeX(b, p)
+		}
+		b.WriteString(`\right)`)
+	case LT, LE, GT, GE, EQ, NE, AND, OR:
+		child(0, n.nodes[0])
+		b.WriteString(" " + latexOperators[n.op] + " ")
+		child(1, n.nodes[1])
+	case NOT:
+		b.WriteString(`\lnot `)
+		child(0, n.nodes[0])
+	case COND:
+		b.WriteString(`\begin{cases}`)
+		writeLaTeX(b, n.nodes[1])
+		b.WriteString(` & \text{if } `)
+		writeLaTeX(b, n.nodes[0])
+		b.WriteString(` \\ `)
+		writeLaTeX(b, n.nodes[2])
+		b.WriteString(` & \text{otherwise}\end{cases}`)
+	default:
+		panic(fmt.Sprintf("Unknown NodeImpl type in LaTeX(): %v", n.op))
+	}
+}
+
+func latexIdentifier(name string) string {
+	if name == "pi" {
+		return `\pi`
+	}
+	if len(name) == 1 {
+		return name
+	}
+	return `\mathrm{` + latexEscape(name) + "}"
+}
+
+func latexEscape(s string) string {
+	return strings.ReplaceAll(s, "_", `\_`)
+}
+
+func writeMathML(b *strings.Builder, n *Node) {
+	child := func(i int, c *Node) {
+		if renderParentheses(n, i, c) {
+			b.WriteString("<mrow><mo>(</mo>")
+			writeMathML(b, c)
+			b.WriteString("<mo>)</mo></mrow>")
+		} else {
+			writeMathML(b, c)
+		}
+	}
+	switch n.op {
+	case PLUS:
+		b.WriteString("<mrow>")
+		for i, c := range n.nodes {
+			if i > 0 && c.op == MINUS {
+				b.WriteString("<mo>-</mo>")
+				child(i, c.nodes[0])
+				continue
+			}
+			if i > 0 {
+				b.WriteString("<mo>+</mo>")
+			}
+			child(i, c)
+		}
+		b.WriteString("</mrow>")
+	case MINUS:
+		b.WriteString("<mrow><mo>-</mo>")
+		child(0, n.nodes[0])
+		b.WriteString("</mrow>")
+	case MULT:
+		b.WriteString("<mrow>")
+		for i, c := range n.nodes {
+			if i > 0 {
+				// dot operator
+				b.WriteString("<mo>&#x22C5;</mo>")
+			}
+			child(i, c)
+		}
+		b.WriteString("</mrow>")
+	case DIV:
+		b.WriteString("<mfrac>")
+		writeMathML(b, n.nodes[0])
+		writeMathML(b, n.nodes[1])
+		b.WriteString("</mfrac>")
+	case POW:
+		b.WriteString("<msup>")
+		child(0, n.nodes[0])
+		writeMathML(b, n.nodes[1])
+		b.WriteString("</msup>")
+	case ATOM:
+		l := n.data.(*Literal)
+		if l.variable == "" {
+			fmt.Fprintf(b, "<mn>%v</mn>", formatNumber(l.val))
+		} else if l.variable == "pi" {
+			b.WriteString("<mi>&#x3C0;</mi>")
+		} else {
+			fmt.Fprintf(b, "<mi>%v</mi>", l.variable)
+		}
+	case FUNC:
+		f := n.data.(*builtinFunc)
+		switch f.id {
+		case "sqrt":
+			b.WriteString("<msqrt>")
+			writeMathML(b, f.params[0])
+			b.WriteString("</msqrt>")
+			return
+		case "abs":
+			b.WriteString("<mrow><mo>|</mo>")
+			writeMathML(b, f.params[0])
+			b.WriteString("<mo>|</mo></mrow>")
+			return
+		}
+		// the function name is followed by an invisible function application operator
+		fmt.Fprintf(b, "<mrow><mi>%v</mi><mo>&#x2061;</mo><mrow><mo>(</mo>", f.id)
+		for i, p := range f.params {
+			if i > 0 {
+				b.WriteString("<mo>,</mo>")
+			}
+			writeMathML(b, p)
+		}
+		b.WriteString("<mo>)</mo></mrow></mrow>")
+	case LT, LE, GT, GE, EQ, NE, AND, OR:
+		b.WriteString("<mrow>")
+		child(0, n.nodes[0])
+		fmt.Fprintf(b, "<mo>%v</mo>", mathMLOperators[n.op])
+		child(1, n.nodes[1])
+		b.WriteString("</mrow>")
+	case NOT:
+		b.WriteString("<mrow><mo>&#x00AC;</mo>")
+		child(0, n.nodes[0])
+		b.WriteString("</mrow>")
+	case COND:
+		// a brace followed by a table of the cases
+		b.WriteString(`<mrow><mo>{</mo><mtable><mtr><mtd>`)
+		writeMathML(b, n.nodes[1])
+		b.WriteString(`</mtd><mtd><mtext>if</mtext><mspace width="0.5em"/>`)
+		writeMathML(b, n.nodes[0])
+		b.WriteString(`</mtd></mtr><mtr><mtd>`)
+		writeMathML(b, n.nodes[2])
+		b.WriteString(`</mtd><mtd><mtext>otherwise</mtext></mtd></mtr></mtable></mrow>`)
+	default:
+		panic(fmt.Sprintf("Unknown NodeImpl type in MathML(): %v", n.op))
+	}
+}
+
+var mathMLOperators = map[Operator]string{
+	LT:  "&lt;",
+	LE:  "&#x2264;",
+	GT:  "&gt;",
+	GE:  "&#x2265;",
+	EQ:  "=",
+	NE:  "&#x2260;",
+	AND: "&#x2227;",
+	OR:  "&#x2228;",
+}
@@ -0,0 +1,55 @@
+package matheval
+
+import (
+	"testing"
+)
+
+func TestLaTeX(t *testing.T) {
+	tests := map[string]string{
+		"x^2 / 2 + sin(pi * t)":   `\frac{x^{2}}{2} + \sin\left(\pi \cdot t\right)`,
+		"a - (b + c)":             `a - \left(b + c\right)`,
+		"-(a * b) + 2 * -c":       `-\left(a \cdot b\right) + 2 \cdot \left(-c\right)`,
+		"(a + b)^(c + 1)":         `\left(a + b\right)^{c + 1}`,
+		"sqrt(abs(x)) * log(x)":   `\sqrt{\left|x\right|} \cdot \ln\left(x\right)`,
+		"floor(level_scale / 3)":  `\left\lfloor\frac{\mathrm{level\_scale}}{3}\right\rfloor`,
+		"1.5e-7 * clamp(x, 0, 1)": `1.5 \times 10^{-7} \cdot \operatorname{clamp}\left(x, 0, 1\right)`,
+		"a + (b < c)":             `a + \left(b < c\right)`,
+		"(a == b) - c":            `\left(a = b\right) - c`,
+		"(a || b) + 1":            `\left(a \lor b\right) + 1`,
+	}
+	for expr, expected := range tests {
+		node, err := Parse(expr)
+		if err != nil {
+			t.Fatalf("Failed to parse %v: %v", expr, err)
+		}
+		if s := node.LaTeX(); s != expected {
+			t.Errorf("Rendered %v as\n%v\nexpected\n%v", expr, s, expected)
+		}
+	}
+}
+
+func TestMathML(t *testing.T) {
+	node, _ := Parse("(x - 1)^2 / 2 + max(pi, y)")
+	expected := `<math xmlns="http://www.w3.org/1998/Math/MathML"><mrow>` +
+		`<mfrac><msup><mrow><mo>(</mo><mrow><mi>x</mi><mo>-</mo><mn>1</mn></mrow><mo>)</mo></mrow><mn>2</mn></msup><mn>2</mn></mfrac>` +
+		`<mo>+</mo><mrow><mi>max</mi><mo>&#x2061;</mo><mrow><mo>(</mo><mi>&#x3C0;</mi><mo>,</mo><mi>y</mi><mo>)</mo></mrow></mrow>` +
+		`</mrow></math>`
+	if s := node.MathML(); s != expected {
+		t.Errorf("Rendered as\n%v\nexpected\n%v", s, expected)
+	}
+}
+
+func TestMathMLComparisonsInSums(t *testing.T) {
+	tests := map[string]string{
+		"a + (b < c)":  `<mrow><mi>a</mi><mo>+</mo><mrow><mo>(</mo><mrow><mi>b</mi><mo>&lt;</mo><mi>c</mi></mrow><mo>)</mo></mrow></mrow>`,
+		"(a == b) - c": `<mrow><mrow><mo>(</mo><mrow><mi>a</mi><mo>=</mo><mi>b</mi></mrow><mo>)</mo></mrow><mo>-</mo><mi>c</mi></mrow>`,
+		"(a || b) + 1": `<mrow><mrow><mo>(</mo><mrow><mi>a</mi><mo>&#x2228;</mo><mi>b</mi></mrow><mo>)</mo></mrow><mo>+</mo><mn>1</mn></mrow>`,
+	}
+	for expr, expected := range tests {
+		node, _ := Parse(expr)
+		expected = `<math xmlns="http://www.w3.org/1998/Math/MathML">` + expected + `</math>`
+		if s := node.MathML(); s != expected {
+			t.Errorf("Rendered %v as\n%v\nexpected\n%v", expr, s, expected)
+		}
+	}
+}
@@ -0,0 +1,300 @@
+package matheval
+
+import (
+	"math"
+	"slices"
+	"strings"
+)
+
+// Simplify returns a simplified copy of the expression:
+//   - constants are folded, except for divisions by zero and function calls that would give infinities or NaN,
+//     and conditionals with a constant condition are replaced by the branch they take
+//   - identities are removed, such as x + 0, x * 1, x * 0, 0 / x, x / 1, x^1, x^0 and --x. x * 0 and 0 / x become 0
+//     even where the sign of x would make them -0, while folded constants such as 0 * -3 keep the sign for atan2.
+//   - nested sums and products are flattened, and negations are pulled out of products
+//   - like terms are collected, so x + 2 * x becomes 3 * x
+//   - the terms of sums and the factors of products are sorted into a canonical order, with numbers last in sums and
+//     first in products
+//
+// Negative numbers are negated literals like the parser produces, so the result prints in a form that parses back to
+// the same tree. The constants pi and e are kept by name. The input isn't modified, but the result may share nodes with it.
+func Simplify(node *Node) *Node {
+	switch node.op {
+	case PLUS:
+		return simplifySum(node)
+	case MINUS:
+		return negate(Simplify(node.nodes[0]))
+	case MULT:
+		return simplifyProduct(node)
+	case DIV:
+		return simplifyDivision(node)
+	case POW:
+		return simplifyPower(node)
+	case FUNC:
+		return simplifyFunction(node)
+	case LT, LE, GT, GE, EQ, NE, AND, OR, NOT, COND:
+		return simplifyLogic(node)
+	default:
+		return node
+	}
+}
+
+// simplifyLogic folds comparisons, logical operators and conditionals with constant operands
+func simplifyLogic(node *Node) *Node {
+	children := make([]*Node, len(node.nodes))
+	constant := true
+	for i, c := range node.nodes {
+		children[i] = Simplify(c)
+		_, ok := constantValue(children[i])
+		constant = constant && ok
+	}
+	if node.op == COND {
+		if c, ok := constantValue(children[0]); ok {
+			if c != 0 {
+				return children[1]
+			}
+			return children[2]
+		}
+	}
+	n := &Node{op: node.op, nodes: children}
+	if constant {
+		return newNumber(n.Evaluate(nil))
+	}
+	return n
+}
+
+func simplifySum(node *Node) *Node {
+	terms := make([]*Node, 0, len(node.nodes))
+	sum := 0.0
+	var add func(t *Node)
+	add = func(t *Node) {
+		if v, ok := constantValue(t); ok {
+			sum += v
+			return
+		}
+		switch {
+		case t.op == PLUS:
+			for _, c := range t.nodes {
+				add(c)
+			}
+		case t.op == MINUS && t.nodes[0].op == PLUS:
+			for _, c := range t.nodes[0].nodes {
+				add(negate(c))
+			}
+		default:
+			terms = append(terms, t)
+		}
+	}
+	for _, c := range node.nodes {
+		add(Simplify(c))
+	}
+	terms = collectTerms(terms)
+	slices.SortStableFunc(terms, func(a, b *Node) int {
+		return strings.Compare(stripMinus(a).String(), stripMinus(b).String())
+	})
+	if sum != 0 || len(terms) == 0 {
+		terms = append(terms, newNumber(sum))
+	}
+	if len(terms) == 1 {
+		return terms[0]
+	}
+	return NewPlusNode(terms)
+}
+
+// collectTerms adds up the coefficients of terms that only differ by their coefficient, and drops terms that cancel out
+func collectTerms(terms []*Node) []*Node {
+	type like struct {
+		coefficient float64
+		rest        *Node
+	}
+	var likes []like
+	index := make(map[string]int, len(terms))
+	for _, t := range terms {
+		c, rest := splitCoefficient(t)
+		key := rest.String()
+		if i, ok := index[key]; ok {
+			likes[i].coefficient += c
+		} else {
+			index[key] = len(likes)
+			likes = append(likes, like{c, rest})
+		}
+	}
+	if len(likes) == len(terms) {
+		return terms
+	}
+	terms = terms[:0]
+	for _, l := range likes {
+		if l.coefficient != 0 {
+			terms = append(terms, simplifyProduct(NewMultNode([]*Node{newNumber(l.coefficient), l.rest})))
+		}
+	}
+	return terms
+}
+
+// splitCoefficient splits a simplified term into its numeric coefficient and the rest
+func splitCoefficient(t *Node) (float64, *Node) {
+	c := 1.0
+	if t.op == MINUS {
+		c, t = -1, t.nodes[0]
+	}
+	if t.op == MULT {
+		if v, ok := constantValue(t.nodes[0]); ok {
+			rest := t.nodes[1]
+			if len(t.nodes) > 2 {
+				rest = NewMultNode(t.nodes[1:])
+			}
+			return c * v, rest
+		}
+	}
+	return c, t
+}
+
+func simplifyProduct(node *Node) *Node {
+	factors := make([]*Node, 0, len(node.nodes))
+	coefficient := 1.0
+	var multiply func(f *Node)
+	multiply = func(f *Node) {
+		if v, ok := constantValue(f); ok {
+			coefficient *= v
+			return
+		}
+		switch f.op {
+		case MINUS:
+			coefficient = -coefficient
+			multiply(f.nodes[0])
+		case MULT:
+			for _, c := range f.nodes {
+				multiply(c)
+			}
+		default:
+			factors = append(factors, f)
+		}
+	}
+	for _, c := range node.nodes {
+		multiply(Simplify(c))
+	}
+	if len(factors) == 0 {
+		return newNumber(coefficient)
+	}
+	if coefficient == 0 {
+		// the sign depends on the other factors
+		return NewLiteralNode(0)
+	}
+	slices.SortStableFunc(factors, func(a, b *Node) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	if abs := math.Abs(coefficient); abs != 1 {
+		factors = slices.Insert(factors, 0, NewLiteralNode(abs))
+	}
+	product := factors[0]
+	if len(factors) > 1 {
+		product = NewMultNode(factors)
+	}
+	if coefficient < 0 {
+		return NewMinusNode(product)
+	}
+	return product
+}
+
+func simplifyDivision(node *Node) *Node {
+	a, b := Simplify(node.nodes[0]), Simplify(node.nodes[1])
+	av, aConstant := constantValue(a)
+	bv, bConstant := constantValue(b)
+	switch {
+	case aConstant && bConstant && bv != 0 && isFinite(av/bv):
+		return newNumber(av / bv)
+	case aConstant && av == 0 && !(bConstant && bv == 0):
+		return NewLiteralNode(0)
+	case bConstant && bv == 1:
+		return a
+	case bConstant && bv == -1:
+		return negate(a)
+	case a.op == MINUS:
+		return NewMinusNode(NewDivNode(a.nodes[0], b))
+	}
+	return NewDivNode(a, b)
+}
+
+func simplifyPower(node *Node) *Node {
+	base, exponent := Simplify(node.nodes[0]), Simplify(node.nodes[1])
+	ev, exponentConstant := constantValue(exponent)
+	bv, baseConstant := constantValue(base)
+	switch {
+	case exponentConstant && ev == 0, baseConstant && bv == 1:
+		return NewLiteralNode(1)
+	case exponentConstant && ev == 1:
+		return base
+	case exponentConstant && baseConstant:
+		if v := math.Pow(bv, ev); isFinite(v) {
+			return newNumber(v)
+		}
+	}
+	return NewPowNode(base, exponent)
+}
+
+func simplifyFunction(node *Node) *Node {
+	f := node.data.(*builtinFunc)
+	params := make([]*Node, len(f.params))
+	values := make([]float64, len(f.params))
+	constant := isDefaultFunc(f.fn)
+	for i, p := range f.params {
+		params[i] = Simplify(p)
+		var ok bool
+		values[i], ok = constantValue(params[i])
+		constant = constant && ok
+	}
+	if constant {
+		if v := f.fn.Call(values...); isFinite(v) {
+			return newNumber(v)
+		}
+	}
+	return NewFunctionNode(&builtinFunc{id: f.id, fn: f.fn, params: params})
+}
+
+// constantValue returns the value of numbers and negated numbers
+func constantValue(n *Node) (float64, bool) {
+	switch n.op {
+	case ATOM:
+		if l := n.data.(*Literal); l.variable == "" {
+			return l.val, true
+		}
+	case MINUS:
+		if v, ok := constantValue(n.nodes[0]); ok {
+			return -v, true
+		}
+	}
+	return 0, false
+}
+
+// newNumber creates a literal from a folded constant, which keeps the sign of -0 for functions like atan2
+func newNumber(v float64) *Node {
+	return NewLiteralNode(v)
+}
+
+// negate negates a simplified node
+func negate(n *Node) *Node {
+	if n.op == MINUS {
+		return n.nodes[0]
+	}
+	if v, ok := constantValue(n); ok {
+		return newNumber(-v)
+	}
+	return NewMinusNode(n)
+}
+
+func stripMinus(n *Node) *Node {
+	if n.op == MINUS {
+		return n.nodes[0]
+	}
+	return n
+}
+
+// isDefaultFunc reports whether fn is from the default library, which is known to have no side effects
+func isDefaultFunc(fn *Func) bool {
+	d, ok := defaultFunctions.Lookup(fn.Name)
+	return ok && d == fn
+}
+
+func isFinite(v float64) bool {
+	return !math.IsInf(v, 0) && !math.IsNaN(v)
+}
@@ -0,0 +1,98 @@
+package matheval
+
+import (
+	"testing"
+)
+
+func TestSimplify(t *testing.T) {
+	tests := map[string]string{
+		"x * 1":                   "x",
+		"1 * x":                   "x",
+		"x + 0":                   "x",
+		"0 + x * 0":               "0",
+		"x / 1":                   "x",
+		"x^1":                     "x",
+		"x^0":                     "1",
+		"1^x":                     "1",
+		"--x":                     "x",
+		"2 + 3 * 4":               "14",
+		"2 - 5":                   "-3",
+		"x + 2 + 3":               "x + 5",
+		"3 + x":                   "x + 3",
+		"x - 3":                   "x - 3",
+		"(a + b) + (c + d)":       "a + b + c + d",
+		"a - (b - c)":             "a - b + c",
+		"a * (b * c)":             "a * b * c",
+		"x * 2 * 3":               "6 * x",
+		"-x * 2":                  "-(2 * x)",
+		"-x * -y":                 "x * y",
+		"b + a":                   "a + b",
+		"y * x":                   "x * y",
+		"-b + a":                  "a - b",
+		"2^3":                     "8",
+		"sqrt(16) + x":            "x + 4",
+		"sqrt(-1)":                "sqrt(-1)",
+		"1 / 0":                   "1 / 0",
+		"0 / 0":                   "0 / 0",
+		"0 / (x == 2 && y)":       "0",
+		"pi * 2":                  "2 * pi",
+		"-x / y":                  "-(x / y)",
+		"x / -1":                  "-x",
+		"(x + 0) * (y * 1) / 2^1": "x * y / 2",
+		"x + 2 * x":               "3 * x",
+		"x * y - y * x + 1":       "1",
+		"a - x - 2 * a":           "-a - x",
+	}
+	for expr, expected := range tests {
+		node, err := Parse(expr)
+		if err != nil {
+			t.Fatalf("Failed to parse %v: %v", expr, err)
+		}
+		if result := Simplify(node).String(); result != expected {
+			t.Errorf("Simplified %v to %v, expected %v", expr, result, expected)
+		}
+	}
+}
+
+func TestSimplifyKeepsValue(t *testing.T) {
+	vars := map[string]float64{"x": 1.5, "y": -2.25, "a": 3, "b": 0.5}
+	exprs := []string{
+		"x * (y + 2) - (a - b) / 4 + x",
+		"-(x - y) * -(a + 1)",
+		"pow(x, 2) + 2^3^0.5 - min(a, b, 1)",
+		"a / b / x * y",
+		"-(-x)^2 + -x^2",
+		"sin(pi * 0.5) * x - 0 * y + 1 * a",
+		"atan2(0 * -3, -1) + atan2(-(x - x), -1)",
+	}
+	for _, expr := range exprs {
+		node, _ := Parse(expr)
+		simplified := Simplify(node)
+		expected := node.Evaluate(vars)
+		if result := simplified.Evaluate(vars); !closeTo(result, expected) {
+			t.Errorf("Simplified %v to %v, which gives %v instead of %v", expr, simplified, result, expected)
+		}
+	}
+}
+
+func TestSimplifyRoundTrip(t *testing.T) {
+	exprs := []string{
+		"x * (y + 2) - (a - b) / 4 + x",
+		"-(x - y) * -(a + 1)",
+		"a / b / x * y",
+		"a / (b / x) * (y * x)",
+		"(x + 1)^(y - 1)^2",
+		"(-x)^2 - x^-2",
+		"max(a - b, -a, 2 * b)",
+	}
+	for _, expr := range exprs {
+		node, _ := Parse(expr)
+		simplified := Simplify(node)
+		reparsed, err := Parse(simplified.String())
+		if err != nil {
+			t.Errorf("Failed to parse %v: %v", simplified, err)
+		} else if reparsed.String() != simplified.String() || !sameTree(reparsed, simplified) {
+			t.Errorf("%v parsed back as a different tree", simplified)
+		}
+	}
+}
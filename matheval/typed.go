@@ -0,0 +1,567 @@
+package matheval
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/Lundis/go-gmath/vec2"
+	"github.com/Lundis/go-gmath/vec3"
+)
+
+// Type is the type of the values in a typed expression
+type Type int
+
+const (
+	Scalar Type = iota
+	// Vec2 values are vec2.D
+	Vec2
+	// Vec3 values are vec3.F
+	Vec3
+)
+
+var typeNames = []string{Scalar: "scalar", Vec2: "vec2", Vec3: "vec3"}
+
+func (t Type) String() string {
+	if t >= 0 && int(t) < len(typeNames) {
+		return typeNames[t]
+	}
+	return fmt.Sprintf("Type(%d)", int(t))
+}
+
+// size returns the number of components of values of the type
+func (t Type) size() int {
+	return int(t) + 1
+}
+
+// ErrType is returned for typed expressions that use values of the wrong type, such as vec2(1, 2) + 3
+var ErrType = errors.New("Type error")
+
+// component is the data of COMPONENT nodes
+type component int
+
+var componentNames = []string{"x", "y", "z"}
+
+func (c component) String() string {
+	return componentNames[c]
+}
+
+// Evaluate gives NaN, as components can only be evaluated by a TypedExpr
+func (c component) Evaluate(map[string]float64) float64 {
+	return math.NaN()
+}
+
+// vectorFunctions are the functions that typed expressions can call in addition to the scalar functions of the parser,
+// which they take priority over:
+//   - vec2(x, y) and vec3(x, y, z) create vectors
+//   - dot(a, b), length(v) and distance(a, b) give scalars
+//   - cross(a, b) gives a vec3 for vec3, and the scalar a.x * b.y - a.y * b.x for vec2
+//   - normalize(v) gives a vector of length 1, or v itself if its length is 0
+//   - rotate(v, angle) rotates a vec2 like vec2.D.Rotate, and rotate(v, axis, angle) rotates a vec3 around axis by the
+//     right-hand rule like vec3.Quat. Around the Z axis, that is the opposite way from vec3.F.Rotate and the vec2 form.
+//   - lerp(a, b, t) interpolates between two scalars or two vectors
+var vectorFunctions = map[string]*Func{
+	"vec2":      {Name: "vec2", MinParams: 2, MaxParams: 2},
+	"vec3":      {Name: "vec3", MinParams: 3, MaxParams: 3},
+	"dot":       {Name: "dot", MinParams: 2, MaxParams: 2},
+	"cross":     {Name: "cross", MinParams: 2, MaxParams: 2},
+	"length":    {Name: "length", MinParams: 1, MaxParams: 1},
+	"distance":  {Name: "distance", MinParams: 2, MaxParams: 2},
+	"normalize": {Name: "normalize", MinParams: 1, MaxParams: 1},
+	"rotate":    {Name: "rotate", MinParams: 2, MaxParams: 3},
+	"lerp":      {Name: "lerp", MinParams: 3, MaxParams: 3},
+}
+
+// TypedExpr is an expression whose values can be scalars or vectors. The components of vectors are read with v.x,
+// v.y and v.z. Vectors of the same type can be added and subtracted, and vectors can be multiplied and divided by
+// scalars. The vectorFunctions work with vectors, and everything else takes scalars.
+// A TypedExpr can be evaluated from several goroutines at once, as long as the functions it calls allow it.
+type TypedExpr struct {
+	node      *Node
+	typ       Type
+	eval      typedFunc
+	slots     []string
+	slotTypes []Type
+}
+
+// vector holds a value of any type, with the components that the type doesn't use set to 0
+type vector [3]float64
+
+type typedFunc func(vars []vector) vector
+
+// ParseTyped parses a typed expression with the default syntax. vars holds the types of the variables, and variables
+// that aren't in it are scalars.
+func ParseTyped(expr string, vars map[string]Type) (*TypedExpr, error) {
+	return Parser{}.ParseTyped(expr, vars)
+}
+
+// ParseTyped parses and type checks a typed expression. Syntax errors are returned as a *ParseError, and type errors as
+// an error wrapping ErrType.
+func (self Parser) ParseTyped(expr string, vars map[string]Type) (*TypedExpr, error) {
+	node, err := self.parse(expr, true)
+	if err != nil {
+		return nil, err
+	}
+	e := &TypedExpr{node: node}
+	e.eval, e.typ, err = e.compile(node, vars)
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Type returns the type of the values of the expression
+func (self *TypedExpr) Type() Type {
+	return self.typ
+}
+
+func (self *TypedExpr) String() string {
+	return self.node.String()
+}
+
+// Evaluate evaluates the expression to a float64, vec2.D or vec3.F depending on its Type.
+// vars holds values of the same Go types as the variables, and variables that are missing from it are 0.
+func (self *TypedExpr) Evaluate(vars map[string]any) (any, error) {
+	v, err := self.evaluate(vars)
+	if err != nil {
+		return nil, err
+	}
+	switch self.typ {
+	case Vec2:
+		return vec2.D{X: v[0], Y: v[1]}, nil
+	case Vec3:
+		return vec3.F{X: float32(v[0]), Y: float32(v[1]), Z: float32(v[2])}, nil
+	default:
+		return v[0], nil
+	}
+}
+
+// EvaluateScalar is like Evaluate, but returns an error wrapping ErrType if the expression isn't a Scalar
+func (self *TypedExpr) EvaluateScalar(vars map[string]any) (float64, error) {
+	v, err := self.evaluateAs(Scalar, vars)
+	return v[0], err
+}
+
+// EvaluateVec2 is like Evaluate, but returns an error wrapping ErrType if the expression isn't a Vec2
+func (self *TypedExpr) EvaluateVec2(vars map[string]any) (vec2.D, error) {
+	v, err := self.evaluateAs(Vec2, vars)
+	return vec2.D{X: v[0], Y: v[1]}, err
+}
+
+// EvaluateVec3 is like Evaluate, but returns an error wrapping ErrType if the expression isn't a Vec3
+func (self *TypedExpr) EvaluateVec3(vars map[string]any) (vec3.F, error) {
+	v, err := self.evaluateAs(Vec3, vars)
+	return vec3.F{X: float32(v[0]), Y: float32(v[1]), Z: float32(v[2])}, err
+}
+
+func (self *TypedExpr) evaluateAs(t Type, vars map[string]any) (vector, error) {
+	if self.typ != t {
+		return vector{}, fmt.Errorf("%w: the expression is a %v, not a %v", ErrType, self.typ, t)
+	}
+	return self.evaluate(vars)
+}
+
+func (self *TypedExpr) evaluate(vars map[string]any) (vector, error) {
+	values := make([]vector, len(self.slots))
+	for i, name := range self.slots {
+		value, ok := vars[name]
+		if !ok {
+			continue
+		}
+		if values[i], ok = toVector(value, self.slotTypes[i]); !ok {
+			return vector{}, fmt.Errorf("%w: variable %v is a %v, got %T", ErrType, name, self.slotTypes[i], value)
+		}
+	}
+	return self.eval(values), nil
+}
+
+func toVector(value any, t Type) (vector, bool) {
+	switch v := value.(type) {
+	case float64:
+		return vector{v}, t == Scalar
+	case vec2.D:
+		return vector{v.X, v.Y}, t == Vec2
+	case vec3.F:
+		return vector{float64(v.X), float64(v.Y), float64(v.Z)}, t == Vec3
+	}
+	return vector{}, false
+}
+
+// compile type checks the tree and turns it into a closure
+func (self *TypedExpr) compile(n *Node, vars map[string]Type) (typedFunc, Type, error) {
+	switch n.op {
+	case ATOM:
+		return self.compileLiteral(n.data.(*Literal), vars)
+	case PLUS:
+		sum, t, err := self.compile(n.nodes[0], vars)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, term := range n.nodes[1:] {
+			f, u, err := self.compile(term, vars)
+			if err != nil {
+				return nil, 0, err
+			}
+			if u != t {
+				return nil, 0, typeError(n, "cannot add %v and %v", t, u)
+			}
+			a := sum
+			sum = func(vars []vector) vector { return a(vars).add(f(vars)) }
+		}
+		return sum, t, nil
+	case MINUS:
+		f, t, err := self.compile(n.nodes[0], vars)
+		if err != nil {
+			return nil, 0, err
+		}
+		return func(vars []vector) vector { return f(vars).scale(-1) }, t, nil
+	case MULT:
+		product, t, err := self.compile(n.nodes[0], vars)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, factor := range n.nodes[1:] {
+			f, u, err := self.compile(factor, vars)
+			if err != nil {
+				return nil, 0, err
+			}
+			a := product
+			switch {
+			case u == Scalar:
+				product = func(vars []vector) vector { return a(vars).scale(f(vars)[0]) }
+			case t == Scalar:
+				product, t = func(vars []vector) vector { return f(vars).scale(a(vars)[0]) }, u
+			default:
+				return nil, 0, typeError(n, "cannot multiply %v and %v, use dot or cross", t, u)
+			}
+		}
+		return product, t, nil
+	case DIV:
+		fs, types, err := self.compileAll(n.nodes, vars)
+		if err != nil {
+			return nil, 0, err
+		}
+		if types[1] != Scalar {
+			return nil, 0, typeError(n, "cannot divide by a %v", types[1])
+		}
+		a, b, size := fs[0], fs[1], types[0].size()
+		return func(vars []vector) vector {
+			v, d := a(vars), b(vars)[0]
+			for i := range size {
+				v[i] = divide(v[i], d)
+			}
+			return v
+		}, types[0], nil
+	case POW, LT, LE, GT, GE, EQ, NE, AND, OR, NOT:
+		return self.compileScalarOperator(n, vars)
+	case COND:
+		fs, types, err := self.compileAll(n.nodes, vars)
+		if err != nil {
+			return nil, 0, err
+		}
+		if types[0] != Scalar {
+			return nil, 0, typeError(n, "the condition is a %v", types[0])
+		}
+		if types[1] != types[2] {
+			return nil, 0, typeError(n, "the branches are a %v and a %v", types[1], types[2])
+		}
+		condition, a, b := fs[0], fs[1], fs[2]
+		return func(vars []vector) vector {
+			if condition(vars)[0] != 0 {
+				return a(vars)
+			}
+			return b(vars)
+		}, types[1], nil
+	case COMPONENT:
+		f, t, err := self.compile(n.nodes[0], vars)
+		if err != nil {
+			return nil, 0, err
+		}
+		c := int(n.data.(component))
+		if c >= t.size() || t == Scalar {
+			return nil, 0, typeError(n, "a %v has no component %v", t, n.data)
+		}
+		return func(vars []vector) vector { return vector{f(vars)[c]} }, Scalar, nil
+	case FUNC:
+		f := n.data.(*builtinFunc)
+		if vectorFunctions[f.id] == f.fn {
+			return self.compileVectorFunc(n, f, vars)
+		}
+		params, err := self.compileScalars(n, f.params, vars)
+		if err != nil {
+			return nil, 0, err
+		}
+		return compileTypedFunc(f.fn, params), Scalar, nil
+	default:
+		panic(fmt.Sprintf("Unknown NodeImpl type in ParseTyped(): %v", n.op))
+	}
+}
+
+// compileTypedFunc compiles a call to a scalar function like Program.compileFunc does
+func compileTypedFunc(fn *Func, params []typedFunc) typedFunc {
+	switch {
+	case fn.f1 != nil:
+		a := params[0]
+		return func(vars []vector) vector { return vector{fn.f1(a(vars)[0])} }
+	case fn.f2 != nil:
+		a, b := params[0], params[1]
+		return func(vars []vector) vector { return vector{fn.f2(a(vars)[0], b(vars)[0])} }
+	case fn.f3 != nil:
+		a, b, c := params[0], params[1], params[2]
+		return func(vars []vector) vector { return vector{fn.f3(a(vars)[0], b(vars)[0], c(vars)[0])} }
+	case fn.fold != nil:
+		return func(vars []vector) vector {
+			r := params[0](vars)[0]
+			for _, param := range params[1:] {
+				r = fn.fold(r, param(vars)[0])
+			}
+			return vector{r}
+		}
+	default:
+		buffers := newArgPool(len(params))
+		return func(vars []vector) vector {
+			buf := buffers.Get().(*[]float64)
+			args := *buf
+			for i, param := range params {
+				args[i] = param(vars)[0]
+			}
+			r := fn.fn(args)
+			buffers.Put(buf)
+			return vector{r}
+		}
+	}
+}
+
+func (self *TypedExpr) compileAll(nodes []*Node, vars map[string]Type) ([]typedFunc, []Type, error) {
+	fs := make([]typedFunc, len(nodes))
+	types := make([]Type, len(nodes))
+	for i, c := range nodes {
+		var err error
+		if fs[i], types[i], err = self.compile(c, vars); err != nil {
+			return nil, nil, err
+		}
+	}
+	return fs, types, nil
+}
+
+// compileScalars compiles the operands or parameters of n, which must all be scalars
+func (self *TypedExpr) compileScalars(n *Node, nodes []*Node, vars map[string]Type) ([]typedFunc, error) {
+	fs, types, err := self.compileAll(nodes, vars)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range types {
+		if t != Scalar {
+			return nil, typeError(n, "expected a scalar, got a %v", t)
+		}
+	}
+	return fs, nil
+}
+
+// compileScalarOperator compiles the operators that only take scalars
+func (self *TypedExpr) compileScalarOperator(n *Node, vars map[string]Type) (typedFunc, Type, error) {
+	fs, err := self.compileScalars(n, n.nodes, vars)
+	if err != nil {
+		return nil, 0, err
+	}
+	a := fs[0]
+	if n.op == NOT {
+		return func(vars []vector) vector { return vector{boolValue(a(vars)[0] == 0)} }, Scalar, nil
+	}
+	b := fs[1]
+	switch n.op {
+	case POW:
+		return func(vars []vector) vector { return vector{math.Pow(a(vars)[0], b(vars)[0])} }, Scalar, nil
+	case AND:
+		return func(vars []vector) vector { return vector{boolValue(a(vars)[0] != 0 && b(vars)[0] != 0)} }, Scalar, nil
+	case OR:
+		return func(vars []vector) vector { return vector{boolValue(a(vars)[0] != 0 || b(vars)[0] != 0)} }, Scalar, nil
+	default:
+		op := n.op
+		return func(vars []vector) vector { return vector{compare(op, a(vars)[0], b(vars)[0])} }, Scalar, nil
+	}
+}
+
+// compare evaluates the comparison op
+func compare(op Operator, a, b float64) float64 {
+	switch op {
+	case LT:
+		return boolValue(a < b)
+	case LE:
+		return boolValue(a <= b)
+	case GT:
+		return boolValue(a > b)
+	case GE:
+		return boolValue(a >= b)
+	case EQ:
+		return boolValue(a == b)
+	default:
+		return boolValue(a != b)
+	}
+}
+
+func (self *TypedExpr) compileLiteral(l *Literal, vars map[string]Type) (typedFunc, Type, error) {
+	if l.variable == "" {
+		v := vector{l.val}
+		return func([]vector) vector { return v }, Scalar, nil
+	}
+	t, declared := vars[l.variable]
+	if !declared {
+		if v, ok := commonConstants[l.variable]; ok {
+			c := vector{v}
+			return func([]vector) vector { return c }, Scalar, nil
+		}
+	}
+	if t < Scalar || t > Vec3 {
+		return nil, 0, fmt.Errorf("%w: variable %v has the unknown type %v", ErrType, l.variable, t)
+	}
+	i := slices.Index(self.slots, l.variable)
+	if i < 0 {
+		i = len(self.slots)
+		self.slots = append(self.slots, l.variable)
+		self.slotTypes = append(self.slotTypes, t)
+	}
+	return func(vars []vector) vector { return vars[i] }, t, nil
+}
+
+func (self *TypedExpr) compileVectorFunc(n *Node, f *builtinFunc, vars map[string]Type) (typedFunc, Type, error) {
+	fs, types, err := self.compileAll(f.params, vars)
+	if err != nil {
+		return nil, 0, err
+	}
+	// check checks the types of the parameters
+	check := func(expected ...Type) error {
+		for i, t := range expected {
+			if types[i] != t {
+				return typeError(n, "parameter %v of %v is a %v, expected a %v", i+1, f.id, types[i], t)
+			}
+		}
+		return nil
+	}
+	isVector := types[0] == Vec2 || types[0] == Vec3
+	switch f.id {
+	case "vec2":
+		if err := check(Scalar, Scalar); err != nil {
+			return nil, 0, err
+		}
+		x, y := fs[0], fs[1]
+		return func(vars []vector) vector { return vector{x(vars)[0], y(vars)[0]} }, Vec2, nil
+	case "vec3":
+		if err := check(Scalar, Scalar, Scalar); err != nil {
+			return nil, 0, err
+		}
+		x, y, z := fs[0], fs[1], fs[2]
+		return func(vars []vector) vector { return vector{x(vars)[0], y(vars)[0], z(vars)[0]} }, Vec3, nil
+	case "dot", "distance":
+		if !isVector {
+			return nil, 0, typeError(n, "%v takes vectors, got a %v", f.id, types[0])
+		}
+		if err := check(types[0], types[0]); err != nil {
+			return nil, 0, err
+		}
+		a, b := fs[0], fs[1]
+		if f.id == "dot" {
+			return func(vars []vector) vector { return vector{a(vars).dot(b(vars))} }, Scalar, nil
+		}
+		return func(vars []vector) vector { return vector{a(vars).sub(b(vars)).length()} }, Scalar, nil
+	case "cross":
+		if !isVector {
+			return nil, 0, typeError(n, "cross takes vectors, got a %v", types[0])
+		}
+		if err := check(types[0], types[0]); err != nil {
+			return nil, 0, err
+		}
+		a, b := fs[0], fs[1]
+		if types[0] == Vec2 {
+			// the z component of the cross product of the vectors extended to 3D
+			return func(vars []vector) vector { return vector{a(vars).cross(b(vars))[2]} }, Scalar, nil
+		}
+		return func(vars []vector) vector { return a(vars).cross(b(vars)) }, Vec3, nil
+	case "length", "normalize":
+		if !isVector {
+			return nil, 0, typeError(n, "%v takes a vector, got a %v", f.id, types[0])
+		}
+		a := fs[0]
+		if f.id == "length" {
+			return func(vars []vector) vector { return vector{a(vars).length()} }, Scalar, nil
+		}
+		return func(vars []vector) vector {
+			v := a(vars)
+			if l := v.length(); l > 0 {
+				return v.scale(1 / l)
+			}
+			return v
+		}, types[0], nil
+	case "rotate":
+		if len(fs) == 2 {
+			if err := check(Vec2, Scalar); err != nil {
+				return nil, 0, err
+			}
+			v, angle := fs[0], fs[1]
+			return func(vars []vector) vector {
+				r := v(vars)
+				rotated := vec2.D{X: r[0], Y: r[1]}.Rotate(angle(vars)[0])
+				return vector{rotated.X, rotated.Y}
+			}, Vec2, nil
+		}
+		if err := check(Vec3, Vec3, Scalar); err != nil {
+			return nil, 0, err
+		}
+		v, axis, angle := fs[0], fs[1], fs[2]
+		return func(vars []vector) vector {
+			return v(vars).rotate(axis(vars), angle(vars)[0])
+		}, Vec3, nil
+	case "lerp":
+		if err := check(types[0], types[0], Scalar); err != nil {
+			return nil, 0, err
+		}
+		a, b, t := fs[0], fs[1], fs[2]
+		return func(vars []vector) vector {
+			from := a(vars)
+			return from.add(b(vars).sub(from).scale(t(vars)[0]))
+		}, types[0], nil
+	default:
+		panic(fmt.Sprintf("Unknown vector function in ParseTyped(): %v", f.id))
+	}
+}
+
+func typeError(n *Node, format string, args ...any) error {
+	return fmt.Errorf("%w: %v in %v", ErrType, fmt.Sprintf(format, args...), n)
+}
+
+func (a vector) add(b vector) vector {
+	return vector{a[0] + b[0], a[1] + b[1], a[2] + b[2]}
+}
+
+func (a vector) sub(b vector) vector {
+	return vector{a[0] - b[0], a[1] - b[1], a[2] - b[2]}
+}
+
+func (a vector) scale(s float64) vector {
+	return vector{a[0] * s, a[1] * s, a[2] * s}
+}
+
+func (a vector) dot(b vector) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+
+func (a vector) cross(b vector) vector {
+	return vector{a[1]*b[2] - a[2]*b[1], a[2]*b[0] - a[0]*b[2], a[0]*b[1] - a[1]*b[0]}
+}
+
+func (a vector) length() float64 {
+	return math.Sqrt(a.dot(a))
+}
+
+// rotate rotates a around axis by the right-hand rule like vec3.Quat, with Rodrigues' formula.
+// The axis doesn't have to be normalized.
+func (a vector) rotate(axis vector, angle float64) vector {
+	l := axis.length()
+	if l == 0 {
+		return a
+	}
+	k := axis.scale(1 / l)
+	sin, cos := math.Sincos(angle)
+	return a.scale(cos).add(k.cross(a).scale(sin)).add(k.scale(k.dot(a) * (1 - cos)))
+}
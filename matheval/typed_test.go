@@ -0,0 +1,231 @@
+package matheval
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/Lundis/go-gmath/fastmath"
+	"github.com/Lundis/go-gmath/vec2"
+	"github.com/Lundis/go-gmath/vec3"
+)
+
+var typedVars = map[string]Type{"p": Vec2, "v": Vec2, "axis": Vec3, "pos": Vec3}
+
+var typedValues = map[string]any{
+	"p":    vec2.D{X: 1, Y: 2},
+	"v":    vec2.D{X: 3, Y: -4},
+	"axis": vec3.F{Z: 2},
+	"pos":  vec3.F{X: 1, Y: 2, Z: 3},
+	"t":    0.5,
+}
+
+func TestTypedScalars(t *testing.T) {
+	tests := map[string]float64{
+		"p.x + p.y * 10":                 21,
+		"v.x^2":                          9,
+		"-v.y":                           4,
+		"(p + v).y":                      -2,
+		"vec2(t, 2 * t).y":               1,
+		"dot(p, v)":                      -5,
+		"length(v)":                      5,
+		"distance(p, v)":                 math.Sqrt(4 + 36),
+		"cross(p, v)":                    -10,
+		"length(normalize(v))":           1,
+		"pos.z > 2 && t < 1 ? pos.x : 0": 1,
+		"lerp(10, 20, t)":                15,
+		"max(p.x, p.y) + sin(0)":         2,
+	}
+	for expr, expected := range tests {
+		e, err := ParseTyped(expr, typedVars)
+		if err != nil {
+			t.Fatalf("Failed to parse %v: %v", expr, err)
+		}
+		if e.Type() != Scalar {
+			t.Errorf("%v: expected a scalar, got a %v", expr, e.Type())
+		}
+		result, err := e.EvaluateScalar(typedValues)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !fastmath.Equald(result, expected, 1e-9) {
+			t.Errorf("%v: expected %v, got %v", expr, expected, result)
+		}
+	}
+}
+
+func TestTypedVec2(t *testing.T) {
+	tests := map[string]vec2.D{
+		"p + v * t":                        {X: 2.5, Y: 0},
+		"2 * p - v":                        {X: -1, Y: 8},
+		"vec2(p.y, p.x) / 2":               {X: 1, Y: 0.5},
+		"normalize(v)":                     {X: 0.6, Y: -0.8},
+		"rotate(vec2(1, 0), pi / 2)":       vec2.D{X: 1}.Rotate(math.Pi / 2),
+		"rotate(p, -t)":                    vec2.D{X: 1, Y: 2}.Rotate(-0.5),
+		"lerp(p, v, t)":                    {X: 2, Y: -1},
+		"t > 1 ? p : -p":                   {X: -1, Y: -2},
+		"vec2(cos(t), sin(t)) * 0 + p":     {X: 1, Y: 2},
+		"normalize(vec2(0, 0))":            {X: 0, Y: 0},
+		"(p + v) * dot(p, vec2(1, 0))":     {X: 4, Y: -2},
+		"vec2(length(p - p), cross(p, p))": {X: 0, Y: 0},
+	}
+	for expr, expected := range tests {
+		e, err := ParseTyped(expr, typedVars)
+		if err != nil {
+			t.Fatalf("Failed to parse %v: %v", expr, err)
+		}
+		result, err := e.EvaluateVec2(typedValues)
+		if err != nil {
+			t.Fatalf("%v: %v", expr, err)
+		}
+		if !fastmath.Equald(result.X, expected.X, 1e-9) || !fastmath.Equald(result.Y, expected.Y, 1e-9) {
+			t.Errorf("%v: expected %v, got %v", expr, expected, result)
+		}
+	}
+}
+
+func TestTypedVec3(t *testing.T) {
+	tests := map[string]vec3.F{
+		"pos + vec3(1, 1, 1) * t":             {X: 1.5, Y: 2.5, Z: 3.5},
+		"cross(vec3(1, 0, 0), vec3(0, 1, 0))": {Z: 1},
+		"rotate(vec3(1, 0, 0), axis, pi / 2)": {Y: 1},
+		"rotate(pos, axis, 0)":                {X: 1, Y: 2, Z: 3},
+		"rotate(pos, axis, 1)":                vec3.NewQuatAxisAngle(vec3.F{Z: 2}, 1).Rotate(vec3.F{X: 1, Y: 2, Z: 3}),
+		"normalize(axis)":                     {Z: 1},
+		"lerp(pos, -pos, 1)":                  {X: -1, Y: -2, Z: -3},
+	}
+	for expr, expected := range tests {
+		e, err := ParseTyped(expr, typedVars)
+		if err != nil {
+			t.Fatalf("Failed to parse %v: %v", expr, err)
+		}
+		result, err := e.Evaluate(typedValues)
+		if err != nil {
+			t.Fatalf("%v: %v", expr, err)
+		}
+		v, ok := result.(vec3.F)
+		if !ok {
+			t.Fatalf("%v: expected a vec3.F, got %T", expr, result)
+		}
+		if v.DistanceTo(expected) > 1e-6 {
+			t.Errorf("%v: expected %v, got %v", expr, expected, v)
+		}
+	}
+}
+
+func TestTypedErrors(t *testing.T) {
+	tests := map[string]error{
+		"p + 1":              ErrType,
+		"p * v":              ErrType,
+		"2 / p":              ErrType,
+		"p^2":                ErrType,
+		"p < v":              ErrType,
+		"!p":                 ErrType,
+		"t ? p : 1":          ErrType,
+		"p ? 1 : 2":          ErrType,
+		"p.z":                ErrType,
+		"t.x":                ErrType,
+		"sin(p)":             ErrType,
+		"vec2(p, 1)":         ErrType,
+		"dot(p, pos)":        ErrType,
+		"dot(1, 2)":          ErrType,
+		"length(t)":          ErrType,
+		"rotate(pos, 1)":     ErrType,
+		"rotate(p, axis, 1)": ErrType,
+		"lerp(p, pos, t)":    ErrType,
+		"lerp(p, v, p)":      ErrType,
+		"vec2(1)":            ErrArity,
+		"rotate(p)":          ErrArity,
+		"p.w":                ErrSyntax,
+		"p.":                 ErrSyntax,
+		"p.1":                ErrSyntax,
+		"unknown(p)":         ErrUnknownFunction,
+	}
+	for expr, expected := range tests {
+		if _, err := ParseTyped(expr, typedVars); !errors.Is(err, expected) {
+			t.Errorf("%v: expected %v, got %v", expr, expected, err)
+		}
+	}
+
+	// vector syntax is only available in typed expressions
+	for _, expr := range []string{"p.x", "vec2(1, 2)"} {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Parsed %v as a scalar expression", expr)
+		}
+	}
+}
+
+func TestTypedEvaluateErrors(t *testing.T) {
+	e, err := ParseTyped("p * t", typedVars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Evaluate(map[string]any{"p": vec3.F{}}); !errors.Is(err, ErrType) {
+		t.Errorf("Expected a type error for a vec3.F variable, got %v", err)
+	}
+	if _, err := e.Evaluate(map[string]any{"t": 1}); !errors.Is(err, ErrType) {
+		t.Errorf("Expected a type error for an int variable, got %v", err)
+	}
+	if _, err := e.EvaluateScalar(typedValues); !errors.Is(err, ErrType) {
+		t.Errorf("Expected a type error for evaluating a vec2 as a scalar, got %v", err)
+	}
+	// missing variables are 0
+	if v, err := e.Evaluate(nil); err != nil || v != (vec2.D{}) {
+		t.Errorf("Expected the zero vector, got %v, %v", v, err)
+	}
+}
+
+func TestTypedString(t *testing.T) {
+	e, err := ParseTyped("(p + v).x * -v.y + vec2(1, 2).y", typedVars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := e.String(); s != "(p + v).x * -v.y + vec2(1, 2).y" {
+		t.Errorf("Printed as %v", s)
+	}
+}
+
+func TestTypedCustomFunctions(t *testing.T) {
+	funcs := DefaultFunctions()
+	funcs.Register1("double", func(x float64) float64 { return 2 * x })
+	e, err := Parser{Functions: funcs}.ParseTyped("vec2(double(p.x), p.y)", typedVars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := e.EvaluateVec2(typedValues); v != (vec2.D{X: 2, Y: 2}) {
+		t.Errorf("Expected (2, 2), got %v", v)
+	}
+}
+
+func TestTypedConcurrent(t *testing.T) {
+	funcs := DefaultFunctions()
+	funcs.RegisterVariadic("sum", 1, Variadic, func(args []float64) float64 {
+		total := 0.0
+		for _, a := range args {
+			total += a
+		}
+		return total
+	})
+	e, err := Parser{Functions: funcs}.ParseTyped("vec2(max(p.x, 0) + sin(p.y), sum(p.x, p.y, t))", typedVars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for g := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			x := float64(g)
+			expected := vec2.D{X: x + math.Sin(2*x), Y: 3*x + 1}
+			for range 1000 {
+				v, err := e.EvaluateVec2(map[string]any{"p": vec2.D{X: x, Y: 2 * x}, "t": 1.0})
+				if err != nil || v != expected {
+					t.Errorf("Expected %v, got %v, %v", expected, v, err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
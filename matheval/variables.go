@@ -0,0 +1,89 @@
+package matheval
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ErrUnknownVariable is returned by strict evaluation and validation when an expression uses a variable that
+// wasn't provided
+var ErrUnknownVariable = errors.New("Unknown variable")
+
+// Variables returns the free variables and the names of the functions used by the expression, sorted and without
+// duplicates. The constants pi and e are not free variables.
+func (self *Node) Variables() (variables []string, functions []string) {
+	vars := make(map[string]bool)
+	funcs := make(map[string]bool)
+	self.walk(func(n *Node) {
+		switch a := n.data.(type) {
+		case *Literal:
+			if _, constant := commonConstants[a.variable]; a.variable != "" && !constant {
+				vars[a.variable] = true
+			}
+		case *builtinFunc:
+			funcs[a.id] = true
+		}
+	})
+	return slices.Sorted(maps.Keys(vars)), slices.Sorted(maps.Keys(funcs))
+}
+
+// EvaluateStrict is like Evaluate, but returns an error wrapping ErrUnknownVariable instead of using 0 for
+// variables that are missing from vars
+func (self *Node) EvaluateStrict(vars map[string]float64) (float64, error) {
+	variables, _ := self.Variables()
+	var missing []string
+	for _, v := range variables {
+		if _, ok := vars[v]; !ok {
+			missing = append(missing, v)
+		}
+	}
+	if len(missing) > 0 {
+		return 0, unknownVariables(missing)
+	}
+	return self.Evaluate(vars), nil
+}
+
+// Validate parses expr with the default syntax and checks that it only uses the allowed variables
+func Validate(expr string, allowedVars []string) error {
+	return Parser{}.Validate(expr, allowedVars)
+}
+
+// Validate parses expr and checks that it only uses the allowed variables and the constants pi and e.
+// Syntax errors are returned as a *ParseError, and unknown variables as an error wrapping ErrUnknownVariable.
+func (self Parser) Validate(expr string, allowedVars []string) error {
+	node, err := self.Parse(expr)
+	if err != nil {
+		return err
+	}
+	variables, _ := node.Variables()
+	var missing []string
+	for _, v := range variables {
+		if !slices.Contains(allowedVars, v) {
+			missing = append(missing, v)
+		}
+	}
+	if len(missing) > 0 {
+		return unknownVariables(missing)
+	}
+	return nil
+}
+
+func unknownVariables(names []string) error {
+	return fmt.Errorf("%w: %v", ErrUnknownVariable, strings.Join(names, ", "))
+}
+
+// walk calls visit for self and all nodes below it, including function parameters
+func (self *Node) walk(visit func(*Node)) {
+	visit(self)
+	for _, n := range self.nodes {
+		n.walk(visit)
+	}
+	if f, ok := self.data.(*builtinFunc); ok {
+		for _, n := range f.params {
+			n.walk(visit)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package matheval
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestVariables(t *testing.T) {
+	node, err := Parse("level * pi + max(armor, lvl, 2) / sqrt(level) - e")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vars, funcs := node.Variables()
+	if !slices.Equal(vars, []string{"armor", "level", "lvl"}) {
+		t.Errorf("Unexpected variables %v", vars)
+	}
+	if !slices.Equal(funcs, []string{"max", "sqrt"}) {
+		t.Errorf("Unexpected functions %v", funcs)
+	}
+}
+
+func TestEvaluateStrict(t *testing.T) {
+	node, _ := Parse("level * 2 + lvel + armr")
+	if _, err := node.EvaluateStrict(map[string]float64{"level": 1, "armor": 2}); !errors.Is(err, ErrUnknownVariable) {
+		t.Errorf("Expected an unknown variable error, got %v", err)
+	} else if err.Error() != "Unknown variable: armr, lvel" {
+		t.Errorf("Unexpected message %v", err)
+	}
+	result, err := node.EvaluateStrict(map[string]float64{"level": 1, "lvel": 2, "armr": 3})
+	if err != nil || result != 7 {
+		t.Errorf("Expected 7, got %v, %v", result, err)
+	}
+	if result, err := EvalVariablesStrict("2 * pi - pi", nil); err != nil || result <= 3 {
+		t.Errorf("Expected pi, got %v, %v", result, err)
+	}
+	if _, err := EvalVariablesStrict("2 * x", nil); !errors.Is(err, ErrUnknownVariable) {
+		t.Errorf("Expected an unknown variable error, got %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	allowed := []string{"level", "armor"}
+	if err := Validate("level * 2 + armor / pi", allowed); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err := Validate("level * 2 + armour", allowed); !errors.Is(err, ErrUnknownVariable) {
+		t.Errorf("Expected an unknown variable error, got %v", err)
+	}
+	var perr *ParseError
+	if err := Validate("level * (2 + armor", allowed); !errors.As(err, &perr) {
+		t.Errorf("Expected a parse error, got %v", err)
+	}
+	funcs := NewFunctionSet()
+	funcs.Register1("twice", func(x float64) float64 { return 2 * x })
+	if err := (Parser{Functions: funcs}).Validate("twice(level)", allowed); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
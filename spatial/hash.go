@@ -0,0 +1,218 @@
+package spatial
+
+import (
+	"math"
+
+	"github.com/Lundis/go-gmath/grid"
+	"github.com/Lundis/go-gmath/vec2"
+)
+
+// Hash is a spatial hash that sorts items into a uniform grid of square cells.
+// It works best when items are roughly the size of a cell and spread out evenly.
+// Items are stored in every cell they overlap. Cells are kept when they become empty, so moving items around doesn't allocate.
+type Hash[K comparable] struct {
+	store[K]
+	cellSize float32
+	cells    map[vec2.I][]int32
+}
+
+func NewHash[K comparable](cellSize float32) *Hash[K] {
+	return &Hash[K]{
+		store:    newStore[K](),
+		cellSize: cellSize,
+		cells:    make(map[vec2.I][]int32),
+	}
+}
+
+func (h *Hash[K]) CellSize() float32 {
+	return h.cellSize
+}
+
+func (h *Hash[K]) Len() int {
+	return len(h.ids)
+}
+
+func (h *Hash[K]) Bounds(id K) (vec2.Rect, bool) {
+	return h.bounds(id)
+}
+
+func (h *Hash[K]) Insert(id K, bounds vec2.Rect) {
+	if h.Move(id, bounds) {
+		return
+	}
+	index := h.add(id, bounds)
+	h.addToCells(index, h.cellRange(bounds))
+}
+
+func (h *Hash[K]) Remove(id K) bool {
+	index, ok := h.ids[id]
+	if !ok {
+		return false
+	}
+	h.removeFromCells(index, h.cellRange(h.items[index].bounds))
+	h.remove(index)
+	return true
+}
+
+func (h *Hash[K]) Move(id K, bounds vec2.Rect) bool {
+	index, ok := h.ids[id]
+	if !ok {
+		return false
+	}
+	oldCells := h.cellRange(h.items[index].bounds)
+	newCells := h.cellRange(bounds)
+	h.items[index].bounds = bounds
+	if oldCells != newCells {
+		h.removeFromCells(index, oldCells)
+		h.addToCells(index, newCells)
+	}
+	return true
+}
+
+func (h *Hash[K]) QueryRect(r vec2.Rect, dst []K) []K {
+	stamp := h.nextStamp()
+	cells := h.cellRange(r)
+	for y := cells.Min.Y; y <= cells.Max.Y; y++ {
+		for x := cells.Min.X; x <= cells.Max.X; x++ {
+			for _, index := range h.cells[vec2.I{X: x, Y: y}] {
+				if h.visit(index, stamp) && overlaps(r, h.items[index].bounds) {
+					dst = append(dst, h.items[index].id)
+				}
+			}
+		}
+	}
+	return dst
+}
+
+func (h *Hash[K]) QueryRadius(center vec2.F, radius float32, dst []K) []K {
+	stamp := h.nextStamp()
+	radiusSq := radius * radius
+	cells := h.cellRange(vec2.NewRectCentered(center, vec2.F{X: 2 * radius, Y: 2 * radius}))
+	for y := cells.Min.Y; y <= cells.Max.Y; y++ {
+		for x := cells.Min.X; x <= cells.Max.X; x++ {
+			for _, index := range h.cells[vec2.I{X: x, Y: y}] {
+				if h.visit(index, stamp) && distanceSquaredToRect(center, h.items[index].bounds) <= radiusSq {
+					dst = append(dst, h.items[index].id)
+				}
+			}
+		}
+	}
+	return dst
+}
+
+func (h *Hash[K]) QueryRay(r vec2.Ray2, maxT float32, dst []K) []K {
+	stamp := h.nextStamp()
+	size := vec2.F{X: h.cellSize, Y: h.cellSize}
+	for cell := range grid.Traverse(r.Origin, r.At(maxT), size) {
+		for _, index := range h.cells[cell] {
+			if h.visit(index, stamp) && hitsRect(r, maxT, h.items[index].bounds) {
+				dst = append(dst, h.items[index].id)
+			}
+		}
+	}
+	return dst
+}
+
+// Nearest searches rings of cells around p until no closer item can exist.
+// Once the rings contain more cells than there are items it checks the remaining items directly instead.
+func (h *Hash[K]) Nearest(p vec2.F, maxDistance float32) (id K, distance float32, ok bool) {
+	if len(h.ids) == 0 {
+		return
+	}
+	stamp := h.nextStamp()
+	bestSq := maxDistance * maxDistance
+	best := int32(-1)
+	check := func(index int32) {
+		if !h.visit(index, stamp) {
+			return
+		}
+		if d := distanceSquaredToRect(p, h.items[index].bounds); d <= bestSq {
+			bestSq = d
+			best = index
+		}
+	}
+
+	center := h.cellOf(p)
+	for ring := int32(0); ; ring++ {
+		// anything in this ring is at least (ring - 1) cells away
+		minDist := float32(ring-1) * h.cellSize
+		if ring > 0 && (minDist*minDist > bestSq || minDist > maxDistance) {
+			break
+		}
+		if int(8*ring) > len(h.items) {
+			for i := range h.items {
+				if h.items[i].alive {
+					check(int32(i))
+				}
+			}
+			break
+		}
+		h.forRing(center, ring, func(cell vec2.I) {
+			for _, index := range h.cells[cell] {
+				check(index)
+			}
+		})
+	}
+	if best < 0 {
+		return
+	}
+	return h.items[best].id, float32(math.Sqrt(float64(bestSq))), true
+}
+
+// forRing calls f for every cell whose Chebyshev distance to center is ring
+func (h *Hash[K]) forRing(center vec2.I, ring int32, f func(vec2.I)) {
+	if ring == 0 {
+		f(center)
+		return
+	}
+	for x := center.X - ring; x <= center.X+ring; x++ {
+		f(vec2.I{X: x, Y: center.Y - ring})
+		f(vec2.I{X: x, Y: center.Y + ring})
+	}
+	for y := center.Y - ring + 1; y < center.Y+ring; y++ {
+		f(vec2.I{X: center.X - ring, Y: y})
+		f(vec2.I{X: center.X + ring, Y: y})
+	}
+}
+
+func (h *Hash[K]) cellOf(p vec2.F) vec2.I {
+	return vec2.I{
+		X: int32(math.Floor(float64(p.X / h.cellSize))),
+		Y: int32(math.Floor(float64(p.Y / h.cellSize))),
+	}
+}
+
+// cellRange returns the first and last cell that r overlaps
+func (h *Hash[K]) cellRange(r vec2.Rect) vec2.RectI {
+	return vec2.RectI{Min: h.cellOf(r.Min), Max: h.cellOf(r.Max)}
+}
+
+func (h *Hash[K]) addToCells(index int32, cells vec2.RectI) {
+	for y := cells.Min.Y; y <= cells.Max.Y; y++ {
+		for x := cells.Min.X; x <= cells.Max.X; x++ {
+			key := vec2.I{X: x, Y: y}
+			h.cells[key] = append(h.cells[key], index)
+		}
+	}
+}
+
+func (h *Hash[K]) removeFromCells(index int32, cells vec2.RectI) {
+	for y := cells.Min.Y; y <= cells.Max.Y; y++ {
+		for x := cells.Min.X; x <= cells.Max.X; x++ {
+			key := vec2.I{X: x, Y: y}
+			h.cells[key] = removeIndex(h.cells[key], index)
+		}
+	}
+}
+
+// removeIndex swap-removes index from s
+func removeIndex(s []int32, index int32) []int32 {
+	for i, v := range s {
+		if v == index {
+			last := len(s) - 1
+			s[i] = s[last]
+			return s[:last]
+		}
+	}
+	return s
+}
@@ -0,0 +1,252 @@
+package spatial
+
+import (
+	"math"
+
+	"github.com/Lundis/go-gmath/vec2"
+)
+
+// Quadtree is a loose quadtree. Every node covers a square cell, but holds items whose center lies in the cell and
+// whose size is at most the size of the cell, so the items of a node lie within the cell expanded by half its size.
+// That lets every item live in exactly one node no matter how it straddles cell borders, which makes Move cheap.
+// It works well for items of varying sizes and unevenly spread out scenes.
+// Items outside of the root bounds are kept in the root node, so the tree still works if they leave the expected area.
+type Quadtree[K comparable] struct {
+	store[K]
+	maxDepth int
+	nodes    []quadNode
+}
+
+type quadNode struct {
+	// cell is the area that the centers of the items in this node lie in
+	cell vec2.Rect
+	// loose is the area that the items in this node lie in
+	loose vec2.Rect
+	// children is the index of the first of four child nodes, or 0 for leaves
+	children int32
+	items    []int32
+}
+
+// NewQuadtree creates a quadtree covering bounds, which is made square if it isn't.
+// maxDepth limits how many times the root can be subdivided. Small items and points always end up at the deepest level,
+// so pick it so that the smallest cells are around the size of the smallest items.
+func NewQuadtree[K comparable](bounds vec2.Rect, maxDepth int) *Quadtree[K] {
+	size := max(bounds.Width(), bounds.Height())
+	cell := vec2.Rect{Min: bounds.Min, Max: bounds.Min.AddScalar(size)}
+	return &Quadtree[K]{
+		store:    newStore[K](),
+		maxDepth: maxDepth,
+		nodes:    []quadNode{newQuadNode(cell)},
+	}
+}
+
+func (q *Quadtree[K]) Len() int {
+	return len(q.ids)
+}
+
+func (q *Quadtree[K]) Bounds(id K) (vec2.Rect, bool) {
+	return q.bounds(id)
+}
+
+func (q *Quadtree[K]) Insert(id K, bounds vec2.Rect) {
+	if q.Move(id, bounds) {
+		return
+	}
+	index := q.add(id, bounds)
+	q.place(index)
+}
+
+func (q *Quadtree[K]) Remove(id K) bool {
+	index, ok := q.ids[id]
+	if !ok {
+		return false
+	}
+	q.unplace(index)
+	q.remove(index)
+	return true
+}
+
+func (q *Quadtree[K]) Move(id K, bounds vec2.Rect) bool {
+	index, ok := q.ids[id]
+	if !ok {
+		return false
+	}
+	q.items[index].bounds = bounds
+	if q.nodeFor(bounds, false) != q.items[index].node {
+		q.unplace(index)
+		q.place(index)
+	}
+	return true
+}
+
+func (q *Quadtree[K]) QueryRect(r vec2.Rect, dst []K) []K {
+	return q.queryRect(0, r, dst)
+}
+
+func (q *Quadtree[K]) queryRect(node int32, r vec2.Rect, dst []K) []K {
+	n := &q.nodes[node]
+	for _, index := range n.items {
+		if overlaps(r, q.items[index].bounds) {
+			dst = append(dst, q.items[index].id)
+		}
+	}
+	if n.children != 0 {
+		for child := n.children; child < n.children+4; child++ {
+			if overlaps(r, q.nodes[child].loose) {
+				dst = q.queryRect(child, r, dst)
+			}
+		}
+	}
+	return dst
+}
+
+func (q *Quadtree[K]) QueryRadius(center vec2.F, radius float32, dst []K) []K {
+	return q.queryRadius(0, center, radius*radius, dst)
+}
+
+func (q *Quadtree[K]) queryRadius(node int32, center vec2.F, radiusSq float32, dst []K) []K {
+	n := &q.nodes[node]
+	for _, index := range n.items {
+		if distanceSquaredToRect(center, q.items[index].bounds) <= radiusSq {
+			dst = append(dst, q.items[index].id)
+		}
+	}
+	if n.children != 0 {
+		for child := n.children; child < n.children+4; child++ {
+			if distanceSquaredToRect(center, q.nodes[child].loose) <= radiusSq {
+				dst = q.queryRadius(child, center, radiusSq, dst)
+			}
+		}
+	}
+	return dst
+}
+
+func (q *Quadtree[K]) QueryRay(r vec2.Ray2, maxT float32, dst []K) []K {
+	return q.queryRay(0, r, maxT, dst)
+}
+
+func (q *Quadtree[K]) queryRay(node int32, r vec2.Ray2, maxT float32, dst []K) []K {
+	n := &q.nodes[node]
+	for _, index := range n.items {
+		if hitsRect(r, maxT, q.items[index].bounds) {
+			dst = append(dst, q.items[index].id)
+		}
+	}
+	if n.children != 0 {
+		for child := n.children; child < n.children+4; child++ {
+			if hitsRect(r, maxT, q.nodes[child].loose) {
+				dst = q.queryRay(child, r, maxT, dst)
+			}
+		}
+	}
+	return dst
+}
+
+func (q *Quadtree[K]) Nearest(p vec2.F, maxDistance float32) (id K, distance float32, ok bool) {
+	best, bestSq := q.nearest(0, p, int32(-1), maxDistance*maxDistance)
+	if best < 0 {
+		return
+	}
+	return q.items[best].id, float32(math.Sqrt(float64(bestSq))), true
+}
+
+// nearest searches the children closest to p first, and skips nodes that can't contain anything closer than bestSq
+func (q *Quadtree[K]) nearest(node int32, p vec2.F, best int32, bestSq float32) (int32, float32) {
+	n := &q.nodes[node]
+	for _, index := range n.items {
+		if d := distanceSquaredToRect(p, q.items[index].bounds); d <= bestSq {
+			best, bestSq = index, d
+		}
+	}
+	if n.children == 0 {
+		return best, bestSq
+	}
+	var order [4]int32
+	var dist [4]float32
+	for i := range order {
+		order[i] = n.children + int32(i)
+		dist[i] = distanceSquaredToRect(p, q.nodes[order[i]].loose)
+		// insertion sort by distance
+		for j := i; j > 0 && dist[j] < dist[j-1]; j-- {
+			order[j], order[j-1] = order[j-1], order[j]
+			dist[j], dist[j-1] = dist[j-1], dist[j]
+		}
+	}
+	for i, child := range order {
+		if dist[i] > bestSq {
+			break
+		}
+		best, bestSq = q.nearest(child, p, best, bestSq)
+	}
+	return best, bestSq
+}
+
+// place adds an item to the node that fits it, creating nodes as needed
+func (q *Quadtree[K]) place(index int32) {
+	node := q.nodeFor(q.items[index].bounds, true)
+	q.items[index].node = node
+	q.nodes[node].items = append(q.nodes[node].items, index)
+}
+
+func (q *Quadtree[K]) unplace(index int32) {
+	node := q.items[index].node
+	q.nodes[node].items = removeIndex(q.nodes[node].items, index)
+}
+
+// nodeFor finds the deepest node that can hold bounds. Missing nodes are created if create is true,
+// otherwise the deepest existing node is returned.
+func (q *Quadtree[K]) nodeFor(bounds vec2.Rect, create bool) int32 {
+	root := q.nodes[0].cell
+	center := bounds.Center()
+	size := max(bounds.Width(), bounds.Height())
+	if !root.Contains(center) || size > root.Width() {
+		return 0
+	}
+	node := int32(0)
+	for depth := 0; depth < q.maxDepth; depth++ {
+		n := &q.nodes[node]
+		// children are half the size of this node
+		if size > n.cell.Width()/2 {
+			break
+		}
+		if n.children == 0 {
+			if !create {
+				break
+			}
+			q.split(node)
+			n = &q.nodes[node]
+		}
+		mid := n.cell.Center()
+		child := n.children
+		if center.X >= mid.X {
+			child++
+		}
+		if center.Y >= mid.Y {
+			child += 2
+		}
+		node = child
+	}
+	return node
+}
+
+// split creates the four children of node, in the order top-left, top-right, bottom-left, bottom-right
+func (q *Quadtree[K]) split(node int32) {
+	cell := q.nodes[node].cell
+	mid := cell.Center()
+	first := int32(len(q.nodes))
+	q.nodes[node].children = first
+	for i := 0; i < 4; i++ {
+		child := vec2.Rect{Min: cell.Min, Max: mid}
+		if i&1 != 0 {
+			child.Min.X, child.Max.X = mid.X, cell.Max.X
+		}
+		if i&2 != 0 {
+			child.Min.Y, child.Max.Y = mid.Y, cell.Max.Y
+		}
+		q.nodes = append(q.nodes, newQuadNode(child))
+	}
+}
+
+func newQuadNode(cell vec2.Rect) quadNode {
+	return quadNode{cell: cell, loose: cell.Expand(cell.Width() / 2)}
+}
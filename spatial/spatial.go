@@ -0,0 +1,123 @@
+// Package spatial contains broad-phase indexes that answer "which items are near this point or rect".
+//
+// Items are identified by a comparable key and stored with their axis-aligned bounds. Points are stored as
+// empty rects. Queries append to a caller-provided slice and don't allocate once that slice has grown large enough.
+// Queries update internal bookkeeping, so an index must not be queried from several goroutines at once.
+package spatial
+
+import (
+	"github.com/Lundis/go-gmath/vec2"
+)
+
+// Index is implemented by both Hash and Quadtree, so they can be swapped depending on the scene
+type Index[K comparable] interface {
+	// Insert adds id with the given bounds. Inserting an id that already exists moves it.
+	Insert(id K, bounds vec2.Rect)
+	// Remove removes id and returns false if it didn't exist
+	Remove(id K) bool
+	// Move updates the bounds of id and returns false if it doesn't exist
+	Move(id K, bounds vec2.Rect) bool
+	// Bounds returns the bounds that id was inserted with
+	Bounds(id K) (vec2.Rect, bool)
+	Len() int
+	// QueryRect appends all items whose bounds overlap or touch r to dst
+	QueryRect(r vec2.Rect, dst []K) []K
+	// QueryRadius appends all items whose bounds are within radius of center to dst
+	QueryRadius(center vec2.F, radius float32, dst []K) []K
+	// QueryRay appends all items whose bounds are hit by r within maxT to dst, in no particular order. maxT must be finite.
+	QueryRay(r vec2.Ray2, maxT float32, dst []K) []K
+	// Nearest returns the item whose bounds are closest to p, ignoring items further away than maxDistance
+	Nearest(p vec2.F, maxDistance float32) (id K, distance float32, ok bool)
+}
+
+// InsertPoint is a shorthand for inserting a point-sized item
+func InsertPoint[K comparable](index Index[K], id K, p vec2.F) {
+	index.Insert(id, vec2.Rect{Min: p, Max: p})
+}
+
+type item[K comparable] struct {
+	id     K
+	bounds vec2.Rect
+	// stamp is the last query that visited this item, used to report items that span several cells only once
+	stamp uint32
+	// node is the quadtree node that holds this item
+	node  int32
+	alive bool
+}
+
+// store keeps the items of an index in a slice, reusing the slots of removed items
+type store[K comparable] struct {
+	items []item[K]
+	ids   map[K]int32
+	free  []int32
+	stamp uint32
+}
+
+func newStore[K comparable]() store[K] {
+	return store[K]{ids: make(map[K]int32)}
+}
+
+func (s *store[K]) add(id K, bounds vec2.Rect) int32 {
+	it := item[K]{id: id, bounds: bounds, alive: true}
+	var index int32
+	if n := len(s.free); n > 0 {
+		index = s.free[n-1]
+		s.free = s.free[:n-1]
+		s.items[index] = it
+	} else {
+		index = int32(len(s.items))
+		s.items = append(s.items, it)
+	}
+	s.ids[id] = index
+	return index
+}
+
+func (s *store[K]) remove(index int32) {
+	delete(s.ids, s.items[index].id)
+	s.items[index] = item[K]{}
+	s.free = append(s.free, index)
+}
+
+// nextStamp starts a new query
+func (s *store[K]) nextStamp() uint32 {
+	s.stamp++
+	if s.stamp == 0 {
+		// wrapped around, clear the old stamps so they can't collide with new ones
+		for i := range s.items {
+			s.items[i].stamp = 0
+		}
+		s.stamp = 1
+	}
+	return s.stamp
+}
+
+// visit returns true the first time an item is visited during the query with the given stamp
+func (s *store[K]) visit(index int32, stamp uint32) bool {
+	if s.items[index].stamp == stamp {
+		return false
+	}
+	s.items[index].stamp = stamp
+	return true
+}
+
+func (s *store[K]) bounds(id K) (vec2.Rect, bool) {
+	if index, ok := s.ids[id]; ok {
+		return s.items[index].bounds, true
+	}
+	return vec2.Rect{}, false
+}
+
+// overlaps is like Rect.Intersects, but includes touching edges so that points can be found
+func overlaps(a, b vec2.Rect) bool {
+	return a.Min.X <= b.Max.X && b.Min.X <= a.Max.X &&
+		a.Min.Y <= b.Max.Y && b.Min.Y <= a.Max.Y
+}
+
+func distanceSquaredToRect(p vec2.F, r vec2.Rect) float32 {
+	return p.DistanceToSquared(r.ClampPoint(p))
+}
+
+func hitsRect(r vec2.Ray2, maxT float32, bounds vec2.Rect) bool {
+	_, hit := r.CastRect(bounds, maxT)
+	return hit
+}
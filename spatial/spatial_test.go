@@ -0,0 +1,186 @@
+package spatial
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/Lundis/go-gmath/vec2"
+	"github.com/stretchr/testify/assert"
+)
+
+func indexes() map[string]func() Index[int] {
+	return map[string]func() Index[int]{
+		"hash": func() Index[int] { return NewHash[int](10) },
+		"quadtree": func() Index[int] {
+			return NewQuadtree[int](vec2.NewRect(0, 0, 200, 200), 6)
+		},
+	}
+}
+
+func rect(x, y, w, h float32) vec2.Rect {
+	return vec2.NewRect(x, y, w, h)
+}
+
+func sorted(ids []int) []int {
+	slices.Sort(ids)
+	return ids
+}
+
+func TestIndexBasics(t *testing.T) {
+	for name, create := range indexes() {
+		t.Run(name, func(t *testing.T) {
+			index := create()
+			index.Insert(1, rect(5, 5, 2, 2))
+			index.Insert(2, rect(50, 50, 30, 5))
+			InsertPoint(index, 3, vec2.F{X: 100, Y: 100})
+			// outside of the quadtree root
+			index.Insert(4, rect(-500, 30, 10, 10))
+			assert.Equal(t, 4, index.Len())
+
+			assert.Equal(t, []int{1}, index.QueryRect(rect(0, 0, 10, 10), nil))
+			assert.Equal(t, []int{2, 3}, sorted(index.QueryRect(rect(60, 50, 40, 50), nil)))
+			assert.Equal(t, []int{4}, index.QueryRect(rect(-600, 0, 200, 100), nil))
+			assert.Equal(t, []int{3}, index.QueryRadius(vec2.F{X: 103, Y: 104}, 5, nil))
+			assert.Empty(t, index.QueryRadius(vec2.F{X: 103, Y: 104}, 4.9, nil))
+
+			r := vec2.NewRay2(vec2.F{X: 0, Y: 52}, vec2.F{X: 1})
+			assert.Equal(t, []int{2}, index.QueryRay(r, 1000, nil))
+			assert.Empty(t, index.QueryRay(r, 40, nil))
+
+			id, d, ok := index.Nearest(vec2.F{X: 90, Y: 90}, math.MaxFloat32)
+			assert.True(t, ok)
+			assert.Equal(t, 3, id)
+			assert.InDelta(t, math.Sqrt(200), d, 0.001)
+			_, _, ok = index.Nearest(vec2.F{X: 90, Y: 90}, 10)
+			assert.False(t, ok)
+
+			assert.True(t, index.Move(3, rect(6, 6, 1, 1)))
+			assert.Equal(t, []int{1, 3}, sorted(index.QueryRect(rect(0, 0, 10, 10), nil)))
+			index.Insert(3, rect(150, 150, 1, 1))
+			assert.Equal(t, []int{1}, index.QueryRect(rect(0, 0, 10, 10), nil))
+			b, _ := index.Bounds(3)
+			assert.Equal(t, rect(150, 150, 1, 1), b)
+
+			assert.True(t, index.Remove(1))
+			assert.False(t, index.Remove(1))
+			assert.False(t, index.Move(1, rect(0, 0, 1, 1)))
+			assert.Empty(t, index.QueryRect(rect(0, 0, 10, 10), nil))
+			assert.Equal(t, 3, index.Len())
+		})
+	}
+}
+
+func TestIndexMatchesBruteForce(t *testing.T) {
+	for name, create := range indexes() {
+		t.Run(name, func(t *testing.T) {
+			rng := rand.New(rand.NewPCG(1, 2))
+			random := func(n float32) float32 { return rng.Float32() * n }
+			randomRect := func() vec2.Rect {
+				return rect(random(220)-10, random(220)-10, random(random(40)), random(random(40)))
+			}
+
+			index := create()
+			bounds := map[int]vec2.Rect{}
+			for i := 0; i < 300; i++ {
+				bounds[i] = randomRect()
+				index.Insert(i, bounds[i])
+			}
+			for i := 0; i < 300; i += 3 {
+				bounds[i] = randomRect()
+				index.Move(i, bounds[i])
+			}
+			for i := 0; i < 300; i += 7 {
+				delete(bounds, i)
+				index.Remove(i)
+			}
+
+			var got []int
+			for q := 0; q < 100; q++ {
+				query := randomRect()
+				var want []int
+				for id, b := range bounds {
+					if overlaps(query, b) {
+						want = append(want, id)
+					}
+				}
+				got = index.QueryRect(query, got[:0])
+				assert.ElementsMatch(t, want, got)
+
+				p := query.Min
+				radius := random(30)
+				want = want[:0]
+				bestID, bestDist := -1, float32(math.MaxFloat32)
+				for id, b := range bounds {
+					d := float32(math.Sqrt(float64(distanceSquaredToRect(p, b))))
+					if d <= radius {
+						want = append(want, id)
+					}
+					if d < bestDist {
+						bestID, bestDist = id, d
+					}
+				}
+				got = index.QueryRadius(p, radius, got[:0])
+				assert.ElementsMatch(t, want, got)
+
+				id, d, ok := index.Nearest(p, math.MaxFloat32)
+				assert.True(t, ok)
+				assert.InDelta(t, bestDist, d, 0.0001)
+				if bestDist != d {
+					assert.Equal(t, bestID, id)
+				}
+
+				r, length := vec2.NewRay2Between(p, query.Max)
+				want = want[:0]
+				for id, b := range bounds {
+					if _, hit := r.CastRect(b, length); hit {
+						want = append(want, id)
+					}
+				}
+				got = index.QueryRay(r, length, got[:0])
+				assert.ElementsMatch(t, want, got)
+			}
+		})
+	}
+}
+
+func TestIndexNoAllocations(t *testing.T) {
+	for name, create := range indexes() {
+		t.Run(name, func(t *testing.T) {
+			index := create()
+			for i := 0; i < 100; i++ {
+				x, y := float32(i%10)*20, float32(i/10)*20
+				index.Insert(i, rect(x, y, 5, 5))
+			}
+			dst := make([]int, 0, 100)
+			r := vec2.NewRay2(vec2.F{X: 1, Y: 1}, vec2.F{X: 1, Y: 1})
+			allocs := testing.AllocsPerRun(10, func() {
+				dst = index.QueryRect(rect(10, 10, 50, 50), dst[:0])
+				dst = index.QueryRadius(vec2.F{X: 50, Y: 50}, 30, dst[:0])
+				dst = index.QueryRay(r, 200, dst[:0])
+				index.Nearest(vec2.F{X: 77, Y: 33}, math.MaxFloat32)
+				index.Move(5, rect(3, 3, 5, 5))
+				index.Move(5, rect(100, 0, 5, 5))
+			})
+			assert.Equal(t, float64(0), allocs)
+		})
+	}
+}
+
+func BenchmarkQueryRadius(b *testing.B) {
+	for name, create := range indexes() {
+		b.Run(name, func(b *testing.B) {
+			index := create()
+			rng := rand.New(rand.NewPCG(1, 2))
+			for i := 0; i < 1000; i++ {
+				InsertPoint(index, i, vec2.F{X: rng.Float32() * 200, Y: rng.Float32() * 200})
+			}
+			dst := make([]int, 0, 1000)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				dst = index.QueryRadius(vec2.F{X: 100, Y: 100}, 15, dst[:0])
+			}
+		})
+	}
+}
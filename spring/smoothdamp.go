@@ -0,0 +1,93 @@
+package spring
+
+import (
+	"math"
+
+	"github.com/Lundis/go-gmath/vec2"
+	"github.com/Lundis/go-gmath/vec3"
+)
+
+// SmoothDamp moves current towards target like Unity's Mathf.SmoothDamp, i.e. as a critically damped spring
+// that reaches the target in roughly smoothTime seconds without overshooting.
+// velocity is the velocity returned by the previous call, or 0 at the start.
+// maxSpeed limits how fast the value may move, use math.MaxFloat32 for no limit.
+func SmoothDamp(current, target, velocity, smoothTime, maxSpeed, dt float32) (newValue, newVelocity float32) {
+	omega, decay, ok := smoothDampFactors(smoothTime, dt)
+	if !ok {
+		return current, velocity
+	}
+	originalTarget := target
+	maxChange := maxSpeed * smoothTime
+	change := min(max(current-target, -maxChange), maxChange)
+	target = current - change
+
+	temp := (velocity + omega*change) * dt
+	newVelocity = (velocity - omega*temp) * decay
+	newValue = target + (change+temp)*decay
+
+	// never overshoot the original target
+	if (originalTarget-current > 0) == (newValue > originalTarget) {
+		return originalTarget, 0
+	}
+	return
+}
+
+// SmoothDamp2 is SmoothDamp for vec2.F. maxSpeed limits the length of the velocity.
+func SmoothDamp2(current, target, velocity vec2.F, smoothTime, maxSpeed, dt float32) (newValue, newVelocity vec2.F) {
+	omega, decay, ok := smoothDampFactors(smoothTime, dt)
+	if !ok {
+		return current, velocity
+	}
+	originalTarget := target
+	change := current.Sub(target)
+	maxChange := maxSpeed * smoothTime
+	if length := change.Magnitude(); length > maxChange {
+		change = change.MulScalar(maxChange / length)
+	}
+	target = current.Sub(change)
+
+	temp := velocity.Add(change.MulScalar(omega)).MulScalar(dt)
+	newVelocity = velocity.Sub(temp.MulScalar(omega)).MulScalar(decay)
+	newValue = target.Add(change.Add(temp).MulScalar(decay))
+
+	if originalTarget.Sub(current).Dot(newValue.Sub(originalTarget)) > 0 {
+		return originalTarget, vec2.F{}
+	}
+	return
+}
+
+// SmoothDamp3 is SmoothDamp for vec3.F. maxSpeed limits the length of the velocity.
+func SmoothDamp3(current, target, velocity vec3.F, smoothTime, maxSpeed, dt float32) (newValue, newVelocity vec3.F) {
+	omega, decay, ok := smoothDampFactors(smoothTime, dt)
+	if !ok {
+		return current, velocity
+	}
+	originalTarget := target
+	change := current.Sub(target)
+	maxChange := maxSpeed * smoothTime
+	if length := change.Magnitude(); length > maxChange {
+		change = change.MulScalar(maxChange / length)
+	}
+	target = current.Sub(change)
+
+	temp := velocity.Add(change.MulScalar(omega)).MulScalar(dt)
+	newVelocity = velocity.Sub(temp.MulScalar(omega)).MulScalar(decay)
+	newValue = target.Add(change.Add(temp).MulScalar(decay))
+
+	if originalTarget.Sub(current).Dot(newValue.Sub(originalTarget)) > 0 {
+		return originalTarget, vec3.F{}
+	}
+	return
+}
+
+// smoothDampFactors returns the angular frequency of the critically damped spring, and how much it decays during dt.
+// Unity approximates the decay with a polynomial, but the exact exponential is cheap enough and works for any dt.
+func smoothDampFactors(smoothTime, dt float32) (omega, decay float32, ok bool) {
+	if dt <= 0 {
+		return 0, 0, false
+	}
+	smoothTime = max(smoothTime, 0.0001)
+	omega = 2 / smoothTime
+	decay = float32(math.Exp(-float64(omega * dt)))
+	return omega, decay, true
+}
@@ -0,0 +1,147 @@
+// Package spring contains physically based smoothing that keeps its velocity when the target changes mid-flight,
+// which makes it a good fit for camera follow and UI motion.
+//
+// All functions step the motion with closed-form solutions of the underlying differential equations,
+// so they are stable and give the same result for any timestep.
+package spring
+
+import (
+	"math"
+
+	"github.com/Lundis/go-gmath/vec2"
+	"github.com/Lundis/go-gmath/vec3"
+)
+
+// Spring is a damped harmonic oscillator that pulls a value towards a target
+type Spring struct {
+	// AngularFrequency is the undamped angular frequency in radians per second. Higher values move faster.
+	AngularFrequency float32
+	// DampingRatio is 1 for critical damping, the fastest motion that doesn't overshoot.
+	// Lower values oscillate around the target, and higher values approach it more slowly.
+	DampingRatio float32
+}
+
+// NewSpring creates a spring that oscillates frequency times per second when undamped
+func NewSpring(frequency, dampingRatio float32) Spring {
+	return Spring{AngularFrequency: 2 * math.Pi * frequency, DampingRatio: dampingRatio}
+}
+
+// NewSpringPhysical creates a spring from the stiffness and damping coefficients of a mass on a spring
+func NewSpringPhysical(stiffness, damping, mass float32) Spring {
+	if stiffness <= 0 || mass <= 0 {
+		return Spring{}
+	}
+	omega := math.Sqrt(float64(stiffness / mass))
+	return Spring{
+		AngularFrequency: float32(omega),
+		DampingRatio:     float32(float64(damping) / (2 * math.Sqrt(float64(stiffness*mass)))),
+	}
+}
+
+// Stiffness returns the spring constant of the spring when used with the given mass
+func (s Spring) Stiffness(mass float32) float32 {
+	return s.AngularFrequency * s.AngularFrequency * mass
+}
+
+// Damping returns the damping coefficient of the spring when used with the given mass
+func (s Spring) Damping(mass float32) float32 {
+	return 2 * s.DampingRatio * s.AngularFrequency * mass
+}
+
+func (s Spring) Update(pos, vel, target, dt float32) (newPos, newVel float32) {
+	return s.Coefficients(dt).Update(pos, vel, target)
+}
+
+func (s Spring) Update2(pos, vel, target vec2.F, dt float32) (newPos, newVel vec2.F) {
+	return s.Coefficients(dt).Update2(pos, vel, target)
+}
+
+func (s Spring) Update3(pos, vel, target vec3.F, dt float32) (newPos, newVel vec3.F) {
+	return s.Coefficients(dt).Update3(pos, vel, target)
+}
+
+// Coefficients describe how a spring moves during one timestep, as linear combinations of the position and velocity.
+// Calculating them is the expensive part of a step, so reuse them when updating many values with the same spring and timestep.
+type Coefficients struct {
+	PosPos, PosVel float32
+	VelPos, VelVel float32
+}
+
+// Coefficients calculates the closed-form solution of the spring for a timestep of dt seconds
+func (s Spring) Coefficients(dt float32) Coefficients {
+	const epsilon = 0.0001
+	omega := float64(max(s.AngularFrequency, 0))
+	zeta := float64(max(s.DampingRatio, 0))
+	t := float64(dt)
+	if omega < epsilon {
+		// no spring force, keep moving at the same speed
+		return Coefficients{PosPos: 1, PosVel: dt, VelVel: 1}
+	}
+
+	if zeta > 1+epsilon {
+		// over-damped
+		za := -omega * zeta
+		zb := omega * math.Sqrt(zeta*zeta-1)
+		z1, z2 := za-zb, za+zb
+		e1, e2 := math.Exp(z1*t), math.Exp(z2*t)
+		invTwoZb := 1 / (2 * zb)
+		e1OverTwoZb := e1 * invTwoZb
+		e2OverTwoZb := e2 * invTwoZb
+		z1e1OverTwoZb := z1 * e1OverTwoZb
+		z2e2OverTwoZb := z2 * e2OverTwoZb
+		return Coefficients{
+			PosPos: float32(e1OverTwoZb*z2 - z2e2OverTwoZb + e2),
+			PosVel: float32(-e1OverTwoZb + e2OverTwoZb),
+			VelPos: float32((z1e1OverTwoZb - z2e2OverTwoZb + e2) * z2),
+			VelVel: float32(-z1e1OverTwoZb + z2e2OverTwoZb),
+		}
+	}
+
+	if zeta < 1-epsilon {
+		// under-damped
+		omegaZeta := omega * zeta
+		alpha := omega * math.Sqrt(1-zeta*zeta)
+		expTerm := math.Exp(-omegaZeta * t)
+		sin, cos := math.Sincos(alpha * t)
+		expSin := expTerm * sin
+		expCos := expTerm * cos
+		expOmegaZetaSinOverAlpha := expTerm * omegaZeta * sin / alpha
+		return Coefficients{
+			PosPos: float32(expCos + expOmegaZetaSinOverAlpha),
+			PosVel: float32(expSin / alpha),
+			VelPos: float32(-expSin*alpha - omegaZeta*expOmegaZetaSinOverAlpha),
+			VelVel: float32(expCos - expOmegaZetaSinOverAlpha),
+		}
+	}
+
+	// critically damped
+	expTerm := math.Exp(-omega * t)
+	timeExp := t * expTerm
+	timeExpFreq := timeExp * omega
+	return Coefficients{
+		PosPos: float32(timeExpFreq + expTerm),
+		PosVel: float32(timeExp),
+		VelPos: float32(-omega * timeExpFreq),
+		VelVel: float32(-timeExpFreq + expTerm),
+	}
+}
+
+func (c Coefficients) Update(pos, vel, target float32) (newPos, newVel float32) {
+	offset := pos - target
+	newPos = offset*c.PosPos + vel*c.PosVel + target
+	newVel = offset*c.VelPos + vel*c.VelVel
+	return
+}
+
+func (c Coefficients) Update2(pos, vel, target vec2.F) (newPos, newVel vec2.F) {
+	newPos.X, newVel.X = c.Update(pos.X, vel.X, target.X)
+	newPos.Y, newVel.Y = c.Update(pos.Y, vel.Y, target.Y)
+	return
+}
+
+func (c Coefficients) Update3(pos, vel, target vec3.F) (newPos, newVel vec3.F) {
+	newPos.X, newVel.X = c.Update(pos.X, vel.X, target.X)
+	newPos.Y, newVel.Y = c.Update(pos.Y, vel.Y, target.Y)
+	newPos.Z, newVel.Z = c.Update(pos.Z, vel.Z, target.Z)
+	return
+}
@@ -0,0 +1,137 @@
+package spring
+
+import (
+	"math"
+	"testing"
+
+	"github.com/Lundis/go-gmath/vec2"
+	"github.com/Lundis/go-gmath/vec3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSpringPhysical(t *testing.T) {
+	s := NewSpringPhysical(100, 4, 2)
+	assert.InDelta(t, math.Sqrt(50), s.AngularFrequency, 0.0001)
+	assert.InDelta(t, 4/(2*math.Sqrt(200)), s.DampingRatio, 0.0001)
+	assert.InDelta(t, 100, s.Stiffness(2), 0.001)
+	assert.InDelta(t, 4, s.Damping(2), 0.0001)
+
+	assert.InDelta(t, 2*math.Pi, NewSpring(1, 0.5).AngularFrequency, 0.0001)
+}
+
+func TestSpringTimestepIndependence(t *testing.T) {
+	for _, zeta := range []float32{0, 0.3, 1, 2.5} {
+		s := NewSpring(2, zeta)
+		pos, vel := s.Update(0, 3, 10, 0.5)
+
+		smallPos, smallVel := float32(0), float32(3)
+		c := s.Coefficients(0.005)
+		for i := 0; i < 100; i++ {
+			smallPos, smallVel = c.Update(smallPos, smallVel, 10)
+		}
+		assert.InDelta(t, pos, smallPos, 0.001, "damping ratio %v", zeta)
+		assert.InDelta(t, vel, smallVel, 0.01, "damping ratio %v", zeta)
+	}
+}
+
+func TestSpringCriticallyDamped(t *testing.T) {
+	// x(t) = (x0 + (v0 + w*x0)*t) * e^(-w*t) relative to the target
+	s := Spring{AngularFrequency: 3, DampingRatio: 1}
+	pos, _ := s.Update(2, 1, 0, 0.7)
+	expected := (2 + (1+3*2)*0.7) * math.Exp(-3*0.7)
+	assert.InDelta(t, expected, pos, 0.0001)
+}
+
+func TestSpringOvershoot(t *testing.T) {
+	maxPos := func(s Spring) float32 {
+		pos, vel := float32(0), float32(0)
+		highest := pos
+		for i := 0; i < 200; i++ {
+			pos, vel = s.Update(pos, vel, 1, 1.0/60)
+			highest = max(highest, pos)
+		}
+		return highest
+	}
+	assert.Greater(t, maxPos(NewSpring(1, 0.2)), float32(1.3))
+	assert.LessOrEqual(t, maxPos(NewSpring(1, 1)), float32(1))
+	assert.LessOrEqual(t, maxPos(NewSpring(1, 3)), float32(1))
+}
+
+func TestSpringStableWithLargeSteps(t *testing.T) {
+	s := NewSpring(5, 0.5)
+	pos, vel := s.Update(0, 0, 1, 100)
+	assert.InDelta(t, 1, pos, 0.0001)
+	assert.InDelta(t, 0, vel, 0.0001)
+
+	// no spring keeps the velocity
+	pos, vel = Spring{}.Update(0, 2, 100, 3)
+	assert.Equal(t, float32(6), pos)
+	assert.Equal(t, float32(2), vel)
+}
+
+func TestSpringVectors(t *testing.T) {
+	s := NewSpring(1.5, 0.4)
+	pos, vel := s.Update2(vec2.F{X: 1, Y: 2}, vec2.F{X: -1}, vec2.F{X: 5, Y: 5}, 0.1)
+	x, vx := s.Update(1, -1, 5, 0.1)
+	y, vy := s.Update(2, 0, 5, 0.1)
+	assert.Equal(t, vec2.F{X: x, Y: y}, pos)
+	assert.Equal(t, vec2.F{X: vx, Y: vy}, vel)
+
+	pos3, vel3 := s.Update3(vec3.F{X: 1, Y: 2, Z: 1}, vec3.F{X: -1, Z: -1}, vec3.F{X: 5, Y: 5, Z: 5}, 0.1)
+	assert.Equal(t, vec3.F{X: x, Y: y, Z: x}, pos3)
+	assert.Equal(t, vec3.F{X: vx, Y: vy, Z: vx}, vel3)
+}
+
+func TestSmoothDamp(t *testing.T) {
+	current, vel := float32(0), float32(0)
+	for i := 0; i < 60; i++ {
+		current, vel = SmoothDamp(current, 10, vel, 0.3, math.MaxFloat32, 1.0/60)
+		assert.LessOrEqual(t, current, float32(10))
+	}
+	assert.InDelta(t, 10, current, 0.2)
+
+	// one large step gives the same result as many small ones
+	big, bigVel := SmoothDamp(0, 10, 0, 0.3, math.MaxFloat32, 0.5)
+	small, smallVel := float32(0), float32(0)
+	for i := 0; i < 50; i++ {
+		small, smallVel = SmoothDamp(small, 10, smallVel, 0.3, math.MaxFloat32, 0.01)
+	}
+	assert.InDelta(t, big, small, 0.001)
+	assert.InDelta(t, bigVel, smallVel, 0.001)
+
+	// limited speed
+	current, vel = SmoothDamp(0, 100, 0, 0.1, 5, 1.0/60)
+	current, vel = SmoothDamp(current, 100, vel, 0.1, 5, 1.0/60)
+	assert.LessOrEqual(t, vel, float32(5))
+
+	// retargeting keeps the velocity
+	current, vel = SmoothDamp(0, 10, 0, 0.3, math.MaxFloat32, 0.1)
+	current, vel = SmoothDamp(current, -10, vel, 0.3, math.MaxFloat32, 0.01)
+	assert.Greater(t, vel, float32(0))
+
+	same, sameVel := SmoothDamp(3, 10, 1, 0.3, math.MaxFloat32, 0)
+	assert.Equal(t, float32(3), same)
+	assert.Equal(t, float32(1), sameVel)
+}
+
+func TestSmoothDampVectors(t *testing.T) {
+	target := vec2.F{X: 3, Y: 4}
+	pos, vel := SmoothDamp2(vec2.F{}, target, vec2.F{}, 0.3, math.MaxFloat32, 0.1)
+	scalar, scalarVel := SmoothDamp(0, 5, 0, 0.3, math.MaxFloat32, 0.1)
+	assert.InDelta(t, scalar, pos.Magnitude(), 0.0001)
+	assert.InDelta(t, scalarVel, vel.Magnitude(), 0.0001)
+
+	pos, vel = SmoothDamp2(vec2.F{}, target, vec2.F{}, 0.3, 2, 0.1)
+	assert.Less(t, pos.Magnitude(), scalar)
+
+	for i := 0; i < 100; i++ {
+		pos, vel = SmoothDamp2(pos, target, vel, 0.3, math.MaxFloat32, 0.05)
+	}
+	assert.InDelta(t, 0, pos.DistanceTo(target), 0.001)
+
+	target3 := vec3.F{X: 2, Y: 3, Z: 6}
+	pos3, vel3 := SmoothDamp3(vec3.F{}, target3, vec3.F{}, 0.3, math.MaxFloat32, 0.1)
+	scalar, scalarVel = SmoothDamp(0, 7, 0, 0.3, math.MaxFloat32, 0.1)
+	assert.InDelta(t, scalar, pos3.Magnitude(), 0.0001)
+	assert.InDelta(t, scalarVel, vel3.Magnitude(), 0.0001)
+}
@@ -0,0 +1,219 @@
+package vec2
+
+import (
+	"math"
+
+	"github.com/Lundis/go-gmath/fastmath"
+)
+
+// Affine2 is a 2D affine transform, stored as the top two rows of a row-major 3x3 matrix:
+//
+//	| M00 M01 M02 |
+//	| M10 M11 M12 |
+//	|  0   0   1  |
+//
+// Transforming point p gives (M00*p.X + M01*p.Y + M02, M10*p.X + M11*p.Y + M12).
+// Rotations follow the same convention as F.Rotate and RotationKernel.
+type Affine2 struct {
+	M00, M01, M02 float32
+	M10, M11, M12 float32
+}
+
+func NewAffine2Identity() Affine2 {
+	return Affine2{M00: 1, M11: 1}
+}
+
+func NewAffine2Translation(offset F) Affine2 {
+	return Affine2{M00: 1, M02: offset.X, M11: 1, M12: offset.Y}
+}
+
+func NewAffine2Scale(scale F) Affine2 {
+	return Affine2{M00: scale.X, M11: scale.Y}
+}
+
+func NewAffine2Rotation(angle float32) Affine2 {
+	return NewAffine2FromKernel(NewRotationKernel(angle))
+}
+
+func NewAffine2FromKernel(rk RotationKernel) Affine2 {
+	return Affine2{
+		M00: rk.cos, M01: -rk.sin,
+		M10: rk.sin, M11: rk.cos,
+	}
+}
+
+// NewAffine2Shear creates a transform that shifts X by shear.X*Y and Y by shear.Y*X
+func NewAffine2Shear(shear F) Affine2 {
+	return Affine2{M00: 1, M01: shear.X, M10: shear.Y, M11: 1}
+}
+
+// NewAffine2TRS creates a transform that scales, then rotates and finally translates
+func NewAffine2TRS(translation F, angle float32, scale F) Affine2 {
+	return NewAffine2TRSKernel(translation, NewRotationKernel(angle), scale)
+}
+
+// NewAffine2TRSKernel is like NewAffine2TRS, but uses a precalculated rotation
+func NewAffine2TRSKernel(translation F, rk RotationKernel, scale F) Affine2 {
+	return Affine2{
+		M00: rk.cos * scale.X, M01: -rk.sin * scale.Y, M02: translation.X,
+		M10: rk.sin * scale.X, M11: rk.cos * scale.Y, M12: translation.Y,
+	}
+}
+
+// Affine2 returns the rotation of the kernel as a transform
+func (rk RotationKernel) Affine2() Affine2 {
+	return NewAffine2FromKernel(rk)
+}
+
+func (a Affine2) AsDouble() Affine2D {
+	return Affine2D{
+		M00: float64(a.M00), M01: float64(a.M01), M02: float64(a.M02),
+		M10: float64(a.M10), M11: float64(a.M11), M12: float64(a.M12),
+	}
+}
+
+func (a Affine2) Equals(other Affine2) bool {
+	return a == other
+}
+
+func (a Affine2) IsIdentity() bool {
+	return a == NewAffine2Identity()
+}
+
+// Compose returns the transform that first applies other and then a, i.e. the matrix product a * other
+func (a Affine2) Compose(other Affine2) Affine2 {
+	return Affine2{
+		M00: a.M00*other.M00 + a.M01*other.M10,
+		M01: a.M00*other.M01 + a.M01*other.M11,
+		M02: a.M00*other.M02 + a.M01*other.M12 + a.M02,
+		M10: a.M10*other.M00 + a.M11*other.M10,
+		M11: a.M10*other.M01 + a.M11*other.M11,
+		M12: a.M10*other.M02 + a.M11*other.M12 + a.M12,
+	}
+}
+
+// Then returns the transform that first applies a and then other
+func (a Affine2) Then(other Affine2) Affine2 {
+	return other.Compose(a)
+}
+
+// Translate returns a followed by a translation
+func (a Affine2) Translate(offset F) Affine2 {
+	a.M02 += offset.X
+	a.M12 += offset.Y
+	return a
+}
+
+// Rotate returns a followed by a rotation around origo
+func (a Affine2) Rotate(angle float32) Affine2 {
+	return a.RotateKernel(NewRotationKernel(angle))
+}
+
+// RotateKernel returns a followed by a rotation around origo
+func (a Affine2) RotateKernel(rk RotationKernel) Affine2 {
+	return NewAffine2FromKernel(rk).Compose(a)
+}
+
+// Scale returns a followed by a scaling relative to origo
+func (a Affine2) Scale(scale F) Affine2 {
+	a.M00 *= scale.X
+	a.M01 *= scale.X
+	a.M02 *= scale.X
+	a.M10 *= scale.Y
+	a.M11 *= scale.Y
+	a.M12 *= scale.Y
+	return a
+}
+
+func (a Affine2) Determinant() float32 {
+	return a.M00*a.M11 - a.M01*a.M10
+}
+
+// Inverse returns the inverse transform. ok is false if a is not invertible.
+func (a Affine2) Inverse() (inverse Affine2, ok bool) {
+	det := a.Determinant()
+	if det == 0 {
+		return Affine2{}, false
+	}
+	invDet := 1 / det
+	inverse.M00 = a.M11 * invDet
+	inverse.M01 = -a.M01 * invDet
+	inverse.M10 = -a.M10 * invDet
+	inverse.M11 = a.M00 * invDet
+	inverse.M02 = -(inverse.M00*a.M02 + inverse.M01*a.M12)
+	inverse.M12 = -(inverse.M10*a.M02 + inverse.M11*a.M12)
+	return inverse, true
+}
+
+func (a Affine2) Translation() F {
+	return F{X: a.M02, Y: a.M12}
+}
+
+// Decompose splits a into translation, rotation and scale such that NewAffine2TRS returns a again.
+// Shear can't be represented and is lost. A reflection is represented as a negative Y scale.
+func (a Affine2) Decompose() (translation F, angle float32, scale F) {
+	translation = a.Translation()
+	scale.X = fastmath.Sqrt(a.M00*a.M00 + a.M10*a.M10)
+	if scale.X == 0 {
+		scale.Y = fastmath.Sqrt(a.M01*a.M01 + a.M11*a.M11)
+		return
+	}
+	scale.Y = a.Determinant() / scale.X
+	angle = float32(math.Atan2(float64(-a.M10), float64(a.M00)))
+	return
+}
+
+// TransformPoint applies the full transform to p
+func (a Affine2) TransformPoint(p F) F {
+	return F{
+		X: a.M00*p.X + a.M01*p.Y + a.M02,
+		Y: a.M10*p.X + a.M11*p.Y + a.M12,
+	}
+}
+
+// TransformVector applies the transform to v without the translation
+func (a Affine2) TransformVector(v F) F {
+	return F{
+		X: a.M00*v.X + a.M01*v.Y,
+		Y: a.M10*v.X + a.M11*v.Y,
+	}
+}
+
+// TransformPoints transforms every point in src and stores the result in dst, which must be at least as long as src.
+// dst and src may be the same slice. Returns dst[:len(src)].
+func (a Affine2) TransformPoints(dst, src []F) []F {
+	dst = dst[:len(src)]
+	for i, p := range src {
+		dst[i] = a.TransformPoint(p)
+	}
+	return dst
+}
+
+// TransformVectors is like TransformPoints, but ignores the translation
+func (a Affine2) TransformVectors(dst, src []F) []F {
+	dst = dst[:len(src)]
+	for i, v := range src {
+		dst[i] = a.TransformVector(v)
+	}
+	return dst
+}
+
+// TransformRect returns the bounding box of the transformed corners of r
+func (a Affine2) TransformRect(r Rect) Rect {
+	corners := r.Corners()
+	result := Rect{Min: a.TransformPoint(corners[0])}
+	result.Max = result.Min
+	for _, c := range corners[1:] {
+		result = result.ExpandToInclude(a.TransformPoint(c))
+	}
+	return result
+}
+
+// Mat3 returns the full 3x3 matrix in column-major order, as expected by most graphics APIs
+func (a Affine2) Mat3() [9]float32 {
+	return [9]float32{
+		a.M00, a.M10, 0,
+		a.M01, a.M11, 0,
+		a.M02, a.M12, 1,
+	}
+}
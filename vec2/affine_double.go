@@ -0,0 +1,195 @@
+package vec2
+
+import (
+	"math"
+
+	"github.com/Lundis/go-gmath/fastmath"
+)
+
+// Affine2D is the float64 version of Affine2
+type Affine2D struct {
+	M00, M01, M02 float64
+	M10, M11, M12 float64
+}
+
+func NewAffine2DIdentity() Affine2D {
+	return Affine2D{M00: 1, M11: 1}
+}
+
+func NewAffine2DTranslation(offset D) Affine2D {
+	return Affine2D{M00: 1, M02: offset.X, M11: 1, M12: offset.Y}
+}
+
+func NewAffine2DScale(scale D) Affine2D {
+	return Affine2D{M00: scale.X, M11: scale.Y}
+}
+
+func NewAffine2DRotation(angle float64) Affine2D {
+	cos, sin := fastmath.CosSinD(angle)
+	return Affine2D{
+		M00: cos, M01: -sin,
+		M10: sin, M11: cos,
+	}
+}
+
+// NewAffine2DShear creates a transform that shifts X by shear.X*Y and Y by shear.Y*X
+func NewAffine2DShear(shear D) Affine2D {
+	return Affine2D{M00: 1, M01: shear.X, M10: shear.Y, M11: 1}
+}
+
+// NewAffine2DTRS creates a transform that scales, then rotates and finally translates
+func NewAffine2DTRS(translation D, angle float64, scale D) Affine2D {
+	cos, sin := fastmath.CosSinD(angle)
+	return Affine2D{
+		M00: cos * scale.X, M01: -sin * scale.Y, M02: translation.X,
+		M10: sin * scale.X, M11: cos * scale.Y, M12: translation.Y,
+	}
+}
+
+func (a Affine2D) AsFloat() Affine2 {
+	return Affine2{
+		M00: float32(a.M00), M01: float32(a.M01), M02: float32(a.M02),
+		M10: float32(a.M10), M11: float32(a.M11), M12: float32(a.M12),
+	}
+}
+
+func (a Affine2D) Equals(other Affine2D) bool {
+	return a == other
+}
+
+func (a Affine2D) IsIdentity() bool {
+	return a == NewAffine2DIdentity()
+}
+
+// Compose returns the transform that first applies other and then a, i.e. the matrix product a * other
+func (a Affine2D) Compose(other Affine2D) Affine2D {
+	return Affine2D{
+		M00: a.M00*other.M00 + a.M01*other.M10,
+		M01: a.M00*other.M01 + a.M01*other.M11,
+		M02: a.M00*other.M02 + a.M01*other.M12 + a.M02,
+		M10: a.M10*other.M00 + a.M11*other.M10,
+		M11: a.M10*other.M01 + a.M11*other.M11,
+		M12: a.M10*other.M02 + a.M11*other.M12 + a.M12,
+	}
+}
+
+// Then returns the transform that first applies a and then other
+func (a Affine2D) Then(other Affine2D) Affine2D {
+	return other.Compose(a)
+}
+
+// Translate returns a followed by a translation
+func (a Affine2D) Translate(offset D) Affine2D {
+	a.M02 += offset.X
+	a.M12 += offset.Y
+	return a
+}
+
+// Rotate returns a followed by a rotation around origo
+func (a Affine2D) Rotate(angle float64) Affine2D {
+	return NewAffine2DRotation(angle).Compose(a)
+}
+
+// Scale returns a followed by a scaling relative to origo
+func (a Affine2D) Scale(scale D) Affine2D {
+	a.M00 *= scale.X
+	a.M01 *= scale.X
+	a.M02 *= scale.X
+	a.M10 *= scale.Y
+	a.M11 *= scale.Y
+	a.M12 *= scale.Y
+	return a
+}
+
+func (a Affine2D) Determinant() float64 {
+	return a.M00*a.M11 - a.M01*a.M10
+}
+
+// Inverse returns the inverse transform. ok is false if a is not invertible.
+func (a Affine2D) Inverse() (inverse Affine2D, ok bool) {
+	det := a.Determinant()
+	if det == 0 {
+		return Affine2D{}, false
+	}
+	invDet := 1 / det
+	inverse.M00 = a.M11 * invDet
+	inverse.M01 = -a.M01 * invDet
+	inverse.M10 = -a.M10 * invDet
+	inverse.M11 = a.M00 * invDet
+	inverse.M02 = -(inverse.M00*a.M02 + inverse.M01*a.M12)
+	inverse.M12 = -(inverse.M10*a.M02 + inverse.M11*a.M12)
+	return inverse, true
+}
+
+func (a Affine2D) Translation() D {
+	return D{X: a.M02, Y: a.M12}
+}
+
+// Decompose splits a into translation, rotation and scale such that NewAffine2DTRS returns a again.
+// Shear can't be represented and is lost. A reflection is represented as a negative Y scale.
+func (a Affine2D) Decompose() (translation D, angle float64, scale D) {
+	translation = a.Translation()
+	scale.X = math.Sqrt(a.M00*a.M00 + a.M10*a.M10)
+	if scale.X == 0 {
+		scale.Y = math.Sqrt(a.M01*a.M01 + a.M11*a.M11)
+		return
+	}
+	scale.Y = a.Determinant() / scale.X
+	angle = math.Atan2(-a.M10, a.M00)
+	return
+}
+
+// TransformPoint applies the full transform to p
+func (a Affine2D) TransformPoint(p D) D {
+	return D{
+		X: a.M00*p.X + a.M01*p.Y + a.M02,
+		Y: a.M10*p.X + a.M11*p.Y + a.M12,
+	}
+}
+
+// TransformVector applies the transform to v without the translation
+func (a Affine2D) TransformVector(v D) D {
+	return D{
+		X: a.M00*v.X + a.M01*v.Y,
+		Y: a.M10*v.X + a.M11*v.Y,
+	}
+}
+
+// TransformPoints transforms every point in src and stores the result in dst, which must be at least as long as src.
+// dst and src may be the same slice. Returns dst[:len(src)].
+func (a Affine2D) TransformPoints(dst, src []D) []D {
+	dst = dst[:len(src)]
+	for i, p := range src {
+		dst[i] = a.TransformPoint(p)
+	}
+	return dst
+}
+
+// TransformVectors is like TransformPoints, but ignores the translation
+func (a Affine2D) TransformVectors(dst, src []D) []D {
+	dst = dst[:len(src)]
+	for i, v := range src {
+		dst[i] = a.TransformVector(v)
+	}
+	return dst
+}
+
+// TransformRect returns the bounding box of the transformed corners of r
+func (a Affine2D) TransformRect(r RectD) RectD {
+	corners := r.Corners()
+	result := RectD{Min: a.TransformPoint(corners[0])}
+	result.Max = result.Min
+	for _, c := range corners[1:] {
+		result = result.ExpandToInclude(a.TransformPoint(c))
+	}
+	return result
+}
+
+// Mat3 returns the full 3x3 matrix in column-major order, as expected by most graphics APIs
+func (a Affine2D) Mat3() [9]float64 {
+	return [9]float64{
+		a.M00, a.M10, 0,
+		a.M01, a.M11, 0,
+		a.M02, a.M12, 1,
+	}
+}
@@ -0,0 +1,154 @@
+package vec2
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func assertAffineInDelta(t *testing.T, expected, actual Affine2) {
+	t.Helper()
+	assert.InDelta(t, expected.M00, actual.M00, 0.001)
+	assert.InDelta(t, expected.M01, actual.M01, 0.001)
+	assert.InDelta(t, expected.M02, actual.M02, 0.001)
+	assert.InDelta(t, expected.M10, actual.M10, 0.001)
+	assert.InDelta(t, expected.M11, actual.M11, 0.001)
+	assert.InDelta(t, expected.M12, actual.M12, 0.001)
+}
+
+func TestAffine2Identity(t *testing.T) {
+	a := NewAffine2Identity()
+	assert.True(t, a.IsIdentity())
+	assert.Equal(t, F{3, 4}, a.TransformPoint(F{3, 4}))
+	assert.Equal(t, float32(1), a.Determinant())
+}
+
+func TestAffine2Rotation(t *testing.T) {
+	angle := float32(1.2)
+	p := F{3, -2}
+	expected := p.Rotate(angle)
+
+	res := NewAffine2Rotation(angle).TransformPoint(p)
+	assert.InDelta(t, expected.X, res.X, 0.001)
+	assert.InDelta(t, expected.Y, res.Y, 0.001)
+
+	res = NewRotationKernel(angle).Affine2().TransformPoint(p)
+	assert.InDelta(t, expected.X, res.X, 0.001)
+	assert.InDelta(t, expected.Y, res.Y, 0.001)
+}
+
+func TestAffine2Compose(t *testing.T) {
+	scale := NewAffine2Scale(F{2, 3})
+	translate := NewAffine2Translation(F{10, 20})
+
+	// scale first, then translate
+	a := translate.Compose(scale)
+	assert.Equal(t, F{12, 23}, a.TransformPoint(F{1, 1}))
+	assert.Equal(t, a, scale.Then(translate))
+	assert.Equal(t, a, scale.Translate(F{10, 20}))
+
+	// translate first, then scale
+	b := scale.Compose(translate)
+	assert.Equal(t, F{22, 63}, b.TransformPoint(F{1, 1}))
+	assert.Equal(t, b, translate.Scale(F{2, 3}))
+	assert.Equal(t, F{2, 3}, b.TransformVector(F{1, 1}))
+}
+
+func TestAffine2TRS(t *testing.T) {
+	translation := F{5, -3}
+	angle := float32(0.7)
+	scale := F{2, 0.5}
+	a := NewAffine2TRS(translation, angle, scale)
+	expected := NewAffine2Scale(scale).Then(NewAffine2Rotation(angle)).Then(NewAffine2Translation(translation))
+	assertAffineInDelta(t, expected, a)
+	assertAffineInDelta(t, expected, NewAffine2Scale(scale).Rotate(angle).Translate(translation))
+
+	tr, rot, sc := a.Decompose()
+	assert.InDelta(t, translation.X, tr.X, 0.001)
+	assert.InDelta(t, translation.Y, tr.Y, 0.001)
+	assert.InDelta(t, angle, rot, 0.001)
+	assert.InDelta(t, scale.X, sc.X, 0.001)
+	assert.InDelta(t, scale.Y, sc.Y, 0.001)
+}
+
+func TestAffine2DecomposeReflection(t *testing.T) {
+	a := NewAffine2TRS(F{}, -2, F{3, -2})
+	_, rot, sc := a.Decompose()
+	assert.InDelta(t, -2, rot, 0.001)
+	assert.InDelta(t, 3, sc.X, 0.001)
+	assert.InDelta(t, -2, sc.Y, 0.001)
+}
+
+func TestAffine2Inverse(t *testing.T) {
+	a := NewAffine2TRS(F{5, -3}, 0.7, F{2, 0.5}).Then(NewAffine2Shear(F{0.3, 0}))
+	inv, ok := a.Inverse()
+	assert.True(t, ok)
+	assertAffineInDelta(t, NewAffine2Identity(), a.Compose(inv))
+	assertAffineInDelta(t, NewAffine2Identity(), inv.Compose(a))
+
+	p := F{7, 11}
+	back := inv.TransformPoint(a.TransformPoint(p))
+	assert.InDelta(t, p.X, back.X, 0.001)
+	assert.InDelta(t, p.Y, back.Y, 0.001)
+
+	_, ok = NewAffine2Scale(F{0, 1}).Inverse()
+	assert.False(t, ok)
+}
+
+func TestAffine2Determinant(t *testing.T) {
+	assert.Equal(t, float32(6), NewAffine2Scale(F{2, 3}).Determinant())
+	assert.InDelta(t, 1, NewAffine2Rotation(2).Determinant(), 0.001)
+	assert.Equal(t, float32(1), NewAffine2Shear(F{2, 0}).Determinant())
+}
+
+func TestAffine2TransformPoints(t *testing.T) {
+	a := NewAffine2Translation(F{1, 2})
+	points := []F{{0, 0}, {1, 1}, {2, 2}}
+	res := a.TransformPoints(points, points)
+	assert.Equal(t, []F{{1, 2}, {2, 3}, {3, 4}}, res)
+	assert.Equal(t, []F{{1, 2}, {2, 3}, {3, 4}}, points)
+
+	dst := make([]F, 3)
+	a.TransformVectors(dst, points)
+	assert.Equal(t, points, dst)
+
+	allocs := testing.AllocsPerRun(10, func() {
+		a.TransformPoints(dst, points)
+	})
+	assert.Equal(t, float64(0), allocs)
+}
+
+func TestAffine2TransformRect(t *testing.T) {
+	a := NewAffine2Rotation(math.Pi / 2)
+	r := a.TransformRect(NewRect(0, 0, 2, 1))
+	assert.InDelta(t, 1, r.Width(), 0.001)
+	assert.InDelta(t, 2, r.Height(), 0.001)
+}
+
+func TestAffine2Mat3(t *testing.T) {
+	a := Affine2{M00: 1, M01: 2, M02: 3, M10: 4, M11: 5, M12: 6}
+	assert.Equal(t, [9]float32{1, 4, 0, 2, 5, 0, 3, 6, 1}, a.Mat3())
+}
+
+func TestAffine2D(t *testing.T) {
+	a := NewAffine2DTRS(D{5, -3}, 0.7, D{2, 0.5})
+	assert.InDelta(t, 1, a.AsFloat().AsDouble().M00/a.M00, 0.0001)
+
+	tr, rot, sc := a.Decompose()
+	assert.InDelta(t, 5, tr.X, 0.0001)
+	assert.InDelta(t, 0.7, rot, 0.001)
+	assert.InDelta(t, 2, sc.X, 0.001)
+	assert.InDelta(t, 0.5, sc.Y, 0.001)
+
+	inv, ok := a.Inverse()
+	assert.True(t, ok)
+	p := inv.TransformPoint(a.TransformPoint(D{1, 2}))
+	assert.InDelta(t, 1, p.X, 0.0001)
+	assert.InDelta(t, 2, p.Y, 0.0001)
+
+	expected := D{3, -2}.Rotate(1.2)
+	res := NewAffine2DRotation(1.2).TransformPoint(D{3, -2})
+	assert.InDelta(t, expected.X, res.X, 0.0001)
+	assert.InDelta(t, expected.Y, res.Y, 0.0001)
+}
@@ -0,0 +1,21 @@
+package vec2
+
+type Circle struct {
+	Center F
+	Radius float32
+}
+
+func (c Circle) Bounds() Rect {
+	return NewRectCentered(c.Center, F{X: 2 * c.Radius, Y: 2 * c.Radius})
+}
+
+// ContainsPoint returns true if p is inside c or on its border
+func (c Circle) ContainsPoint(p F) bool {
+	return c.Center.DistanceToSquared(p) <= c.Radius*c.Radius
+}
+
+// Intersects returns true if c and other overlap. Circles that only touch do not intersect.
+func (c Circle) Intersects(other Circle) bool {
+	r := c.Radius + other.Radius
+	return c.Center.DistanceToSquared(other.Center) < r*r
+}
@@ -0,0 +1,202 @@
+package vec2
+
+// Manifold describes how two overlapping shapes A and B touch each other
+type Manifold struct {
+	// Normal is a unit vector pointing from A towards B
+	Normal F
+	// Depth is how far the shapes overlap along Normal. Moving B by Normal*Depth separates them.
+	Depth float32
+	// Contacts holds the contact points, of which the first Count are valid
+	Contacts [2]F
+	Count    int
+}
+
+// Flipped returns the manifold as seen from B, i.e. with the normal pointing from B towards A
+func (m Manifold) Flipped() Manifold {
+	m.Normal = m.Normal.MulScalar(-1)
+	return m
+}
+
+// CollideCircles returns the contact manifold of a and b. ok is false if they don't overlap.
+func CollideCircles(a, b Circle) (m Manifold, ok bool) {
+	d := b.Center.Sub(a.Center)
+	r := a.Radius + b.Radius
+	distSq := d.Dot(d)
+	if distSq >= r*r {
+		return Manifold{}, false
+	}
+	dist := d.Magnitude()
+	if dist == 0 {
+		// same center, any direction works
+		m.Normal = F{X: 1}
+	} else {
+		m.Normal = d.DivScalar(dist)
+	}
+	m.Depth = r - dist
+	m.Contacts[0] = a.Center.Add(m.Normal.MulScalar(a.Radius))
+	m.Count = 1
+	return m, true
+}
+
+// CollidePolygonCircle returns the contact manifold of a and b using the separating axis theorem.
+// ok is false if they don't overlap.
+func CollidePolygonCircle(a ConvexPolygon, b Circle) (m Manifold, ok bool) {
+	if len(a) < 3 {
+		return Manifold{}, false
+	}
+	sign := a.normalSign()
+	bestEdge := 0
+	bestSeparation := float32(-1e30)
+	for i := range a {
+		separation := a.edgeNormal(i, sign).Dot(b.Center.Sub(a[i]))
+		if separation >= b.Radius {
+			return Manifold{}, false
+		}
+		if separation > bestSeparation {
+			bestSeparation = separation
+			bestEdge = i
+		}
+	}
+
+	v1, v2 := Polygon(a).Edge(bestEdge)
+	if bestSeparation <= 0 {
+		// the center is inside the polygon, push it out through the closest edge
+		m.Normal = a.edgeNormal(bestEdge, sign)
+		m.Depth = b.Radius - bestSeparation
+		m.Contacts[0] = b.Center.Sub(m.Normal.MulScalar(bestSeparation))
+		m.Count = 1
+		return m, true
+	}
+
+	closest, _ := ClosestPointOnLineSegmentF(v1, v2, b.Center)
+	d := b.Center.Sub(closest)
+	distSq := d.Dot(d)
+	if distSq >= b.Radius*b.Radius {
+		return Manifold{}, false
+	}
+	dist := d.Magnitude()
+	m.Normal = d.DivScalar(dist)
+	m.Depth = b.Radius - dist
+	m.Contacts[0] = closest
+	m.Count = 1
+	return m, true
+}
+
+// CollideCirclePolygon is like CollidePolygonCircle with the shapes swapped
+func CollideCirclePolygon(a Circle, b ConvexPolygon) (m Manifold, ok bool) {
+	m, ok = CollidePolygonCircle(b, a)
+	return m.Flipped(), ok
+}
+
+// CollidePolygons returns the contact manifold of a and b using the separating axis theorem.
+// ok is false if they don't overlap. The contact points lie on the surface of the polygon that
+// contributes the incident edge, which gives stable results for resting contacts.
+func CollidePolygons(a, b ConvexPolygon) (m Manifold, ok bool) {
+	if len(a) < 3 || len(b) < 3 {
+		return Manifold{}, false
+	}
+	edgeA, separationA := maxSeparation(a, b)
+	if separationA >= 0 {
+		return Manifold{}, false
+	}
+	edgeB, separationB := maxSeparation(b, a)
+	if separationB >= 0 {
+		return Manifold{}, false
+	}
+
+	// prefer a as the reference polygon to avoid flip-flopping between nearly equal axes
+	const relativeTolerance = 0.98
+	const absoluteTolerance = 0.001
+	ref, inc, refEdge, separation, flip := a, b, edgeA, separationA, false
+	if separationB > relativeTolerance*separationA+absoluteTolerance {
+		ref, inc, refEdge, separation, flip = b, a, edgeB, separationB, true
+	}
+
+	refNormal := ref.EdgeNormal(refEdge)
+	incSign := inc.normalSign()
+	incEdge := 0
+	minDot := float32(1e30)
+	for i := range inc {
+		if d := inc.edgeNormal(i, incSign).Dot(refNormal); d < minDot {
+			minDot = d
+			incEdge = i
+		}
+	}
+
+	r1, r2 := Polygon(ref).Edge(refEdge)
+	i1, i2 := Polygon(inc).Edge(incEdge)
+	tangent := r2.Sub(r1).Normalized()
+
+	// clip the incident edge to the side planes of the reference edge
+	var clipped bool
+	i1, i2, clipped = clipSegment(i1, i2, tangent.MulScalar(-1), -tangent.Dot(r1))
+	if clipped {
+		i1, i2, clipped = clipSegment(i1, i2, tangent, tangent.Dot(r2))
+	}
+
+	if clipped {
+		for _, p := range [2]F{i1, i2} {
+			if refNormal.Dot(p.Sub(r1)) <= 0 {
+				m.Contacts[m.Count] = p
+				m.Count++
+			}
+		}
+	}
+	if m.Count == 0 {
+		// numerical edge case, fall back to the deepest point of the incident polygon
+		m.Contacts[0] = deepestPoint(inc, refNormal)
+		m.Count = 1
+	}
+
+	m.Depth = -separation
+	m.Normal = refNormal
+	if flip {
+		m.Normal = m.Normal.MulScalar(-1)
+	}
+	return m, true
+}
+
+// maxSeparation finds the edge of a along whose normal b is separated the most.
+// Negative separations mean that the polygons overlap along that axis.
+func maxSeparation(a, b ConvexPolygon) (edge int, separation float32) {
+	separation = -1e30
+	sign := a.normalSign()
+	for i := range a {
+		n := a.edgeNormal(i, sign)
+		minDist := float32(1e30)
+		for _, v := range b {
+			minDist = min(minDist, n.Dot(v.Sub(a[i])))
+		}
+		if minDist > separation {
+			separation = minDist
+			edge = i
+		}
+	}
+	return
+}
+
+func deepestPoint(p ConvexPolygon, normal F) F {
+	best := p[0]
+	bestDist := normal.Dot(best)
+	for _, v := range p[1:] {
+		if d := normal.Dot(v); d < bestDist {
+			best, bestDist = v, d
+		}
+	}
+	return best
+}
+
+// clipSegment keeps the part of a-b where normal.Dot(p) <= offset. ok is false if nothing remains.
+func clipSegment(a, b, normal F, offset float32) (ca, cb F, ok bool) {
+	da := normal.Dot(a) - offset
+	db := normal.Dot(b) - offset
+	if da > 0 && db > 0 {
+		return a, b, false
+	}
+	if da > 0 {
+		a = a.Add(b.Sub(a).MulScalar(da / (da - db)))
+	} else if db > 0 {
+		b = b.Add(a.Sub(b).MulScalar(db / (db - da)))
+	}
+	return a, b, true
+}
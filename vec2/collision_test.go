@@ -0,0 +1,115 @@
+package vec2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func box(x, y, w, h float32) ConvexPolygon {
+	c := NewRect(x, y, w, h).Corners()
+	return ConvexPolygon(c[:])
+}
+
+func TestCollideCircles(t *testing.T) {
+	m, ok := CollideCircles(Circle{F{0, 0}, 2}, Circle{F{3, 0}, 2})
+	assert.True(t, ok)
+	assert.Equal(t, F{1, 0}, m.Normal)
+	assert.Equal(t, float32(1), m.Depth)
+	assert.Equal(t, 1, m.Count)
+	assert.Equal(t, F{2, 0}, m.Contacts[0])
+
+	_, ok = CollideCircles(Circle{F{0, 0}, 1}, Circle{F{2, 0}, 1})
+	assert.False(t, ok)
+
+	m, ok = CollideCircles(Circle{F{1, 1}, 1}, Circle{F{1, 1}, 1})
+	assert.True(t, ok)
+	assert.Equal(t, float32(2), m.Depth)
+}
+
+func TestCollidePolygonCircle(t *testing.T) {
+	square := box(0, 0, 2, 2)
+
+	// touching the right edge
+	m, ok := CollidePolygonCircle(square, Circle{F{2.5, 1}, 1})
+	assert.True(t, ok)
+	assert.InDelta(t, 1, m.Normal.X, 0.0001)
+	assert.InDelta(t, 0, m.Normal.Y, 0.0001)
+	assert.InDelta(t, 0.5, m.Depth, 0.0001)
+	assert.Equal(t, F{2, 1}, m.Contacts[0])
+
+	// near a corner
+	m, ok = CollidePolygonCircle(square, Circle{F{2.5, 2.5}, 1})
+	assert.True(t, ok)
+	assert.InDelta(t, 0.7071, m.Normal.X, 0.001)
+	assert.InDelta(t, 0.7071, m.Normal.Y, 0.001)
+	assert.InDelta(t, 1-0.7071, m.Depth, 0.001)
+	_, ok = CollidePolygonCircle(square, Circle{F{2.8, 2.8}, 1})
+	assert.False(t, ok)
+
+	// center inside the polygon
+	m, ok = CollidePolygonCircle(square, Circle{F{1, 0.25}, 0.5})
+	assert.True(t, ok)
+	assert.Equal(t, F{0, -1}, m.Normal)
+	assert.InDelta(t, 0.75, m.Depth, 0.0001)
+
+	_, ok = CollidePolygonCircle(square, Circle{F{5, 1}, 1})
+	assert.False(t, ok)
+
+	m, ok = CollideCirclePolygon(Circle{F{2.5, 1}, 1}, square)
+	assert.True(t, ok)
+	assert.InDelta(t, -1, m.Normal.X, 0.0001)
+}
+
+func TestCollidePolygons(t *testing.T) {
+	a := box(0, 0, 4, 4)
+	b := box(3, 1, 4, 2)
+
+	m, ok := CollidePolygons(a, b)
+	assert.True(t, ok)
+	assert.Equal(t, F{1, 0}, m.Normal)
+	assert.InDelta(t, 1, m.Depth, 0.0001)
+	assert.Equal(t, 2, m.Count)
+	for _, c := range m.Contacts[:m.Count] {
+		assert.True(t, NewRect(3, 1, 1, 2).Contains(c), c.String())
+	}
+
+	m, ok = CollidePolygons(b, a)
+	assert.True(t, ok)
+	assert.Equal(t, F{-1, 0}, m.Normal)
+	assert.InDelta(t, 1, m.Depth, 0.0001)
+
+	_, ok = CollidePolygons(a, box(4, 0, 1, 1))
+	assert.False(t, ok)
+	_, ok = CollidePolygons(a, box(10, 10, 1, 1))
+	assert.False(t, ok)
+}
+
+func TestCollidePolygonsRotated(t *testing.T) {
+	a := box(0, 0, 4, 4)
+	// a diamond poking into the top of a
+	diamond := ConvexPolygon{{2, 0.5}, {3, -0.5}, {2, -1.5}, {1, -0.5}}
+	diamond.AsPolygon().Reverse()
+
+	m, ok := CollidePolygons(a, diamond)
+	assert.True(t, ok)
+	assert.InDelta(t, 0, m.Normal.X, 0.0001)
+	assert.InDelta(t, -1, m.Normal.Y, 0.0001)
+	assert.InDelta(t, 0.5, m.Depth, 0.0001)
+	assert.Equal(t, 1, m.Count)
+	assert.Equal(t, F{2, 0.5}, m.Contacts[0])
+
+	// separating axis only found on the diamond
+	_, ok = CollidePolygons(a, ConvexPolygon{{5, 4.5}, {6, 5.5}, {5, 6.5}, {4, 5.5}})
+	assert.False(t, ok)
+}
+
+func TestCollideNoAllocations(t *testing.T) {
+	a := box(0, 0, 4, 4)
+	b := box(3, 1, 4, 2)
+	allocs := testing.AllocsPerRun(10, func() {
+		CollidePolygons(a, b)
+		CollidePolygonCircle(a, Circle{F{5, 1}, 2})
+	})
+	assert.Equal(t, float64(0), allocs)
+}
@@ -0,0 +1,197 @@
+package vec2
+
+// RectD is an axis-aligned rectangle with float64 coordinates. Min is the top-left corner and Max the bottom-right corner.
+// A RectD where Max is not larger than Min on both axes is considered empty.
+type RectD struct {
+	Min, Max D
+}
+
+// NewRectD creates a rectangle from its top-left corner and its size
+func NewRectD(x, y, w, h float64) RectD {
+	return RectD{Min: D{X: x, Y: y}, Max: D{X: x + w, Y: y + h}}
+}
+
+// NewRectDFromPoints creates the smallest rectangle containing both a and b
+func NewRectDFromPoints(a, b D) RectD {
+	return RectD{Min: a.Min(b), Max: a.Max(b)}
+}
+
+// NewRectDCentered creates a rectangle of the given size centered at center
+func NewRectDCentered(center, size D) RectD {
+	half := size.MulScalar(0.5)
+	return RectD{Min: center.Sub(half), Max: center.Add(half)}
+}
+
+func (r RectD) AsFloat() Rect {
+	return Rect{Min: r.Min.AsFloat(), Max: r.Max.AsFloat()}
+}
+
+func (r RectD) AsInt() RectI {
+	return RectI{Min: r.Min.AsInt(), Max: r.Max.AsInt()}
+}
+
+func (r RectD) Equals(other RectD) bool {
+	return r.Min.Equals(other.Min) && r.Max.Equals(other.Max)
+}
+
+func (r RectD) String() string {
+	return "[" + r.Min.String() + " - " + r.Max.String() + "]"
+}
+
+func (r RectD) X() float64 {
+	return r.Min.X
+}
+
+func (r RectD) Y() float64 {
+	return r.Min.Y
+}
+
+func (r RectD) Width() float64 {
+	return r.Max.X - r.Min.X
+}
+
+func (r RectD) Height() float64 {
+	return r.Max.Y - r.Min.Y
+}
+
+func (r RectD) Size() D {
+	return r.Max.Sub(r.Min)
+}
+
+func (r RectD) Center() D {
+	return r.Min.Add(r.Max).MulScalar(0.5)
+}
+
+func (r RectD) Area() float64 {
+	return r.Width() * r.Height()
+}
+
+func (r RectD) IsEmpty() bool {
+	return r.Max.X <= r.Min.X || r.Max.Y <= r.Min.Y
+}
+
+// Canonical returns r with Min and Max swapped where needed so that Min <= Max on both axes
+func (r RectD) Canonical() RectD {
+	return NewRectDFromPoints(r.Min, r.Max)
+}
+
+// Contains returns true if p is inside r or on its border
+func (r RectD) Contains(p D) bool {
+	return p.IsBetweenInclusive(r.Min, r.Max)
+}
+
+// ContainsRect returns true if other lies completely within r
+func (r RectD) ContainsRect(other RectD) bool {
+	return r.Min.X <= other.Min.X && other.Max.X <= r.Max.X &&
+		r.Min.Y <= other.Min.Y && other.Max.Y <= r.Max.Y
+}
+
+// Intersects returns true if r and other overlap. Rectangles that only share an edge do not intersect.
+func (r RectD) Intersects(other RectD) bool {
+	return r.Min.X < other.Max.X && other.Min.X < r.Max.X &&
+		r.Min.Y < other.Max.Y && other.Min.Y < r.Max.Y
+}
+
+// Intersection returns the overlapping area of r and other. ok is false if they don't intersect.
+func (r RectD) Intersection(other RectD) (intersection RectD, ok bool) {
+	intersection = RectD{Min: r.Min.Max(other.Min), Max: r.Max.Min(other.Max)}
+	if intersection.IsEmpty() {
+		return RectD{}, false
+	}
+	return intersection, true
+}
+
+// Union returns the smallest rectangle containing both r and other
+func (r RectD) Union(other RectD) RectD {
+	return RectD{Min: r.Min.Min(other.Min), Max: r.Max.Max(other.Max)}
+}
+
+// Expand grows r by amount in every direction. Negative values shrink it.
+func (r RectD) Expand(amount float64) RectD {
+	return r.ExpandXY(amount, amount)
+}
+
+// ExpandXY grows r horizontally by x and vertically by y in both directions
+func (r RectD) ExpandXY(x, y float64) RectD {
+	r.Min = r.Min.SubScalars(x, y)
+	r.Max = r.Max.AddScalars(x, y)
+	return r
+}
+
+// ExpandToInclude returns the smallest rectangle containing both r and p
+func (r RectD) ExpandToInclude(p D) RectD {
+	return RectD{Min: r.Min.Min(p), Max: r.Max.Max(p)}
+}
+
+func (r RectD) Translate(offset D) RectD {
+	r.Min = r.Min.Add(offset)
+	r.Max = r.Max.Add(offset)
+	return r
+}
+
+// Corners returns the corners in the order top-left, top-right, bottom-right, bottom-left
+func (r RectD) Corners() [4]D {
+	return [4]D{
+		r.Min,
+		{X: r.Max.X, Y: r.Min.Y},
+		r.Max,
+		{X: r.Min.X, Y: r.Max.Y},
+	}
+}
+
+// ClampPoint returns the point inside r closest to p
+func (r RectD) ClampPoint(p D) D {
+	return p.Clamp(r.Min, r.Max)
+}
+
+// SplitX splits r at the vertical line x. x is clamped to the horizontal bounds of r.
+func (r RectD) SplitX(x float64) (left, right RectD) {
+	x = max(r.Min.X, min(r.Max.X, x))
+	left, right = r, r
+	left.Max.X = x
+	right.Min.X = x
+	return
+}
+
+// SplitY splits r at the horizontal line y. y is clamped to the vertical bounds of r.
+func (r RectD) SplitY(y float64) (top, bottom RectD) {
+	y = max(r.Min.Y, min(r.Max.Y, y))
+	top, bottom = r, r
+	top.Max.Y = y
+	bottom.Min.Y = y
+	return
+}
+
+// Quadrants splits r at its center, in the order top-left, top-right, bottom-right, bottom-left
+func (r RectD) Quadrants() [4]RectD {
+	c := r.Center()
+	return [4]RectD{
+		{Min: r.Min, Max: c},
+		{Min: D{X: c.X, Y: r.Min.Y}, Max: D{X: r.Max.X, Y: c.Y}},
+		{Min: c, Max: r.Max},
+		{Min: D{X: r.Min.X, Y: c.Y}, Max: D{X: c.X, Y: r.Max.Y}},
+	}
+}
+
+// Subdivide splits r into a grid of columns x rows cells and appends them to dst row by row.
+func (r RectD) Subdivide(columns, rows int, dst []RectD) []RectD {
+	if columns <= 0 || rows <= 0 {
+		return dst
+	}
+	cell := r.Size().DivScalars(float64(columns), float64(rows))
+	for y := 0; y < rows; y++ {
+		for x := 0; x < columns; x++ {
+			cellMin := r.Min.Add(cell.MulScalars(float64(x), float64(y)))
+			cellMax := r.Min.Add(cell.MulScalars(float64(x+1), float64(y+1)))
+			// avoid gaps from rounding errors at the outer edges
+			if x == columns-1 {
+				cellMax.X = r.Max.X
+			}
+			if y == rows-1 {
+				cellMax.Y = r.Max.Y
+			}
+			dst = append(dst, RectD{Min: cellMin, Max: cellMax})
+		}
+	}
+	return dst
+}
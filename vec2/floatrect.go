@@ -0,0 +1,197 @@
+package vec2
+
+// Rect is an axis-aligned rectangle. Min is the top-left corner and Max the bottom-right corner.
+// A Rect where Max is not larger than Min on both axes is considered empty.
+type Rect struct {
+	Min, Max F
+}
+
+// NewRect creates a rectangle from its top-left corner and its size
+func NewRect(x, y, w, h float32) Rect {
+	return Rect{Min: F{X: x, Y: y}, Max: F{X: x + w, Y: y + h}}
+}
+
+// NewRectFromPoints creates the smallest rectangle containing both a and b
+func NewRectFromPoints(a, b F) Rect {
+	return Rect{Min: a.Min(b), Max: a.Max(b)}
+}
+
+// NewRectCentered creates a rectangle of the given size centered at center
+func NewRectCentered(center, size F) Rect {
+	half := size.MulScalar(0.5)
+	return Rect{Min: center.Sub(half), Max: center.Add(half)}
+}
+
+func (r Rect) AsDouble() RectD {
+	return RectD{Min: r.Min.AsDouble(), Max: r.Max.AsDouble()}
+}
+
+func (r Rect) AsInt() RectI {
+	return RectI{Min: r.Min.AsInt(), Max: r.Max.AsInt()}
+}
+
+func (r Rect) Equals(other Rect) bool {
+	return r.Min.Equals(other.Min) && r.Max.Equals(other.Max)
+}
+
+func (r Rect) String() string {
+	return "[" + r.Min.String() + " - " + r.Max.String() + "]"
+}
+
+func (r Rect) X() float32 {
+	return r.Min.X
+}
+
+func (r Rect) Y() float32 {
+	return r.Min.Y
+}
+
+func (r Rect) Width() float32 {
+	return r.Max.X - r.Min.X
+}
+
+func (r Rect) Height() float32 {
+	return r.Max.Y - r.Min.Y
+}
+
+func (r Rect) Size() F {
+	return r.Max.Sub(r.Min)
+}
+
+func (r Rect) Center() F {
+	return r.Min.Add(r.Max).MulScalar(0.5)
+}
+
+func (r Rect) Area() float32 {
+	return r.Width() * r.Height()
+}
+
+func (r Rect) IsEmpty() bool {
+	return r.Max.X <= r.Min.X || r.Max.Y <= r.Min.Y
+}
+
+// Canonical returns r with Min and Max swapped where needed so that Min <= Max on both axes
+func (r Rect) Canonical() Rect {
+	return NewRectFromPoints(r.Min, r.Max)
+}
+
+// Contains returns true if p is inside r or on its border
+func (r Rect) Contains(p F) bool {
+	return p.IsBetweenInclusive(r.Min, r.Max)
+}
+
+// ContainsRect returns true if other lies completely within r
+func (r Rect) ContainsRect(other Rect) bool {
+	return r.Min.X <= other.Min.X && other.Max.X <= r.Max.X &&
+		r.Min.Y <= other.Min.Y && other.Max.Y <= r.Max.Y
+}
+
+// Intersects returns true if r and other overlap. Rectangles that only share an edge do not intersect.
+func (r Rect) Intersects(other Rect) bool {
+	return r.Min.X < other.Max.X && other.Min.X < r.Max.X &&
+		r.Min.Y < other.Max.Y && other.Min.Y < r.Max.Y
+}
+
+// Intersection returns the overlapping area of r and other. ok is false if they don't intersect.
+func (r Rect) Intersection(other Rect) (intersection Rect, ok bool) {
+	intersection = Rect{Min: r.Min.Max(other.Min), Max: r.Max.Min(other.Max)}
+	if intersection.IsEmpty() {
+		return Rect{}, false
+	}
+	return intersection, true
+}
+
+// Union returns the smallest rectangle containing both r and other
+func (r Rect) Union(other Rect) Rect {
+	return Rect{Min: r.Min.Min(other.Min), Max: r.Max.Max(other.Max)}
+}
+
+// Expand grows r by amount in every direction. Negative values shrink it.
+func (r Rect) Expand(amount float32) Rect {
+	return r.ExpandXY(amount, amount)
+}
+
+// ExpandXY grows r horizontally by x and vertically by y in both directions
+func (r Rect) ExpandXY(x, y float32) Rect {
+	r.Min = r.Min.SubScalars(x, y)
+	r.Max = r.Max.AddScalars(x, y)
+	return r
+}
+
+// ExpandToInclude returns the smallest rectangle containing both r and p
+func (r Rect) ExpandToInclude(p F) Rect {
+	return Rect{Min: r.Min.Min(p), Max: r.Max.Max(p)}
+}
+
+func (r Rect) Translate(offset F) Rect {
+	r.Min = r.Min.Add(offset)
+	r.Max = r.Max.Add(offset)
+	return r
+}
+
+// Corners returns the corners in the order top-left, top-right, bottom-right, bottom-left
+func (r Rect) Corners() [4]F {
+	return [4]F{
+		r.Min,
+		{X: r.Max.X, Y: r.Min.Y},
+		r.Max,
+		{X: r.Min.X, Y: r.Max.Y},
+	}
+}
+
+// ClampPoint returns the point inside r closest to p
+func (r Rect) ClampPoint(p F) F {
+	return p.Clamp(r.Min, r.Max)
+}
+
+// SplitX splits r at the vertical line x. x is clamped to the horizontal bounds of r.
+func (r Rect) SplitX(x float32) (left, right Rect) {
+	x = max(r.Min.X, min(r.Max.X, x))
+	left, right = r, r
+	left.Max.X = x
+	right.Min.X = x
+	return
+}
+
+// SplitY splits r at the horizontal line y. y is clamped to the vertical bounds of r.
+func (r Rect) SplitY(y float32) (top, bottom Rect) {
+	y = max(r.Min.Y, min(r.Max.Y, y))
+	top, bottom = r, r
+	top.Max.Y = y
+	bottom.Min.Y = y
+	return
+}
+
+// Quadrants splits r at its center, in the order top-left, top-right, bottom-right, bottom-left
+func (r Rect) Quadrants() [4]Rect {
+	c := r.Center()
+	return [4]Rect{
+		{Min: r.Min, Max: c},
+		{Min: F{X: c.X, Y: r.Min.Y}, Max: F{X: r.Max.X, Y: c.Y}},
+		{Min: c, Max: r.Max},
+		{Min: F{X: r.Min.X, Y: c.Y}, Max: F{X: c.X, Y: r.Max.Y}},
+	}
+}
+
+// Subdivide splits r into a grid of columns x rows cells and appends them to dst row by row.
+func (r Rect) Subdivide(columns, rows int, dst []Rect) []Rect {
+	if columns <= 0 || rows <= 0 {
+		return dst
+	}
+	cell := r.Size().DivScalars(float32(columns), float32(rows))
+	for y := 0; y < rows; y++ {
+		for x := 0; x < columns; x++ {
+			cellMin := r.Min.Add(cell.MulScalars(float32(x), float32(y)))
+			cellMax := r.Min.Add(cell.MulScalars(float32(x+1), float32(y+1)))
+			// avoid gaps from rounding errors at the outer edges
+			if x == columns-1 {
+				cellMax.X = r.Max.X
+			}
+			if y == rows-1 {
+				cellMax.Y = r.Max.Y
+			}
+			dst = append(dst, Rect{Min: cellMin, Max: cellMax})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,196 @@
+package vec2
+
+// RectI is an axis-aligned rectangle with int32 coordinates. Min is the top-left corner and Max the bottom-right corner.
+// A RectI where Max is not larger than Min on both axes is considered empty.
+type RectI struct {
+	Min, Max I
+}
+
+// NewRectI creates a rectangle from its top-left corner and its size
+func NewRectI(x, y, w, h int32) RectI {
+	return RectI{Min: I{X: x, Y: y}, Max: I{X: x + w, Y: y + h}}
+}
+
+// NewRectIFromPoints creates the smallest rectangle containing both a and b
+func NewRectIFromPoints(a, b I) RectI {
+	return RectI{Min: a.Min(b), Max: a.Max(b)}
+}
+
+// NewRectICentered creates a rectangle of the given size centered at center.
+// Odd sizes put the extra unit on the bottom-right side.
+func NewRectICentered(center, size I) RectI {
+	minPoint := center.Sub(I{X: size.X / 2, Y: size.Y / 2})
+	return RectI{Min: minPoint, Max: minPoint.Add(size)}
+}
+
+func (r RectI) AsFloat() Rect {
+	return Rect{Min: r.Min.AsFloat(), Max: r.Max.AsFloat()}
+}
+
+func (r RectI) AsDouble() RectD {
+	return RectD{Min: r.Min.AsDouble(), Max: r.Max.AsDouble()}
+}
+
+func (r RectI) Equals(other RectI) bool {
+	return r.Min.Equals(other.Min) && r.Max.Equals(other.Max)
+}
+
+func (r RectI) String() string {
+	return "[" + r.Min.String() + " - " + r.Max.String() + "]"
+}
+
+func (r RectI) X() int32 {
+	return r.Min.X
+}
+
+func (r RectI) Y() int32 {
+	return r.Min.Y
+}
+
+func (r RectI) Width() int32 {
+	return r.Max.X - r.Min.X
+}
+
+func (r RectI) Height() int32 {
+	return r.Max.Y - r.Min.Y
+}
+
+func (r RectI) Size() I {
+	return r.Max.Sub(r.Min)
+}
+
+// Center returns the center of r, rounded towards Min
+func (r RectI) Center() I {
+	return r.Min.Add(I{X: r.Width() / 2, Y: r.Height() / 2})
+}
+
+func (r RectI) Area() int32 {
+	return r.Size().Area()
+}
+
+func (r RectI) IsEmpty() bool {
+	return r.Max.X <= r.Min.X || r.Max.Y <= r.Min.Y
+}
+
+// Canonical returns r with Min and Max swapped where needed so that Min <= Max on both axes
+func (r RectI) Canonical() RectI {
+	return NewRectIFromPoints(r.Min, r.Max)
+}
+
+// Contains returns true if p is inside r or on its border
+func (r RectI) Contains(p I) bool {
+	return p.IsBetweenInclusive(r.Min, r.Max)
+}
+
+// ContainsRect returns true if other lies completely within r
+func (r RectI) ContainsRect(other RectI) bool {
+	return r.Min.X <= other.Min.X && other.Max.X <= r.Max.X &&
+		r.Min.Y <= other.Min.Y && other.Max.Y <= r.Max.Y
+}
+
+// Intersects returns true if r and other overlap. Rectangles that only share an edge do not intersect.
+func (r RectI) Intersects(other RectI) bool {
+	return r.Min.X < other.Max.X && other.Min.X < r.Max.X &&
+		r.Min.Y < other.Max.Y && other.Min.Y < r.Max.Y
+}
+
+// Intersection returns the overlapping area of r and other. ok is false if they don't intersect.
+func (r RectI) Intersection(other RectI) (intersection RectI, ok bool) {
+	intersection = RectI{Min: r.Min.Max(other.Min), Max: r.Max.Min(other.Max)}
+	if intersection.IsEmpty() {
+		return RectI{}, false
+	}
+	return intersection, true
+}
+
+// Union returns the smallest rectangle containing both r and other
+func (r RectI) Union(other RectI) RectI {
+	return RectI{Min: r.Min.Min(other.Min), Max: r.Max.Max(other.Max)}
+}
+
+// Expand grows r by amount in every direction. Negative values shrink it.
+func (r RectI) Expand(amount int32) RectI {
+	return r.ExpandXY(amount, amount)
+}
+
+// ExpandXY grows r horizontally by x and vertically by y in both directions
+func (r RectI) ExpandXY(x, y int32) RectI {
+	r.Min = r.Min.AddScalars(-x, -y)
+	r.Max = r.Max.AddScalars(x, y)
+	return r
+}
+
+// ExpandToInclude returns the smallest rectangle containing both r and p
+func (r RectI) ExpandToInclude(p I) RectI {
+	return RectI{Min: r.Min.Min(p), Max: r.Max.Max(p)}
+}
+
+func (r RectI) Translate(offset I) RectI {
+	r.Min = r.Min.Add(offset)
+	r.Max = r.Max.Add(offset)
+	return r
+}
+
+// Corners returns the corners in the order top-left, top-right, bottom-right, bottom-left
+func (r RectI) Corners() [4]I {
+	return [4]I{
+		r.Min,
+		{X: r.Max.X, Y: r.Min.Y},
+		r.Max,
+		{X: r.Min.X, Y: r.Max.Y},
+	}
+}
+
+// ClampPoint returns the point inside r closest to p
+func (r RectI) ClampPoint(p I) I {
+	return p.Clamp(r.Min, r.Max)
+}
+
+// SplitX splits r at the vertical line x. x is clamped to the horizontal bounds of r.
+func (r RectI) SplitX(x int32) (left, right RectI) {
+	x = max(r.Min.X, min(r.Max.X, x))
+	left, right = r, r
+	left.Max.X = x
+	right.Min.X = x
+	return
+}
+
+// SplitY splits r at the horizontal line y. y is clamped to the vertical bounds of r.
+func (r RectI) SplitY(y int32) (top, bottom RectI) {
+	y = max(r.Min.Y, min(r.Max.Y, y))
+	top, bottom = r, r
+	top.Max.Y = y
+	bottom.Min.Y = y
+	return
+}
+
+// Quadrants splits r at its center, in the order top-left, top-right, bottom-right, bottom-left
+func (r RectI) Quadrants() [4]RectI {
+	c := r.Center()
+	return [4]RectI{
+		{Min: r.Min, Max: c},
+		{Min: I{X: c.X, Y: r.Min.Y}, Max: I{X: r.Max.X, Y: c.Y}},
+		{Min: c, Max: r.Max},
+		{Min: I{X: r.Min.X, Y: c.Y}, Max: I{X: c.X, Y: r.Max.Y}},
+	}
+}
+
+// Subdivide splits r into a grid of columns x rows cells and appends them to dst row by row.
+// When the size isn't evenly divisible, the remainder is distributed so that all cells differ by at most one unit.
+func (r RectI) Subdivide(columns, rows int, dst []RectI) []RectI {
+	if columns <= 0 || rows <= 0 {
+		return dst
+	}
+	w, h := int64(r.Width()), int64(r.Height())
+	cols, rws := int64(columns), int64(rows)
+	for y := int64(0); y < rws; y++ {
+		minY := r.Min.Y + int32(h*y/rws)
+		maxY := r.Min.Y + int32(h*(y+1)/rws)
+		for x := int64(0); x < cols; x++ {
+			minX := r.Min.X + int32(w*x/cols)
+			maxX := r.Min.X + int32(w*(x+1)/cols)
+			dst = append(dst, RectI{Min: I{X: minX, Y: minY}, Max: I{X: maxX, Y: maxY}})
+		}
+	}
+	return dst
+}
@@ -6,26 +6,20 @@ import "encoding/json"
  * Custom JSON marshal/unmarshal to represent vec2 as [x, y] instead of {"X": x, "Y": y}
  */
 
-func (v F) MarshalJSON() ([]byte, error) {
-	return json.Marshal([2]float32{v.X, v.Y})
-}
-func (v I) MarshalJSON() ([]byte, error) {
-	return json.Marshal([2]int32{v.X, v.Y})
-}
-func (v D) MarshalJSON() ([]byte, error) {
-	return json.Marshal([2]float64{v.X, v.Y})
+func (v Vec[T, R]) MarshalJSON() ([]byte, error) {
+	return json.Marshal([2]T{v.X, v.Y})
 }
 
-func (v *F) UnmarshalJSON(data []byte) error {
-	var tmp [2]float32
+func (v *Vec[T, R]) UnmarshalJSON(data []byte) error {
+	var tmp [2]T
 	if err := json.Unmarshal(data, &tmp); err != nil {
-		type Alias F
+		type Alias Vec[T, R]
 		var alias Alias
 		// fallback to default unmarshalling
 		if err := json.Unmarshal(data, &alias); err != nil {
 			return err
 		}
-		*v = F(alias)
+		*v = Vec[T, R](alias)
 		return nil
 	}
 	v.X = tmp[0]
@@ -33,36 +27,64 @@ func (v *F) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-func (i *I) UnmarshalJSON(data []byte) error {
-	var tmp [2]int32
+/**
+ * Rectangles are represented as [x, y, w, h] instead of {"Min": ..., "Max": ...}
+ */
+
+func (r Rect) MarshalJSON() ([]byte, error) {
+	return json.Marshal([4]float32{r.Min.X, r.Min.Y, r.Width(), r.Height()})
+}
+func (r RectI) MarshalJSON() ([]byte, error) {
+	return json.Marshal([4]int32{r.Min.X, r.Min.Y, r.Width(), r.Height()})
+}
+func (r RectD) MarshalJSON() ([]byte, error) {
+	return json.Marshal([4]float64{r.Min.X, r.Min.Y, r.Width(), r.Height()})
+}
+
+func (r *Rect) UnmarshalJSON(data []byte) error {
+	var tmp [4]float32
 	if err := json.Unmarshal(data, &tmp); err != nil {
-		type Alias I
+		type Alias Rect
 		var alias Alias
 		// fallback to default unmarshalling
 		if err := json.Unmarshal(data, &alias); err != nil {
 			return err
 		}
-		*i = I(alias)
+		*r = Rect(alias)
 		return nil
 	}
-	i.X = tmp[0]
-	i.Y = tmp[1]
+	*r = NewRect(tmp[0], tmp[1], tmp[2], tmp[3])
 	return nil
 }
 
-func (v *D) UnmarshalJSON(data []byte) error {
-	var tmp [2]float64
+func (r *RectI) UnmarshalJSON(data []byte) error {
+	var tmp [4]int32
 	if err := json.Unmarshal(data, &tmp); err != nil {
-		type Alias D
+		type Alias RectI
 		var alias Alias
 		// fallback to default unmarshalling
 		if err := json.Unmarshal(data, &alias); err != nil {
 			return err
 		}
-		*v = D(alias)
+		*r = RectI(alias)
 		return nil
 	}
-	v.X = tmp[0]
-	v.Y = tmp[1]
+	*r = NewRectI(tmp[0], tmp[1], tmp[2], tmp[3])
+	return nil
+}
+
+func (r *RectD) UnmarshalJSON(data []byte) error {
+	var tmp [4]float64
+	if err := json.Unmarshal(data, &tmp); err != nil {
+		type Alias RectD
+		var alias Alias
+		// fallback to default unmarshalling
+		if err := json.Unmarshal(data, &alias); err != nil {
+			return err
+		}
+		*r = RectD(alias)
+		return nil
+	}
+	*r = NewRectD(tmp[0], tmp[1], tmp[2], tmp[3])
 	return nil
 }
@@ -374,3 +374,35 @@ func TestMarshalSlice(t *testing.T) {
 	assert.Len(t, result, 3)
 	assert.Equal(t, floatVecs, result)
 }
+
+func TestRectMarshalJSON(t *testing.T) {
+	result, err := json.Marshal(NewRect(1.5, 2, 3, 4.5))
+	assert.NoError(t, err)
+	assert.JSONEq(t, "[1.5,2,3,4.5]", string(result))
+
+	result, err = json.Marshal(NewRectI(-1, 2, 3, 4))
+	assert.NoError(t, err)
+	assert.JSONEq(t, "[-1,2,3,4]", string(result))
+
+	result, err = json.Marshal(NewRectD(1.25, 2, 3, 4))
+	assert.NoError(t, err)
+	assert.JSONEq(t, "[1.25,2,3,4]", string(result))
+}
+
+func TestRectUnmarshalJSON(t *testing.T) {
+	var r Rect
+	assert.NoError(t, json.Unmarshal([]byte("[1.5,2,3,4.5]"), &r))
+	assert.Equal(t, NewRect(1.5, 2, 3, 4.5), r)
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"Min":[1,2],"Max":[3,4]}`), &r))
+	assert.Equal(t, Rect{Min: F{1, 2}, Max: F{3, 4}}, r)
+
+	var ri RectI
+	assert.NoError(t, json.Unmarshal([]byte("[1,2,3,4]"), &ri))
+	assert.Equal(t, NewRectI(1, 2, 3, 4), ri)
+	assert.Error(t, json.Unmarshal([]byte(`"foo"`), &ri))
+
+	var rd RectD
+	assert.NoError(t, json.Unmarshal([]byte("[1.25,2,3,4]"), &rd))
+	assert.Equal(t, NewRectD(1.25, 2, 3, 4), rd)
+}
@@ -0,0 +1,232 @@
+package vec2
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// Polygon is a simple polygon that may be concave. The last point connects back to the first one.
+type Polygon []F
+
+// Winding is the order in which the points of a polygon are listed, as seen on screen where Y points down.
+type Winding int
+
+const (
+	Degenerate Winding = iota
+	Clockwise
+	CounterClockwise
+)
+
+// SignedArea returns the area of p, which is positive when the points are listed clockwise on screen
+func (p Polygon) SignedArea() float32 {
+	if len(p) < 3 {
+		return 0
+	}
+	var sum float32
+	prev := p[len(p)-1]
+	for _, v := range p {
+		sum += prev.Cross(v)
+		prev = v
+	}
+	return sum / 2
+}
+
+func (p Polygon) Area() float32 {
+	return float32(math.Abs(float64(p.SignedArea())))
+}
+
+func (p Polygon) Winding() Winding {
+	area := p.SignedArea()
+	if area > 0 {
+		return Clockwise
+	} else if area < 0 {
+		return CounterClockwise
+	}
+	return Degenerate
+}
+
+// Reverse reverses the order of the points in place, which flips the winding
+func (p Polygon) Reverse() {
+	slices.Reverse(p)
+}
+
+// Centroid returns the center of mass of p. Polygons without area return the average of their points.
+func (p Polygon) Centroid() F {
+	if len(p) == 0 {
+		return F{}
+	}
+	var area float32
+	var c F
+	// relative to the first point for better precision far away from origo
+	origin := p[0]
+	prev := p[len(p)-1].Sub(origin)
+	for _, v := range p {
+		v = v.Sub(origin)
+		cross := prev.Cross(v)
+		area += cross
+		c = c.Add(prev.Add(v).MulScalar(cross))
+		prev = v
+	}
+	if area == 0 {
+		var sum F
+		for _, v := range p {
+			sum = sum.Add(v)
+		}
+		return sum.DivScalar(float32(len(p)))
+	}
+	return c.DivScalar(3 * area).Add(origin)
+}
+
+// Edge returns the edge from point i to the next point
+func (p Polygon) Edge(i int) (a, b F) {
+	a = p[i]
+	if i+1 < len(p) {
+		b = p[i+1]
+	} else {
+		b = p[0]
+	}
+	return
+}
+
+func (p Polygon) Bounds() Rect {
+	if len(p) == 0 {
+		return Rect{}
+	}
+	r := Rect{Min: p[0], Max: p[0]}
+	for _, v := range p[1:] {
+		r = r.ExpandToInclude(v)
+	}
+	return r
+}
+
+// ContainsPoint returns true if pt is inside p. Points exactly on an edge may go either way.
+// Polygons with less than three points contain nothing.
+func (p Polygon) ContainsPoint(pt F) bool {
+	if len(p) < 3 {
+		return false
+	}
+	inside := false
+	prev := p[len(p)-1]
+	for _, v := range p {
+		if (v.Y > pt.Y) != (prev.Y > pt.Y) &&
+			pt.X < (prev.X-v.X)*(pt.Y-v.Y)/(prev.Y-v.Y)+v.X {
+			inside = !inside
+		}
+		prev = v
+	}
+	return inside
+}
+
+// IsConvex returns true if all corners of p turn in the same direction and the edges go around exactly once.
+// Collinear points are allowed.
+func (p Polygon) IsConvex() bool {
+	if len(p) < 3 {
+		return false
+	}
+	var sign float32
+	var totalTurn float64
+	for i := range p {
+		a, b := p.Edge(i)
+		_, c := p.Edge((i + 1) % len(p))
+		ab := b.Sub(a)
+		bc := c.Sub(b)
+		cross := ab.Cross(bc)
+		if cross != 0 {
+			if sign == 0 {
+				sign = cross
+			} else if (sign > 0) != (cross > 0) {
+				return false
+			}
+		}
+		totalTurn += math.Atan2(float64(cross), float64(ab.Dot(bc)))
+	}
+	// a self-intersecting star turns around more than once
+	return sign != 0 && math.Abs(math.Abs(totalTurn)-2*math.Pi) < 0.01
+}
+
+// Convex returns p as a ConvexPolygon, or false if it isn't convex
+func (p Polygon) Convex() (ConvexPolygon, bool) {
+	if !p.IsConvex() {
+		return nil, false
+	}
+	return ConvexPolygon(p), true
+}
+
+// ConvexPolygon is a Polygon that is known to be convex, which enables faster algorithms and collision detection.
+// Create it with Polygon.Convex or ConvexHull.
+type ConvexPolygon []F
+
+func (c ConvexPolygon) AsPolygon() Polygon {
+	return Polygon(c)
+}
+
+// ContainsPoint returns true if pt is inside c or on its border. Polygons with less than three points contain nothing.
+func (c ConvexPolygon) ContainsPoint(pt F) bool {
+	if len(c) < 3 {
+		return false
+	}
+	flip := c.normalSign()
+	for i := range c {
+		a, b := Polygon(c).Edge(i)
+		if b.Sub(a).Cross(pt.Sub(a))*flip < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// normalSign returns 1 if F.Perpendicular() of the edges points outwards, or -1 if it points inwards
+func (c ConvexPolygon) normalSign() float32 {
+	if Polygon(c).SignedArea() < 0 {
+		return -1
+	}
+	return 1
+}
+
+// EdgeNormal returns the normalized outward-facing normal of edge i
+func (c ConvexPolygon) EdgeNormal(i int) F {
+	return c.edgeNormal(i, c.normalSign())
+}
+
+func (c ConvexPolygon) edgeNormal(i int, sign float32) F {
+	a, b := Polygon(c).Edge(i)
+	return b.Sub(a).Perpendicular().Normalized().MulScalar(sign)
+}
+
+// ConvexHull calculates the convex hull of points and appends it to dst in clockwise order.
+// points is sorted in place.
+func ConvexHull(points []F, dst ConvexPolygon) ConvexPolygon {
+	if len(points) < 3 {
+		return append(dst, points...)
+	}
+	slices.SortFunc(points, func(a, b F) int {
+		if c := cmp.Compare(a.X, b.X); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Y, b.Y)
+	})
+	// Andrew's monotone chain, building the lower and then the upper hull
+	start := len(dst)
+	for _, pt := range points {
+		for len(dst)-start >= 2 && turn(dst[len(dst)-2], dst[len(dst)-1], pt) <= 0 {
+			dst = dst[:len(dst)-1]
+		}
+		dst = append(dst, pt)
+	}
+	lower := len(dst) + 1
+	for i := len(points) - 2; i >= 0; i-- {
+		pt := points[i]
+		for len(dst) >= lower && turn(dst[len(dst)-2], dst[len(dst)-1], pt) <= 0 {
+			dst = dst[:len(dst)-1]
+		}
+		dst = append(dst, pt)
+	}
+	// the last point is the same as the first
+	return dst[:len(dst)-1]
+}
+
+// turn is positive if o->a->b turns clockwise on screen
+func turn(o, a, b F) float32 {
+	return a.Sub(o).Cross(b.Sub(o))
+}
@@ -0,0 +1,111 @@
+package vec2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// square with the points listed clockwise on screen
+var testSquare = Polygon{{0, 0}, {2, 0}, {2, 2}, {0, 2}}
+
+// an L shape
+var testConcave = Polygon{{0, 0}, {4, 0}, {4, 1}, {1, 1}, {1, 4}, {0, 4}}
+
+func TestPolygonArea(t *testing.T) {
+	assert.Equal(t, float32(4), testSquare.SignedArea())
+	assert.Equal(t, float32(7), testConcave.Area())
+	assert.Equal(t, float32(0), Polygon{{0, 0}, {1, 1}}.Area())
+
+	reversed := Polygon{{0, 2}, {2, 2}, {2, 0}, {0, 0}}
+	assert.Equal(t, float32(-4), reversed.SignedArea())
+	assert.Equal(t, float32(4), reversed.Area())
+}
+
+func TestPolygonWinding(t *testing.T) {
+	assert.Equal(t, Clockwise, testSquare.Winding())
+	p := append(Polygon{}, testSquare...)
+	p.Reverse()
+	assert.Equal(t, CounterClockwise, p.Winding())
+	assert.Equal(t, Degenerate, Polygon{{0, 0}, {1, 1}, {2, 2}}.Winding())
+}
+
+func TestPolygonCentroid(t *testing.T) {
+	assert.Equal(t, F{1, 1}, testSquare.Centroid())
+	c := testConcave.Centroid()
+	// the L shape is symmetric around the diagonal
+	assert.InDelta(t, c.X, c.Y, 0.0001)
+	assert.InDelta(t, (3*2.5+4*0.5)/7, c.X, 0.0001)
+	assert.Equal(t, F{1, 1}, Polygon{{0, 0}, {1, 1}, {2, 2}}.Centroid())
+
+	far := Polygon{{10000, 10000}, {10002, 10000}, {10002, 10002}, {10000, 10002}}
+	assert.Equal(t, F{10001, 10001}, far.Centroid())
+}
+
+func TestPolygonBounds(t *testing.T) {
+	assert.Equal(t, NewRect(0, 0, 4, 4), testConcave.Bounds())
+}
+
+func TestPolygonContainsPoint(t *testing.T) {
+	assert.True(t, testConcave.ContainsPoint(F{0.5, 0.5}))
+	assert.True(t, testConcave.ContainsPoint(F{3, 0.5}))
+	assert.True(t, testConcave.ContainsPoint(F{0.5, 3}))
+	assert.False(t, testConcave.ContainsPoint(F{3, 3}))
+	assert.False(t, testConcave.ContainsPoint(F{-1, 0.5}))
+
+	assert.False(t, Polygon{}.ContainsPoint(F{}))
+	assert.False(t, Polygon{{0, 0}, {2, 2}}.ContainsPoint(F{1, 1}))
+}
+
+func TestPolygonIsConvex(t *testing.T) {
+	assert.True(t, testSquare.IsConvex())
+	assert.False(t, testConcave.IsConvex())
+	assert.False(t, Polygon{{0, 0}, {1, 1}}.IsConvex())
+
+	collinear := Polygon{{0, 0}, {1, 0}, {2, 0}, {2, 2}, {0, 2}}
+	assert.True(t, collinear.IsConvex())
+
+	star := Polygon{{0, -10}, {6, 8}, {-9, -3}, {9, -3}, {-6, 8}}
+	assert.False(t, star.IsConvex())
+
+	_, ok := testConcave.Convex()
+	assert.False(t, ok)
+	c, ok := testSquare.Convex()
+	assert.True(t, ok)
+	assert.Equal(t, testSquare, c.AsPolygon())
+}
+
+func TestConvexPolygonContainsPoint(t *testing.T) {
+	for _, p := range []Polygon{testSquare, {{0, 2}, {2, 2}, {2, 0}, {0, 0}}} {
+		c, _ := p.Convex()
+		assert.True(t, c.ContainsPoint(F{1, 1}))
+		assert.True(t, c.ContainsPoint(F{2, 1}))
+		assert.False(t, c.ContainsPoint(F{3, 1}))
+		assert.False(t, c.ContainsPoint(F{1, -0.1}))
+	}
+
+	assert.False(t, ConvexPolygon{}.ContainsPoint(F{}))
+	assert.False(t, ConvexPolygon{{0, 0}, {2, 2}}.ContainsPoint(F{1, 1}))
+}
+
+func TestConvexPolygonEdgeNormal(t *testing.T) {
+	c := ConvexPolygon(testSquare)
+	assert.Equal(t, F{0, -1}, c.EdgeNormal(0))
+	assert.Equal(t, F{1, 0}, c.EdgeNormal(1))
+
+	reversed := ConvexPolygon{{0, 2}, {2, 2}, {2, 0}, {0, 0}}
+	assert.Equal(t, F{0, 1}, reversed.EdgeNormal(0))
+}
+
+func TestConvexHull(t *testing.T) {
+	points := []F{{1, 1}, {0, 0}, {2, 0}, {2, 2}, {0, 2}, {1, 0.5}, {1, 0}}
+	hull := ConvexHull(points, nil)
+	assert.Len(t, hull, 4)
+	assert.Equal(t, Clockwise, hull.AsPolygon().Winding())
+	assert.Equal(t, float32(4), hull.AsPolygon().Area())
+
+	prefix := ConvexPolygon{{9, 9}}
+	hull = ConvexHull([]F{{0, 0}, {1, 0}, {0, 1}}, prefix)
+	assert.Len(t, hull, 4)
+	assert.Equal(t, F{9, 9}, hull[0])
+}
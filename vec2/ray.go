@@ -0,0 +1,230 @@
+package vec2
+
+import "github.com/Lundis/go-gmath/fastmath"
+
+// Ray2 is a half-line starting at Origin and going in Direction.
+// Distances along the ray are measured in multiples of Direction, so with a normalized Direction they are world units.
+type Ray2 struct {
+	Origin, Direction F
+}
+
+// NewRay2 creates a ray with a normalized direction
+func NewRay2(origin, direction F) Ray2 {
+	return Ray2{Origin: origin, Direction: direction.Normalized()}
+}
+
+// NewRay2Between creates a ray from a towards b, and returns the distance between them for use as maxT
+func NewRay2Between(a, b F) (ray Ray2, distance float32) {
+	d := b.Sub(a)
+	distance = d.Magnitude()
+	if distance == 0 {
+		return Ray2{Origin: a}, 0
+	}
+	return Ray2{Origin: a, Direction: d.DivScalar(distance)}, distance
+}
+
+// At returns the point at distance t along the ray
+func (r Ray2) At(t float32) F {
+	return r.Origin.Add(r.Direction.MulScalar(t))
+}
+
+// RayHit describes where a ray hit a shape
+type RayHit struct {
+	Point F
+	// Normal is the normalized surface normal at Point, facing the ray
+	Normal F
+	// T is the distance along the ray
+	T float32
+}
+
+// RayCaster is a shape that rays can be cast against
+type RayCaster interface {
+	CastRay(r Ray2, maxT float32) (RayHit, bool)
+}
+
+// Segment is the line segment between A and B
+type Segment struct {
+	A, B F
+}
+
+func (s Segment) CastRay(r Ray2, maxT float32) (RayHit, bool) {
+	return r.CastSegment(s.A, s.B, maxT)
+}
+
+func (c Circle) CastRay(r Ray2, maxT float32) (RayHit, bool) {
+	return r.CastCircle(c, maxT)
+}
+
+func (rect Rect) CastRay(r Ray2, maxT float32) (RayHit, bool) {
+	return r.CastRect(rect, maxT)
+}
+
+func (p Polygon) CastRay(r Ray2, maxT float32) (RayHit, bool) {
+	return r.CastPolygon(p, maxT)
+}
+
+func (c ConvexPolygon) CastRay(r Ray2, maxT float32) (RayHit, bool) {
+	return r.CastConvexPolygon(c, maxT)
+}
+
+// The casts below only report hits with 0 <= T <= maxT. Use math.MaxFloat32 as maxT for an unlimited ray.
+// Circles, rectangles and polygons are solid: a ray that starts inside them hits at T = 0 with the normal facing the ray.
+
+// CastSegment casts the ray against the line segment a-b. Rays parallel to the segment never hit it.
+func (r Ray2) CastSegment(a, b F, maxT float32) (RayHit, bool) {
+	e := b.Sub(a)
+	denom := r.Direction.Cross(e)
+	if denom == 0 {
+		return RayHit{}, false
+	}
+	ao := a.Sub(r.Origin)
+	t := ao.Cross(e) / denom
+	s := ao.Cross(r.Direction) / denom
+	if t < 0 || t > maxT || s < 0 || s > 1 {
+		return RayHit{}, false
+	}
+	normal := e.Perpendicular().Normalized()
+	if normal.Dot(r.Direction) > 0 {
+		normal = normal.MulScalar(-1)
+	}
+	return RayHit{Point: r.At(t), Normal: normal, T: t}, true
+}
+
+func (r Ray2) CastCircle(c Circle, maxT float32) (RayHit, bool) {
+	m := r.Origin.Sub(c.Center)
+	cc := m.Dot(m) - c.Radius*c.Radius
+	if cc <= 0 {
+		return r.insideHit(), true
+	}
+	b := m.Dot(r.Direction)
+	if b >= 0 {
+		// pointing away from the circle
+		return RayHit{}, false
+	}
+	a := r.Direction.Dot(r.Direction)
+	discriminant := b*b - a*cc
+	if discriminant < 0 {
+		return RayHit{}, false
+	}
+	t := (-b - fastmath.Sqrt(discriminant)) / a
+	if t > maxT {
+		return RayHit{}, false
+	}
+	p := r.At(t)
+	return RayHit{Point: p, Normal: p.Sub(c.Center).Normalized(), T: t}, true
+}
+
+// CastRect casts the ray against rect using the slab method
+func (r Ray2) CastRect(rect Rect, maxT float32) (RayHit, bool) {
+	tMin, tMax := float32(0), maxT
+	var normal F
+	for axis := 0; axis < 2; axis++ {
+		o, d, low, high := r.Origin.X, r.Direction.X, rect.Min.X, rect.Max.X
+		if axis == 1 {
+			o, d, low, high = r.Origin.Y, r.Direction.Y, rect.Min.Y, rect.Max.Y
+		}
+		if d == 0 {
+			if o < low || o > high {
+				return RayHit{}, false
+			}
+			continue
+		}
+		t1, t2 := (low-o)/d, (high-o)/d
+		// the normal of the side the ray enters through faces against the ray
+		side := float32(-1)
+		if t1 > t2 {
+			t1, t2 = t2, t1
+			side = 1
+		}
+		if t1 > tMin {
+			tMin = t1
+			normal = F{}
+			if axis == 0 {
+				normal.X = side
+			} else {
+				normal.Y = side
+			}
+		}
+		tMax = min(tMax, t2)
+		if tMin > tMax {
+			return RayHit{}, false
+		}
+	}
+	if normal.IsZero() {
+		return r.insideHit(), true
+	}
+	return RayHit{Point: r.At(tMin), Normal: normal, T: tMin}, true
+}
+
+// CastPolygon casts the ray against a polygon that may be concave
+func (r Ray2) CastPolygon(p Polygon, maxT float32) (hit RayHit, ok bool) {
+	if len(p) < 3 {
+		return RayHit{}, false
+	}
+	if p.ContainsPoint(r.Origin) {
+		return r.insideHit(), true
+	}
+	for i := range p {
+		a, b := p.Edge(i)
+		if h, hitEdge := r.CastSegment(a, b, maxT); hitEdge {
+			hit, ok = h, true
+			maxT = h.T
+		}
+	}
+	return
+}
+
+// CastConvexPolygon casts the ray against c by clipping it against every edge
+func (r Ray2) CastConvexPolygon(c ConvexPolygon, maxT float32) (RayHit, bool) {
+	if len(c) < 3 {
+		return RayHit{}, false
+	}
+	sign := c.normalSign()
+	lower, upper := float32(0), maxT
+	entryEdge := -1
+	for i := range c {
+		n := c.edgeNormal(i, sign)
+		numerator := n.Dot(c[i].Sub(r.Origin))
+		denom := n.Dot(r.Direction)
+		if denom == 0 {
+			if numerator < 0 {
+				// parallel to and outside of this edge
+				return RayHit{}, false
+			}
+			continue
+		}
+		t := numerator / denom
+		if denom < 0 {
+			if t > lower {
+				lower = t
+				entryEdge = i
+			}
+		} else if t < upper {
+			upper = t
+		}
+		if upper < lower {
+			return RayHit{}, false
+		}
+	}
+	if entryEdge < 0 {
+		return r.insideHit(), true
+	}
+	return RayHit{Point: r.At(lower), Normal: c.edgeNormal(entryEdge, sign), T: lower}, true
+}
+
+func (r Ray2) insideHit() RayHit {
+	return RayHit{Point: r.Origin, Normal: r.Direction.Normalized().MulScalar(-1)}
+}
+
+// CastNearest casts r against all shapes and returns the nearest hit and the index of the shape that was hit.
+// index is -1 if nothing was hit within maxT.
+func CastNearest[S RayCaster](r Ray2, shapes []S, maxT float32) (hit RayHit, index int, ok bool) {
+	index = -1
+	for i, s := range shapes {
+		if h, hitShape := s.CastRay(r, maxT); hitShape {
+			hit, index, ok = h, i, true
+			maxT = h.T
+		}
+	}
+	return
+}
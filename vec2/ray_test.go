@@ -0,0 +1,158 @@
+package vec2
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const noLimit = math.MaxFloat32
+
+func TestNewRay2Between(t *testing.T) {
+	r, d := NewRay2Between(F{1, 1}, F{4, 5})
+	assert.Equal(t, float32(5), d)
+	assert.Equal(t, F{0.6, 0.8}, r.Direction)
+	assert.Equal(t, F{4, 5}, r.At(d))
+}
+
+func TestCastSegment(t *testing.T) {
+	r := NewRay2(F{0, 0}, F{1, 0})
+	hit, ok := r.CastSegment(F{3, -1}, F{3, 1}, noLimit)
+	assert.True(t, ok)
+	assert.Equal(t, float32(3), hit.T)
+	assert.Equal(t, F{3, 0}, hit.Point)
+	assert.Equal(t, F{-1, 0}, hit.Normal)
+
+	// the normal faces the ray regardless of the segment direction
+	hit, _ = r.CastSegment(F{3, 1}, F{3, -1}, noLimit)
+	assert.Equal(t, F{-1, 0}, hit.Normal)
+
+	_, ok = r.CastSegment(F{3, -1}, F{3, 1}, 2)
+	assert.False(t, ok)
+	_, ok = r.CastSegment(F{-3, -1}, F{-3, 1}, noLimit)
+	assert.False(t, ok)
+	_, ok = r.CastSegment(F{3, 1}, F{3, 2}, noLimit)
+	assert.False(t, ok)
+	_, ok = r.CastSegment(F{1, 0}, F{5, 0}, noLimit)
+	assert.False(t, ok)
+}
+
+func TestCastCircle(t *testing.T) {
+	c := Circle{F{5, 0}, 1}
+	hit, ok := NewRay2(F{0, 0}, F{1, 0}).CastCircle(c, noLimit)
+	assert.True(t, ok)
+	assert.Equal(t, float32(4), hit.T)
+	assert.Equal(t, F{-1, 0}, hit.Normal)
+
+	hit, ok = NewRay2(F{0, 0.6}, F{1, 0}).CastCircle(c, noLimit)
+	assert.True(t, ok)
+	assert.InDelta(t, 4.2, hit.T, 0.0001)
+	assert.InDelta(t, -0.8, hit.Normal.X, 0.0001)
+	assert.InDelta(t, 0.6, hit.Normal.Y, 0.0001)
+
+	_, ok = NewRay2(F{0, 0}, F{-1, 0}).CastCircle(c, noLimit)
+	assert.False(t, ok)
+	_, ok = NewRay2(F{0, 2}, F{1, 0}).CastCircle(c, noLimit)
+	assert.False(t, ok)
+	_, ok = NewRay2(F{0, 0}, F{1, 0}).CastCircle(c, 3)
+	assert.False(t, ok)
+
+	hit, ok = NewRay2(F{5, 0.5}, F{0, 1}).CastCircle(c, noLimit)
+	assert.True(t, ok)
+	assert.Equal(t, RayHit{Point: F{5, 0.5}, Normal: F{0, -1}}, hit)
+}
+
+func TestCastRect(t *testing.T) {
+	rect := NewRect(2, 2, 2, 2)
+	hit, ok := NewRay2(F{0, 3}, F{1, 0}).CastRect(rect, noLimit)
+	assert.True(t, ok)
+	assert.Equal(t, RayHit{Point: F{2, 3}, Normal: F{-1, 0}, T: 2}, hit)
+
+	hit, ok = NewRay2(F{3, 10}, F{0, -1}).CastRect(rect, noLimit)
+	assert.True(t, ok)
+	assert.Equal(t, RayHit{Point: F{3, 4}, Normal: F{0, 1}, T: 6}, hit)
+
+	hit, ok = NewRay2(F{0, 0}, F{1, 1}).CastRect(rect, noLimit)
+	assert.True(t, ok)
+	assert.InDelta(t, 2, hit.Point.X, 0.0001)
+	assert.InDelta(t, 2, hit.Point.Y, 0.0001)
+
+	_, ok = NewRay2(F{0, 0}, F{1, 0}).CastRect(rect, noLimit)
+	assert.False(t, ok)
+	_, ok = NewRay2(F{0, 3}, F{-1, 0}).CastRect(rect, noLimit)
+	assert.False(t, ok)
+	_, ok = NewRay2(F{0, 3}, F{1, 0}).CastRect(rect, 1)
+	assert.False(t, ok)
+
+	hit, ok = NewRay2(F{3, 3}, F{1, 0}).CastRect(rect, noLimit)
+	assert.True(t, ok)
+	assert.Equal(t, float32(0), hit.T)
+}
+
+func TestCastPolygon(t *testing.T) {
+	// ray along y = 2.5 passes the gap of the L shape before hitting its vertical bar
+	hit, ok := NewRay2(F{10, 2.5}, F{-1, 0}).CastPolygon(testConcave, noLimit)
+	assert.True(t, ok)
+	assert.Equal(t, RayHit{Point: F{1, 2.5}, Normal: F{1, 0}, T: 9}, hit)
+
+	hit, ok = NewRay2(F{10, 0.5}, F{-1, 0}).CastPolygon(testConcave, noLimit)
+	assert.True(t, ok)
+	assert.Equal(t, float32(6), hit.T)
+
+	_, ok = NewRay2(F{10, 5}, F{-1, 0}).CastPolygon(testConcave, noLimit)
+	assert.False(t, ok)
+
+	hit, ok = NewRay2(F{0.5, 0.5}, F{1, 0}).CastPolygon(testConcave, noLimit)
+	assert.True(t, ok)
+	assert.Equal(t, float32(0), hit.T)
+}
+
+func TestCastConvexPolygon(t *testing.T) {
+	for _, c := range []ConvexPolygon{box(2, 2, 2, 2), {{2, 4}, {4, 4}, {4, 2}, {2, 2}}} {
+		hit, ok := NewRay2(F{0, 3}, F{1, 0}).CastConvexPolygon(c, noLimit)
+		assert.True(t, ok)
+		assert.Equal(t, RayHit{Point: F{2, 3}, Normal: F{-1, 0}, T: 2}, hit)
+
+		_, ok = NewRay2(F{0, 0}, F{1, 0}).CastConvexPolygon(c, noLimit)
+		assert.False(t, ok)
+		_, ok = NewRay2(F{0, 3}, F{1, 0}).CastConvexPolygon(c, 1.5)
+		assert.False(t, ok)
+
+		hit, ok = NewRay2(F{3, 3}, F{0, 1}).CastConvexPolygon(c, noLimit)
+		assert.True(t, ok)
+		assert.Equal(t, float32(0), hit.T)
+	}
+
+	triangle := ConvexPolygon{{0, 0}, {4, 4}, {0, 4}}
+	hit, ok := NewRay2(F{4, 1}, F{-1, 0}).CastConvexPolygon(triangle, noLimit)
+	assert.True(t, ok)
+	assert.InDelta(t, 3, hit.T, 0.0001)
+	assert.InDelta(t, 0.7071, hit.Normal.X, 0.0001)
+	assert.InDelta(t, -0.7071, hit.Normal.Y, 0.0001)
+}
+
+func TestCastNearest(t *testing.T) {
+	r := NewRay2(F{0, 0}, F{1, 0})
+	circles := []Circle{{F{10, 0}, 1}, {F{5, 0}, 1}, {F{5, 5}, 1}, {F{7, 0}, 1}}
+	hit, index, ok := CastNearest(r, circles, noLimit)
+	assert.True(t, ok)
+	assert.Equal(t, 1, index)
+	assert.Equal(t, float32(4), hit.T)
+
+	_, index, ok = CastNearest(r, circles, 3)
+	assert.False(t, ok)
+	assert.Equal(t, -1, index)
+
+	shapes := []RayCaster{NewRect(8, -1, 1, 2), Segment{F{6, -1}, F{6, 1}}, circles[0]}
+	hit, index, ok = CastNearest(r, shapes, noLimit)
+	assert.True(t, ok)
+	assert.Equal(t, 1, index)
+	assert.Equal(t, float32(6), hit.T)
+
+	allocs := testing.AllocsPerRun(10, func() {
+		CastNearest(r, circles, noLimit)
+		CastNearest(r, shapes, noLimit)
+	})
+	assert.Equal(t, float64(0), allocs)
+}
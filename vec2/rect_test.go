@@ -0,0 +1,138 @@
+package vec2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRect(t *testing.T) {
+	r := NewRect(1, 2, 3, 4)
+	assert.Equal(t, F{1, 2}, r.Min)
+	assert.Equal(t, F{4, 6}, r.Max)
+	assert.Equal(t, float32(3), r.Width())
+	assert.Equal(t, float32(4), r.Height())
+	assert.Equal(t, F{3, 4}, r.Size())
+	assert.Equal(t, float32(12), r.Area())
+
+	assert.True(t, NewRectFromPoints(F{4, 2}, F{1, 6}).Equals(r))
+	assert.True(t, NewRectCentered(F{2.5, 4}, F{3, 4}).Equals(r))
+	assert.True(t, Rect{Min: F{4, 6}, Max: F{1, 2}}.Canonical().Equals(r))
+}
+
+func TestRectIsEmpty(t *testing.T) {
+	assert.False(t, NewRect(0, 0, 1, 1).IsEmpty())
+	assert.True(t, NewRect(0, 0, 0, 1).IsEmpty())
+	assert.True(t, NewRect(0, 0, 1, -1).IsEmpty())
+}
+
+func TestRectContains(t *testing.T) {
+	r := NewRect(0, 0, 10, 5)
+	assert.True(t, r.Contains(F{5, 2}))
+	assert.True(t, r.Contains(F{0, 0}))
+	assert.True(t, r.Contains(F{10, 5}))
+	assert.False(t, r.Contains(F{10.1, 2}))
+	assert.False(t, r.Contains(F{5, -0.1}))
+
+	assert.True(t, r.ContainsRect(NewRect(1, 1, 2, 2)))
+	assert.True(t, r.ContainsRect(r))
+	assert.False(t, r.ContainsRect(NewRect(9, 1, 2, 2)))
+}
+
+func TestRectIntersects(t *testing.T) {
+	a := NewRect(0, 0, 10, 10)
+	assert.True(t, a.Intersects(NewRect(5, 5, 10, 10)))
+	assert.True(t, a.Intersects(NewRect(2, 2, 1, 1)))
+	assert.False(t, a.Intersects(NewRect(10, 0, 5, 5)))
+	assert.False(t, a.Intersects(NewRect(-5, 20, 5, 5)))
+}
+
+func TestRectIntersection(t *testing.T) {
+	a := NewRect(0, 0, 10, 10)
+	res, ok := a.Intersection(NewRect(5, -5, 10, 10))
+	assert.True(t, ok)
+	assert.True(t, res.Equals(NewRect(5, 0, 5, 5)))
+
+	_, ok = a.Intersection(NewRect(10, 0, 5, 5))
+	assert.False(t, ok)
+}
+
+func TestRectUnion(t *testing.T) {
+	a := NewRect(0, 0, 1, 1)
+	b := NewRect(5, -2, 1, 1)
+	assert.True(t, a.Union(b).Equals(NewRectFromPoints(F{0, -2}, F{6, 1})))
+}
+
+func TestRectExpand(t *testing.T) {
+	r := NewRect(0, 0, 2, 2)
+	assert.True(t, r.Expand(1).Equals(NewRect(-1, -1, 4, 4)))
+	assert.True(t, r.ExpandXY(1, 0).Equals(NewRect(-1, 0, 4, 2)))
+	assert.True(t, r.ExpandToInclude(F{5, -1}).Equals(NewRect(0, -1, 5, 3)))
+	assert.True(t, r.Translate(F{1, 2}).Equals(NewRect(1, 2, 2, 2)))
+}
+
+func TestRectCenter(t *testing.T) {
+	assert.Equal(t, F{2, 3}, NewRect(1, 1, 2, 4).Center())
+	assert.Equal(t, I{1, 2}, NewRectI(0, 0, 3, 5).Center())
+}
+
+func TestRectCorners(t *testing.T) {
+	c := NewRect(0, 0, 2, 1).Corners()
+	assert.Equal(t, [4]F{{0, 0}, {2, 0}, {2, 1}, {0, 1}}, c)
+}
+
+func TestRectClampPoint(t *testing.T) {
+	r := NewRect(0, 0, 2, 2)
+	assert.Equal(t, F{2, 0}, r.ClampPoint(F{5, -3}))
+	assert.Equal(t, F{1, 1}, r.ClampPoint(F{1, 1}))
+}
+
+func TestRectSplit(t *testing.T) {
+	r := NewRect(0, 0, 10, 4)
+	left, right := r.SplitX(3)
+	assert.True(t, left.Equals(NewRect(0, 0, 3, 4)))
+	assert.True(t, right.Equals(NewRect(3, 0, 7, 4)))
+
+	top, bottom := r.SplitY(100)
+	assert.True(t, top.Equals(r))
+	assert.True(t, bottom.IsEmpty())
+
+	q := r.Quadrants()
+	assert.True(t, q[0].Equals(NewRect(0, 0, 5, 2)))
+	assert.True(t, q[1].Equals(NewRect(5, 0, 5, 2)))
+	assert.True(t, q[2].Equals(NewRect(5, 2, 5, 2)))
+	assert.True(t, q[3].Equals(NewRect(0, 2, 5, 2)))
+}
+
+func TestRectSubdivide(t *testing.T) {
+	cells := NewRect(0, 0, 9, 4).Subdivide(3, 2, nil)
+	assert.Len(t, cells, 6)
+	assert.True(t, cells[0].Equals(NewRect(0, 0, 3, 2)))
+	assert.True(t, cells[5].Equals(NewRect(6, 2, 3, 2)))
+
+	cellsI := NewRectI(0, 0, 10, 3).Subdivide(3, 1, nil)
+	assert.Len(t, cellsI, 3)
+	var total int32
+	for _, c := range cellsI {
+		total += c.Area()
+	}
+	assert.Equal(t, int32(30), total)
+	assert.Equal(t, int32(10), cellsI[2].Max.X)
+}
+
+func TestRectConversions(t *testing.T) {
+	r := NewRect(1.5, 2, 3, 4)
+	assert.Equal(t, NewRectD(1.5, 2, 3, 4), r.AsDouble())
+	assert.Equal(t, NewRectI(1, 2, 3, 4), r.AsInt())
+	assert.Equal(t, r, r.AsDouble().AsFloat())
+	assert.Equal(t, NewRect(1, 2, 3, 4), NewRectI(1, 2, 3, 4).AsFloat())
+}
+
+func TestRectIIntersection(t *testing.T) {
+	a := NewRectI(0, 0, 4, 4)
+	res, ok := a.Intersection(NewRectI(2, 2, 4, 4))
+	assert.True(t, ok)
+	assert.Equal(t, NewRectI(2, 2, 2, 2), res)
+	assert.Equal(t, NewRectI(-1, -1, 6, 6), a.Expand(1))
+	assert.True(t, a.Contains(I{4, 4}))
+}
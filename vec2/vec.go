@@ -0,0 +1,338 @@
+package vec2
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/Lundis/go-gmath/fastmath"
+)
+
+// Number is the set of element types a Vec can be made of
+type Number interface {
+	float32 | float64 | int32
+}
+
+// Float is the set of types that operations with fractional results, such as Magnitude, give
+type Float interface {
+	float32 | float64
+}
+
+// Vec is the generic 2D vector that F, D and I are built on. T is the element type, and R is the type of
+// fractional results: Magnitude, the distances and the angles are an R, and Normalized and Rotate give a Vec[R, R].
+// This makes F and D work entirely in their own precision, while I gives float64 results like a D would.
+type Vec[T Number, R Float] struct {
+	X, Y T
+}
+
+func (v Vec[T, R]) AsFloat() F {
+	return F{X: float32(v.X), Y: float32(v.Y)}
+}
+
+func (v Vec[T, R]) AsDouble() D {
+	return D{X: float64(v.X), Y: float64(v.Y)}
+}
+
+// asFloats converts v to the type of its fractional results
+func (v Vec[T, R]) asFloats() Vec[R, R] {
+	return Vec[R, R]{X: R(v.X), Y: R(v.Y)}
+}
+
+func (v Vec[T, R]) AsInt() I {
+	return I{X: int32(v.X), Y: int32(v.Y)}
+}
+
+func (v Vec[T, R]) Equals(other Vec[T, R]) bool {
+	return v.X == other.X && v.Y == other.Y
+}
+
+func (v Vec[T, R]) IsZero() bool {
+	return v.X == 0 && v.Y == 0
+}
+
+func (v Vec[T, R]) String() string {
+	return "(" + formatComponent(v.X) + ", " + formatComponent(v.Y) + ")"
+}
+
+func (v Vec[T, R]) Add(other Vec[T, R]) Vec[T, R] {
+	v.X += other.X
+	v.Y += other.Y
+	return v
+}
+
+func (v Vec[T, R]) AddScalar(scalar T) Vec[T, R] {
+	return v.AddScalars(scalar, scalar)
+}
+
+func (v Vec[T, R]) AddScalars(x, y T) Vec[T, R] {
+	v.X += x
+	v.Y += y
+	return v
+}
+
+func (v Vec[T, R]) Sub(other Vec[T, R]) Vec[T, R] {
+	v.X -= other.X
+	v.Y -= other.Y
+	return v
+}
+
+func (v Vec[T, R]) SubScalar(scalar T) Vec[T, R] {
+	return v.SubScalars(scalar, scalar)
+}
+
+func (v Vec[T, R]) SubScalars(x, y T) Vec[T, R] {
+	v.X -= x
+	v.Y -= y
+	return v
+}
+
+func (v Vec[T, R]) Mul(other Vec[T, R]) Vec[T, R] {
+	v.X *= other.X
+	v.Y *= other.Y
+	return v
+}
+
+func (v Vec[T, R]) MulScalar(scalar T) Vec[T, R] {
+	return v.MulScalars(scalar, scalar)
+}
+
+func (v Vec[T, R]) MulScalars(x, y T) Vec[T, R] {
+	v.X *= x
+	v.Y *= y
+	return v
+}
+
+func (v Vec[T, R]) Div(other Vec[T, R]) Vec[T, R] {
+	v.X /= other.X
+	v.Y /= other.Y
+	return v
+}
+
+func (v Vec[T, R]) DivScalar(scalar T) Vec[T, R] {
+	return v.DivScalars(scalar, scalar)
+}
+
+func (v Vec[T, R]) DivScalars(x, y T) Vec[T, R] {
+	v.X /= x
+	v.Y /= y
+	return v
+}
+
+func (v Vec[T, R]) Magnitude() R {
+	return R(math.Sqrt(float64(v.X*v.X + v.Y*v.Y)))
+}
+
+func (v Vec[T, R]) DistanceTo(v2 Vec[T, R]) R {
+	return v.Sub(v2).Magnitude()
+}
+
+func (v Vec[T, R]) DistanceToLine(a, b Vec[T, R]) R {
+	ab := b.Sub(a)
+	ap := v.Sub(a)
+
+	cross := ab.Cross(ap)
+
+	if cross < 0 {
+		cross = -cross
+	}
+
+	return R(cross) / ab.Magnitude()
+}
+
+// SideOfLine calculates which side of the line A->B the point P lies on. Check the sign of the response.
+func (v Vec[T, R]) SideOfLine(a, b Vec[T, R]) T {
+	ab := b.Sub(a)
+	ap := v.Sub(a)
+
+	return ab.Cross(ap)
+}
+
+func (v Vec[T, R]) DistanceToSquared(v2 Vec[T, R]) T {
+	diff := v.Sub(v2)
+	return diff.X*diff.X + diff.Y*diff.Y
+}
+
+func (v Vec[T, R]) Normalized() Vec[R, R] {
+	f := v.asFloats()
+	m := f.Magnitude()
+
+	if m > 0.0 {
+		return f.DivScalar(m)
+	} else {
+		return f
+	}
+}
+
+func (v Vec[T, R]) Angle() R {
+	return atan2(R(v.Y), R(v.X))
+}
+
+// AngleBetweenLines calculates the angle between two lines starting at origo
+// returns values in the range [-Pi, Pi].
+func (v Vec[T, R]) AngleBetweenLines(v2 Vec[T, R]) R {
+	pi := R(halfTurn)
+	angle := v2.Angle() - v.Angle()
+	if angle > pi {
+		angle -= 2 * pi
+	} else if angle <= -pi {
+		angle += 2 * pi
+	}
+	return angle
+}
+
+// AngleTo returns the angle of the line v->v2
+func (v Vec[T, R]) AngleTo(v2 Vec[T, R]) R {
+	return v2.Sub(v).Angle()
+}
+
+func (v Vec[T, R]) Abs() Vec[T, R] {
+	v.X = T(math.Abs(float64(v.X)))
+	v.Y = T(math.Abs(float64(v.Y)))
+	return v
+}
+
+func (v Vec[T, R]) Clamp(low, high Vec[T, R]) Vec[T, R] {
+	return low.Max(v.Min(high))
+}
+
+func (v Vec[T, R]) Min(v2 Vec[T, R]) Vec[T, R] {
+	v.X = min(v.X, v2.X)
+	v.Y = min(v.Y, v2.Y)
+	return v
+}
+
+func (v Vec[T, R]) Max(v2 Vec[T, R]) Vec[T, R] {
+	v.X = max(v.X, v2.X)
+	v.Y = max(v.Y, v2.Y)
+	return v
+}
+
+// MinMax returns the component-wise minimum and maximum of v and other
+func (v Vec[T, R]) MinMax(other Vec[T, R]) (min_, max_ Vec[T, R]) {
+	return v.Min(other), v.Max(other)
+}
+
+func (v Vec[T, R]) Round() Vec[T, R] {
+	v.X = T(math.Round(float64(v.X)))
+	v.Y = T(math.Round(float64(v.Y)))
+	return v
+}
+
+// Floor rounds towards zero
+func (v Vec[T, R]) Floor() Vec[T, R] {
+	v.X = T(int(v.X))
+	v.Y = T(int(v.Y))
+	return v
+}
+
+func (v Vec[T, R]) Ceil() Vec[T, R] {
+	v.X = T(math.Ceil(float64(v.X)))
+	v.Y = T(math.Ceil(float64(v.Y)))
+	return v
+}
+
+func (v Vec[T, R]) Swap() Vec[T, R] {
+	v.X, v.Y = v.Y, v.X
+	return v
+}
+
+func (v Vec[T, R]) Perpendicular() Vec[T, R] {
+	v.X, v.Y = v.Y, -v.X
+	return v
+}
+
+func (v Vec[T, R]) WithX(value T) Vec[T, R] {
+	v.X = value
+	return v
+}
+
+func (v Vec[T, R]) WithY(value T) Vec[T, R] {
+	v.Y = value
+	return v
+}
+
+func (v Vec[T, R]) NegatedY() Vec[T, R] {
+	v.Y = -v.Y
+	return v
+}
+
+func (v Vec[T, R]) Components() (x, y T) {
+	return v.X, v.Y
+}
+
+// Area returns X * Y, for vectors that represent a size
+func (v Vec[T, R]) Area() T {
+	return v.X * v.Y
+}
+
+// Index returns the index of v in a row-major grid of the given width
+func (v Vec[T, R]) Index(width T) T {
+	return width*v.Y + v.X
+}
+
+func (v Vec[T, R]) Rotate(angle R) Vec[R, R] {
+	if isFloat32[R]() {
+		cos32, sin32 := fastmath.CosSin(float32(angle))
+		cos, sin := R(cos32), R(sin32)
+		x, y := R(v.X), R(v.Y)
+		return Vec[R, R]{
+			X: x*cos + y*-sin,
+			Y: x*sin + y*cos,
+		}
+	}
+	cos, sin := fastmath.CosSinD(float64(angle))
+	x, y := float64(v.X), float64(v.Y)
+	return Vec[R, R]{
+		X: R(x*cos + y*-sin),
+		Y: R(x*sin + y*cos),
+	}
+}
+
+func (v Vec[T, R]) IsBetweenInclusive(left, right Vec[T, R]) bool {
+	return left.X <= v.X && v.X <= right.X &&
+		left.Y <= v.Y && v.Y <= right.Y
+}
+
+func (v Vec[T, R]) Cross(other Vec[T, R]) T {
+	return v.X*other.Y - v.Y*other.X
+}
+
+func (v Vec[T, R]) Dot(other Vec[T, R]) T {
+	return v.X*other.X + v.Y*other.Y
+}
+
+func (v Vec[T, R]) Reflect(other Vec[T, R]) Vec[T, R] {
+	factor := -2 * v.Dot(other)
+	return Vec[T, R]{
+		X: factor*v.X + other.X,
+		Y: factor*v.Y + other.Y,
+	}
+}
+
+// halfTurn is a variable so that it can be converted to any Number at runtime
+var halfTurn = math.Pi
+
+// The helpers below pick the float32 fastmath implementations for float32,
+// so that F behaves exactly like it did before it became generic.
+
+func formatComponent[T Number](x T) string {
+	if x == T(int(x)) {
+		return strconv.Itoa(int(x))
+	}
+	if isFloat32[T]() {
+		return strconv.FormatFloat(float64(x), 'f', 4, 32)
+	}
+	return strconv.FormatFloat(float64(x), 'f', 4, 64)
+}
+
+func atan2[T Number](y, x T) T {
+	if isFloat32[T]() {
+		return T(fastmath.Atan2(float32(y), float32(x)))
+	}
+	return T(fastmath.Atan2D(float64(y), float64(x)))
+}
+
+func isFloat32[T Number]() bool {
+	var zero T
+	_, ok := any(zero).(float32)
+	return ok
+}
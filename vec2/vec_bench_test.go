@@ -0,0 +1,68 @@
+package vec2
+
+import "testing"
+
+var benchF F
+var benchD D
+var benchI I
+var benchScalar float32
+
+func BenchmarkFAdd(b *testing.B) {
+	v := F{1, 2}
+	for i := 0; i < b.N; i++ {
+		v = v.Add(F{0.5, 0.25})
+	}
+	benchF = v
+}
+
+func BenchmarkFNormalized(b *testing.B) {
+	v := F{1, 2}
+	for i := 0; i < b.N; i++ {
+		v = v.AddScalar(1).Normalized()
+	}
+	benchF = v
+}
+
+func BenchmarkFRotate(b *testing.B) {
+	v := F{1, 2}
+	for i := 0; i < b.N; i++ {
+		v = v.Rotate(0.1)
+	}
+	benchF = v
+}
+
+func BenchmarkFAngle(b *testing.B) {
+	v := F{1, 2}
+	var sum float32
+	for i := 0; i < b.N; i++ {
+		sum += v.Angle()
+		v.X += 0.001
+	}
+	benchScalar = sum
+}
+
+func BenchmarkFDistanceToLine(b *testing.B) {
+	v := F{1, 2}
+	var sum float32
+	for i := 0; i < b.N; i++ {
+		sum += v.DistanceToLine(F{0, 0}, F{3, 4})
+		v.X += 0.001
+	}
+	benchScalar = sum
+}
+
+func BenchmarkDRotate(b *testing.B) {
+	v := D{1, 2}
+	for i := 0; i < b.N; i++ {
+		v = v.Rotate(0.1)
+	}
+	benchD = v
+}
+
+func BenchmarkIAdd(b *testing.B) {
+	v := I{1, 2}
+	for i := 0; i < b.N; i++ {
+		v = v.Add(I{1, 3})
+	}
+	benchI = v
+}
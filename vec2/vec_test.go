@@ -0,0 +1,81 @@
+package vec2
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIArithmetic(t *testing.T) {
+	a := I{6, -4}
+	b := I{2, 3}
+	assert.Equal(t, I{8, -1}, a.Add(b))
+	assert.Equal(t, I{4, -7}, a.Sub(b))
+	assert.Equal(t, I{12, -12}, a.Mul(b))
+	assert.Equal(t, I{3, -1}, a.Div(b))
+	assert.Equal(t, I{12, -8}, a.MulScalar(2))
+	assert.Equal(t, I{3, -2}, a.DivScalar(2))
+	assert.Equal(t, I{5, -5}, a.SubScalar(1))
+	assert.Equal(t, int32(0), a.Dot(b))
+	assert.Equal(t, int32(26), a.Cross(b))
+}
+
+func TestIMagnitude(t *testing.T) {
+	assert.Equal(t, 5.0, I{3, 4}.Magnitude())
+	assert.Equal(t, math.Sqrt2, I{1, 1}.Magnitude())
+	assert.Equal(t, 5.0, I{1, 1}.DistanceTo(I{4, 5}))
+	assert.Equal(t, int32(25), I{1, 1}.DistanceToSquared(I{4, 5}))
+	assert.InDelta(t, 2*math.Sqrt2, I{0, 0}.DistanceToLine(I{4, 0}, I{0, 4}), 1e-9)
+	assert.Equal(t, D{0, 1}, I{0, 7}.Normalized())
+	assert.Equal(t, D{0.6, 0.8}, I{3, 4}.Normalized())
+	assert.Equal(t, D{2, 2}.Angle(), I{2, 2}.Angle())
+}
+
+func TestIString(t *testing.T) {
+	assert.Equal(t, "(3, -6)", I{3, -6}.String())
+}
+
+func TestIRotate(t *testing.T) {
+	r := I{10, 0}.Rotate(3)
+	expected := D{10, 0}.Rotate(3)
+	assert.InDelta(t, expected.X, r.X, 1e-9)
+	assert.InDelta(t, expected.Y, r.Y, 1e-9)
+}
+
+func TestIMinMax(t *testing.T) {
+	low, high := I{1, 7}.MinMax(I{4, 2})
+	assert.Equal(t, I{1, 2}, low)
+	assert.Equal(t, I{4, 7}, high)
+	assert.Equal(t, I{1, 7}, I{-5, 10}.Clamp(low, high))
+	assert.Equal(t, I{5, 10}, I{-5, -10}.Abs())
+}
+
+func TestIIndex(t *testing.T) {
+	assert.Equal(t, int32(23), I{3, 2}.Index(10))
+	assert.Equal(t, int32(6), I{3, 2}.Area())
+}
+
+func TestNewRandomI(t *testing.T) {
+	for i := int32(1); i < 100; i++ {
+		a := NewRandomI(-1, i)
+		assert.True(t, -1 <= a.X && a.X < i)
+		assert.True(t, -1 <= a.Y && a.Y < i)
+	}
+}
+
+func TestConversions(t *testing.T) {
+	assert.Equal(t, D{1.5, -2}, F{1.5, -2}.AsDouble())
+	assert.Equal(t, I{1, -2}, F{1.5, -2.5}.AsInt())
+	assert.Equal(t, F{1, -2}, I{1, -2}.AsFloat())
+	assert.Equal(t, F{1.5, -2}, D{1.5, -2}.AsFloat())
+	assert.Equal(t, F{1.5, -2}, F{1.5, -2}.AsFloat())
+}
+
+func TestDOperations(t *testing.T) {
+	a := D{3, 4}
+	assert.Equal(t, float64(5), a.Magnitude())
+	assert.Equal(t, "(3, 4.2500)", D{3, 4.25}.String())
+	assert.InDelta(t, math.Pi/2, D{1, 0}.AngleBetweenLines(D{0, -1}), 0.01)
+	assert.Equal(t, D{-4, 3}.Perpendicular(), D{3, 4})
+}
@@ -0,0 +1,30 @@
+package vec3
+
+import "encoding/json"
+
+/**
+ * Custom JSON marshal/unmarshal to represent Quat as [x, y, z, w] instead of {"X": x, "Y": y, "Z": z, "W": w}
+ */
+
+func (q Quat) MarshalJSON() ([]byte, error) {
+	return json.Marshal([4]float32{q.X, q.Y, q.Z, q.W})
+}
+
+func (q *Quat) UnmarshalJSON(data []byte) error {
+	var tmp [4]float32
+	if err := json.Unmarshal(data, &tmp); err != nil {
+		type Alias Quat
+		var alias Alias
+		// fallback to default unmarshalling
+		if err := json.Unmarshal(data, &alias); err != nil {
+			return err
+		}
+		*q = Quat(alias)
+		return nil
+	}
+	q.X = tmp[0]
+	q.Y = tmp[1]
+	q.Z = tmp[2]
+	q.W = tmp[3]
+	return nil
+}
@@ -0,0 +1,288 @@
+package vec3
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/Lundis/go-gmath/fastmath"
+)
+
+// Quat is a rotation quaternion. X, Y and Z are the vector part and W the scalar part.
+// Rotations follow the right-hand rule, so a rotation by angle around -Z matches F.Rotate(angle).
+// Note that the zero value is not a valid rotation, use NewQuatIdentity instead.
+type Quat struct {
+	X, Y, Z, W float32
+}
+
+func NewQuatIdentity() Quat {
+	return Quat{W: 1}
+}
+
+// NewQuatAxisAngle creates a rotation of angle radians around axis, which doesn't need to be normalized
+func NewQuatAxisAngle(axis F, angle float32) Quat {
+	axis = axis.Normalized()
+	s, c := math.Sincos(float64(angle) / 2)
+	return Quat{
+		X: axis.X * float32(s),
+		Y: axis.Y * float32(s),
+		Z: axis.Z * float32(s),
+		W: float32(c),
+	}
+}
+
+// NewQuatEuler creates a rotation that first rotates x radians around the X axis, then y around Y and finally z around Z
+func NewQuatEuler(x, y, z float32) Quat {
+	sx, cx := math.Sincos(float64(x) / 2)
+	sy, cy := math.Sincos(float64(y) / 2)
+	sz, cz := math.Sincos(float64(z) / 2)
+	return Quat{
+		X: float32(sx*cy*cz - cx*sy*sz),
+		Y: float32(cx*sy*cz + sx*cy*sz),
+		Z: float32(cx*cy*sz - sx*sy*cz),
+		W: float32(cx*cy*cz + sx*sy*sz),
+	}
+}
+
+// NewQuatFromTo creates the shortest rotation that turns direction from into direction to
+func NewQuatFromTo(from, to F) Quat {
+	from = from.Normalized()
+	to = to.Normalized()
+	d := from.Dot(to)
+	if d >= 1 {
+		return NewQuatIdentity()
+	}
+	if d <= -1+1e-6 {
+		// opposite directions, rotate half a turn around any perpendicular axis
+		axis := F{X: 1}.Cross(from)
+		if axis.Dot(axis) < 1e-6 {
+			axis = F{Y: 1}.Cross(from)
+		}
+		return NewQuatAxisAngle(axis, math.Pi)
+	}
+	c := from.Cross(to)
+	return Quat{X: c.X, Y: c.Y, Z: c.Z, W: 1 + d}.Normalized()
+}
+
+// NewQuatLookRotation creates a rotation that turns the Z axis towards forward,
+// and the Y axis as close as possible towards up
+func NewQuatLookRotation(forward, up F) Quat {
+	z := forward.Normalized()
+	if z.IsZero() {
+		return NewQuatIdentity()
+	}
+	x := up.Cross(z)
+	if x.Dot(x) < 1e-12 {
+		// forward and up are parallel, any rotation around forward is fine
+		return NewQuatFromTo(F{Z: 1}, z)
+	}
+	x = x.Normalized()
+	y := z.Cross(x)
+	return NewQuatFromMatrix3([9]float32{
+		x.X, x.Y, x.Z,
+		y.X, y.Y, y.Z,
+		z.X, z.Y, z.Z,
+	})
+}
+
+// NewQuatFromMatrix3 extracts the rotation from a column-major 3x3 rotation matrix
+func NewQuatFromMatrix3(m [9]float32) Quat {
+	m00, m10, m20 := m[0], m[1], m[2]
+	m01, m11, m21 := m[3], m[4], m[5]
+	m02, m12, m22 := m[6], m[7], m[8]
+
+	var q Quat
+	trace := m00 + m11 + m22
+	if trace > 0 {
+		s := fastmath.Sqrt(trace+1) * 2
+		q.W = s / 4
+		q.X = (m21 - m12) / s
+		q.Y = (m02 - m20) / s
+		q.Z = (m10 - m01) / s
+	} else if m00 > m11 && m00 > m22 {
+		s := fastmath.Sqrt(1+m00-m11-m22) * 2
+		q.W = (m21 - m12) / s
+		q.X = s / 4
+		q.Y = (m01 + m10) / s
+		q.Z = (m02 + m20) / s
+	} else if m11 > m22 {
+		s := fastmath.Sqrt(1+m11-m00-m22) * 2
+		q.W = (m02 - m20) / s
+		q.X = (m01 + m10) / s
+		q.Y = s / 4
+		q.Z = (m12 + m21) / s
+	} else {
+		s := fastmath.Sqrt(1+m22-m00-m11) * 2
+		q.W = (m10 - m01) / s
+		q.X = (m02 + m20) / s
+		q.Y = (m12 + m21) / s
+		q.Z = s / 4
+	}
+	return q.Normalized()
+}
+
+// NewQuatFromMatrix4 extracts the rotation from the upper 3x3 part of a column-major 4x4 matrix without scaling
+func NewQuatFromMatrix4(m [16]float32) Quat {
+	return NewQuatFromMatrix3([9]float32{
+		m[0], m[1], m[2],
+		m[4], m[5], m[6],
+		m[8], m[9], m[10],
+	})
+}
+
+func (q Quat) Equals(other Quat) bool {
+	return q == other
+}
+
+func (q Quat) String() string {
+	return "(" + strconv.FormatFloat(float64(q.X), 'f', 4, 32) +
+		", " + strconv.FormatFloat(float64(q.Y), 'f', 4, 32) +
+		", " + strconv.FormatFloat(float64(q.Z), 'f', 4, 32) +
+		", " + strconv.FormatFloat(float64(q.W), 'f', 4, 32) + ")"
+}
+
+// Vector returns the vector part of q
+func (q Quat) Vector() F {
+	return F{X: q.X, Y: q.Y, Z: q.Z}
+}
+
+// Mul returns the rotation that first applies other and then q
+func (q Quat) Mul(other Quat) Quat {
+	return Quat{
+		X: q.W*other.X + q.X*other.W + q.Y*other.Z - q.Z*other.Y,
+		Y: q.W*other.Y - q.X*other.Z + q.Y*other.W + q.Z*other.X,
+		Z: q.W*other.Z + q.X*other.Y - q.Y*other.X + q.Z*other.W,
+		W: q.W*other.W - q.X*other.X - q.Y*other.Y - q.Z*other.Z,
+	}
+}
+
+func (q Quat) Dot(other Quat) float32 {
+	return q.X*other.X + q.Y*other.Y + q.Z*other.Z + q.W*other.W
+}
+
+func (q Quat) Length() float32 {
+	return fastmath.Sqrt(q.Dot(q))
+}
+
+func (q Quat) Normalized() Quat {
+	l := q.Length()
+	if l > 0 {
+		return Quat{X: q.X / l, Y: q.Y / l, Z: q.Z / l, W: q.W / l}
+	}
+	return q
+}
+
+func (q Quat) Conjugate() Quat {
+	return Quat{X: -q.X, Y: -q.Y, Z: -q.Z, W: q.W}
+}
+
+// Inverse returns the opposite rotation. For unit quaternions this is the same as Conjugate.
+func (q Quat) Inverse() Quat {
+	lengthSquared := q.Dot(q)
+	if lengthSquared == 0 {
+		return q
+	}
+	return Quat{X: -q.X / lengthSquared, Y: -q.Y / lengthSquared, Z: -q.Z / lengthSquared, W: q.W / lengthSquared}
+}
+
+// Rotate rotates v by q, which must be normalized
+func (q Quat) Rotate(v F) F {
+	// v + 2w(u x v) + 2u x (u x v), where u is the vector part
+	u := q.Vector()
+	t := u.Cross(v).MulScalar(2)
+	return v.Add(t.MulScalar(q.W)).Add(u.Cross(t))
+}
+
+// AxisAngle returns the rotation axis and the angle around it, in the range [0, 2*Pi]
+func (q Quat) AxisAngle() (axis F, angle float32) {
+	q = q.Normalized()
+	angle = 2 * float32(math.Acos(float64(fastmath.Clamp(q.W, -1, 1))))
+	s := fastmath.Sqrt(1 - q.W*q.W)
+	if s < 1e-6 {
+		return F{X: 1}, 0
+	}
+	return q.Vector().DivScalar(s), angle
+}
+
+// Euler returns the angles that NewQuatEuler needs to recreate q
+func (q Quat) Euler() (x, y, z float32) {
+	m := q.Matrix3()
+	m00, m10, m20 := m[0], m[1], m[2]
+	m01, m11, m21 := m[3], m[4], m[5]
+	m22 := m[8]
+
+	y = float32(math.Asin(float64(fastmath.Clamp(-m20, -1, 1))))
+	if m20 < 0.99999 && m20 > -0.99999 {
+		x = float32(math.Atan2(float64(m21), float64(m22)))
+		z = float32(math.Atan2(float64(m10), float64(m00)))
+	} else {
+		// gimbal lock, only the combination of x and z is defined
+		z = float32(math.Atan2(float64(-m01), float64(m11)))
+	}
+	return
+}
+
+// Angle returns the smallest angle between the rotations q and other
+func (q Quat) Angle(other Quat) float32 {
+	d := float64(q.Normalized().Dot(other.Normalized()))
+	return 2 * float32(math.Acos(min(math.Abs(d), 1)))
+}
+
+// Nlerp linearly interpolates between q and other along the shortest path and normalizes the result.
+// It's faster than Slerp, but doesn't rotate at constant speed.
+func (q Quat) Nlerp(other Quat, t float32) Quat {
+	if q.Dot(other) < 0 {
+		other = Quat{X: -other.X, Y: -other.Y, Z: -other.Z, W: -other.W}
+	}
+	return Quat{
+		X: q.X + (other.X-q.X)*t,
+		Y: q.Y + (other.Y-q.Y)*t,
+		Z: q.Z + (other.Z-q.Z)*t,
+		W: q.W + (other.W-q.W)*t,
+	}.Normalized()
+}
+
+// Slerp interpolates between q and other along the shortest path at constant angular speed
+func (q Quat) Slerp(other Quat, t float32) Quat {
+	cosTheta := q.Dot(other)
+	if cosTheta < 0 {
+		other = Quat{X: -other.X, Y: -other.Y, Z: -other.Z, W: -other.W}
+		cosTheta = -cosTheta
+	}
+	if cosTheta > 0.9995 {
+		// too close for a stable division by sin(theta)
+		return q.Nlerp(other, t)
+	}
+	theta := math.Acos(float64(cosTheta))
+	sinTheta := math.Sin(theta)
+	a := float32(math.Sin((1-float64(t))*theta) / sinTheta)
+	b := float32(math.Sin(float64(t)*theta) / sinTheta)
+	return Quat{
+		X: q.X*a + other.X*b,
+		Y: q.Y*a + other.Y*b,
+		Z: q.Z*a + other.Z*b,
+		W: q.W*a + other.W*b,
+	}
+}
+
+// Matrix3 returns the rotation as a column-major 3x3 matrix
+func (q Quat) Matrix3() [9]float32 {
+	xx, yy, zz := q.X*q.X, q.Y*q.Y, q.Z*q.Z
+	xy, xz, yz := q.X*q.Y, q.X*q.Z, q.Y*q.Z
+	wx, wy, wz := q.W*q.X, q.W*q.Y, q.W*q.Z
+	return [9]float32{
+		1 - 2*(yy+zz), 2 * (xy + wz), 2 * (xz - wy),
+		2 * (xy - wz), 1 - 2*(xx+zz), 2 * (yz + wx),
+		2 * (xz + wy), 2 * (yz - wx), 1 - 2*(xx+yy),
+	}
+}
+
+// Matrix4 returns the rotation as a column-major 4x4 matrix
+func (q Quat) Matrix4() [16]float32 {
+	m := q.Matrix3()
+	return [16]float32{
+		m[0], m[1], m[2], 0,
+		m[3], m[4], m[5], 0,
+		m[6], m[7], m[8], 0,
+		0, 0, 0, 1,
+	}
+}
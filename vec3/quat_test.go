@@ -0,0 +1,154 @@
+package vec3
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func assertFInDelta(t *testing.T, expected, actual F) {
+	t.Helper()
+	assert.InDelta(t, expected.X, actual.X, 0.001)
+	assert.InDelta(t, expected.Y, actual.Y, 0.001)
+	assert.InDelta(t, expected.Z, actual.Z, 0.001)
+}
+
+// assertSameRotation accounts for q and -q representing the same rotation
+func assertSameRotation(t *testing.T, expected, actual Quat) {
+	t.Helper()
+	assert.InDelta(t, 0, expected.Angle(actual), 0.002)
+}
+
+func TestQuatAxisAngle(t *testing.T) {
+	q := NewQuatAxisAngle(F{0, 0, 1}, math.Pi/2)
+	assertFInDelta(t, F{0, 1, 0}, q.Rotate(F{1, 0, 0}))
+
+	q = NewQuatAxisAngle(F{0, 5, 0}, math.Pi/2)
+	assertFInDelta(t, F{0, 0, -1}, q.Rotate(F{1, 0, 0}))
+
+	axis, angle := NewQuatAxisAngle(F{1, 2, 3}, 1.3).AxisAngle()
+	assertFInDelta(t, F{1, 2, 3}.Normalized(), axis)
+	assert.InDelta(t, 1.3, angle, 0.001)
+}
+
+func TestQuatMatchesRotate(t *testing.T) {
+	v := F{3, -2, 0}
+	q := NewQuatAxisAngle(F{0, 0, -1}, 0.8)
+	assertFInDelta(t, v.Rotate(0.8), q.Rotate(v))
+}
+
+func TestQuatIdentity(t *testing.T) {
+	q := NewQuatIdentity()
+	assert.Equal(t, F{1, 2, 3}, q.Rotate(F{1, 2, 3}))
+	assert.Equal(t, q, q.Mul(q))
+}
+
+func TestQuatMul(t *testing.T) {
+	a := NewQuatAxisAngle(F{1, 0, 0}, 0.5)
+	b := NewQuatAxisAngle(F{0, 1, 0}, 1.1)
+	v := F{1, 2, 3}
+	// b first, then a
+	assertFInDelta(t, a.Rotate(b.Rotate(v)), a.Mul(b).Rotate(v))
+}
+
+func TestQuatInverse(t *testing.T) {
+	q := NewQuatEuler(0.3, -1.2, 2)
+	v := F{1, 2, 3}
+	assertFInDelta(t, v, q.Inverse().Rotate(q.Rotate(v)))
+	assertFInDelta(t, v, q.Conjugate().Rotate(q.Rotate(v)))
+	assertSameRotation(t, NewQuatIdentity(), q.Mul(q.Inverse()))
+
+	scaled := Quat{X: q.X * 2, Y: q.Y * 2, Z: q.Z * 2, W: q.W * 2}
+	assertSameRotation(t, NewQuatIdentity(), scaled.Mul(scaled.Inverse()))
+}
+
+func TestQuatEuler(t *testing.T) {
+	x, y, z := float32(0.3), float32(-0.7), float32(2.1)
+	q := NewQuatEuler(x, y, z)
+	expected := NewQuatAxisAngle(F{0, 0, 1}, z).
+		Mul(NewQuatAxisAngle(F{0, 1, 0}, y)).
+		Mul(NewQuatAxisAngle(F{1, 0, 0}, x))
+	assertSameRotation(t, expected, q)
+
+	ex, ey, ez := q.Euler()
+	assert.InDelta(t, x, ex, 0.001)
+	assert.InDelta(t, y, ey, 0.001)
+	assert.InDelta(t, z, ez, 0.001)
+}
+
+func TestQuatEulerGimbalLock(t *testing.T) {
+	q := NewQuatEuler(0.4, math.Pi/2, 0.1)
+	ex, ey, ez := q.Euler()
+	assertSameRotation(t, q, NewQuatEuler(ex, ey, ez))
+}
+
+func TestQuatMatrix(t *testing.T) {
+	q := NewQuatEuler(0.3, -0.7, 2.1)
+	m := q.Matrix3()
+	v := F{1, 2, 3}
+	rotated := F{
+		X: m[0]*v.X + m[3]*v.Y + m[6]*v.Z,
+		Y: m[1]*v.X + m[4]*v.Y + m[7]*v.Z,
+		Z: m[2]*v.X + m[5]*v.Y + m[8]*v.Z,
+	}
+	assertFInDelta(t, q.Rotate(v), rotated)
+	assertSameRotation(t, q, NewQuatFromMatrix3(m))
+	assertSameRotation(t, q, NewQuatFromMatrix4(q.Matrix4()))
+
+	// exercise every branch of the matrix conversion
+	for _, r := range []Quat{
+		NewQuatAxisAngle(F{1, 0, 0}, 3),
+		NewQuatAxisAngle(F{0, 1, 0}, 3),
+		NewQuatAxisAngle(F{0, 0, 1}, 3),
+	} {
+		assertSameRotation(t, r, NewQuatFromMatrix3(r.Matrix3()))
+	}
+}
+
+func TestQuatSlerp(t *testing.T) {
+	a := NewQuatIdentity()
+	b := NewQuatAxisAngle(F{0, 1, 0}, 2)
+	assertSameRotation(t, a, a.Slerp(b, 0))
+	assertSameRotation(t, b, a.Slerp(b, 1))
+	assertSameRotation(t, NewQuatAxisAngle(F{0, 1, 0}, 0.5), a.Slerp(b, 0.25))
+
+	// shortest path
+	c := NewQuatAxisAngle(F{0, 1, 0}, -0.2)
+	negated := Quat{X: -c.X, Y: -c.Y, Z: -c.Z, W: -c.W}
+	assertSameRotation(t, NewQuatAxisAngle(F{0, 1, 0}, -0.1), a.Slerp(negated, 0.5))
+	assertSameRotation(t, NewQuatAxisAngle(F{0, 1, 0}, -0.1), a.Nlerp(negated, 0.5))
+}
+
+func TestQuatFromTo(t *testing.T) {
+	from := F{1, 2, 3}
+	to := F{-3, 0.5, 1}
+	assertFInDelta(t, to.Normalized(), NewQuatFromTo(from, to).Rotate(from.Normalized()))
+	assertFInDelta(t, F{-1, 0, 0}, NewQuatFromTo(F{1, 0, 0}, F{-1, 0, 0}).Rotate(F{1, 0, 0}))
+	assertSameRotation(t, NewQuatIdentity(), NewQuatFromTo(from, from))
+}
+
+func TestQuatLookRotation(t *testing.T) {
+	forward := F{1, 0, 1}.Normalized()
+	q := NewQuatLookRotation(forward, F{0, 1, 0})
+	assertFInDelta(t, forward, q.Rotate(F{0, 0, 1}))
+	assertFInDelta(t, F{0, 1, 0}, q.Rotate(F{0, 1, 0}))
+
+	q = NewQuatLookRotation(F{0, 2, 0}, F{0, 1, 0})
+	assertFInDelta(t, F{0, 1, 0}, q.Rotate(F{0, 0, 1}))
+}
+
+func TestQuatJSON(t *testing.T) {
+	q := Quat{1, 2, 3, 4}
+	data, err := json.Marshal(q)
+	assert.NoError(t, err)
+	assert.JSONEq(t, "[1,2,3,4]", string(data))
+
+	var res Quat
+	assert.NoError(t, json.Unmarshal(data, &res))
+	assert.Equal(t, q, res)
+	assert.NoError(t, json.Unmarshal([]byte(`{"X":1,"Y":2,"Z":3,"W":4}`), &res))
+	assert.Equal(t, q, res)
+	assert.Error(t, json.Unmarshal([]byte(`"q"`), &res))
+}